@@ -107,6 +107,8 @@ type AddRequest struct {
 	Tags          []string               `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
 	Groups        []string               `protobuf:"bytes,3,rep,name=groups,proto3" json:"groups,omitempty"`
 	Name          string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"` // optional unique name
+	Annotations   map[string]string      `protobuf:"bytes,5,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	TrackChildren bool                   `protobuf:"varint,6,opt,name=track_children,json=trackChildren,proto3" json:"track_children,omitempty"` // register descendants as child entries
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -169,6 +171,20 @@ func (x *AddRequest) GetName() string {
 	return ""
 }
 
+func (x *AddRequest) GetAnnotations() map[string]string {
+	if x != nil {
+		return x.Annotations
+	}
+	return nil
+}
+
+func (x *AddRequest) GetTrackChildren() bool {
+	if x != nil {
+		return x.TrackChildren
+	}
+	return false
+}
+
 type AddResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -214,16 +230,36 @@ func (x *AddResponse) GetId() uint64 {
 }
 
 type ListRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Ids           []uint64               `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
-	Pids          []int32                `protobuf:"varint,2,rep,packed,name=pids,proto3" json:"pids,omitempty"`
-	TagsAny       []string               `protobuf:"bytes,3,rep,name=tags_any,json=tagsAny,proto3" json:"tags_any,omitempty"`
-	TagsAll       []string               `protobuf:"bytes,4,rep,name=tags_all,json=tagsAll,proto3" json:"tags_all,omitempty"`
-	GroupsAny     []string               `protobuf:"bytes,5,rep,name=groups_any,json=groupsAny,proto3" json:"groups_any,omitempty"`
-	GroupsAll     []string               `protobuf:"bytes,6,rep,name=groups_all,json=groupsAll,proto3" json:"groups_all,omitempty"`
-	AliveOnly     bool                   `protobuf:"varint,7,opt,name=alive_only,json=aliveOnly,proto3" json:"alive_only,omitempty"`
-	TextSearch    string                 `protobuf:"bytes,8,opt,name=text_search,json=textSearch,proto3" json:"text_search,omitempty"`
-	Names         []string               `protobuf:"bytes,9,rep,name=names,proto3" json:"names,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Ids         []uint64               `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	Pids        []int32                `protobuf:"varint,2,rep,packed,name=pids,proto3" json:"pids,omitempty"`
+	TagsAny     []string               `protobuf:"bytes,3,rep,name=tags_any,json=tagsAny,proto3" json:"tags_any,omitempty"`
+	TagsAll     []string               `protobuf:"bytes,4,rep,name=tags_all,json=tagsAll,proto3" json:"tags_all,omitempty"`
+	GroupsAny   []string               `protobuf:"bytes,5,rep,name=groups_any,json=groupsAny,proto3" json:"groups_any,omitempty"`
+	GroupsAll   []string               `protobuf:"bytes,6,rep,name=groups_all,json=groupsAll,proto3" json:"groups_all,omitempty"`
+	AliveOnly   bool                   `protobuf:"varint,7,opt,name=alive_only,json=aliveOnly,proto3" json:"alive_only,omitempty"`
+	TextSearch  string                 `protobuf:"bytes,8,opt,name=text_search,json=textSearch,proto3" json:"text_search,omitempty"`
+	Names       []string               `protobuf:"bytes,9,rep,name=names,proto3" json:"names,omitempty"`
+	Annotations []*AnnotationSelector  `protobuf:"bytes,10,rep,name=annotations,proto3" json:"annotations,omitempty"` // all must match
+	Query       string                 `protobuf:"bytes,11,opt,name=query,proto3" json:"query,omitempty"`             // query expression, e.g. "tag:db and rss>500MB"; ANDed with the fields above
+	// Globs (*, ?, [...]) match the whole value; any glob in a list may match.
+	// Regexes use Go syntax and match anywhere unless anchored.
+	NameGlobs  []string `protobuf:"bytes,12,rep,name=name_globs,json=nameGlobs,proto3" json:"name_globs,omitempty"`
+	NameRegex  string   `protobuf:"bytes,13,opt,name=name_regex,json=nameRegex,proto3" json:"name_regex,omitempty"`
+	TagGlobs   []string `protobuf:"bytes,14,rep,name=tag_globs,json=tagGlobs,proto3" json:"tag_globs,omitempty"` // some tag must match
+	TagRegex   string   `protobuf:"bytes,15,opt,name=tag_regex,json=tagRegex,proto3" json:"tag_regex,omitempty"`
+	GroupGlobs []string `protobuf:"bytes,16,rep,name=group_globs,json=groupGlobs,proto3" json:"group_globs,omitempty"` // some group must match
+	GroupRegex string   `protobuf:"bytes,17,opt,name=group_regex,json=groupRegex,proto3" json:"group_regex,omitempty"`
+	CmdGlobs   []string `protobuf:"bytes,18,rep,name=cmd_globs,json=cmdGlobs,proto3" json:"cmd_globs,omitempty"`
+	CmdRegex   string   `protobuf:"bytes,19,opt,name=cmd_regex,json=cmdRegex,proto3" json:"cmd_regex,omitempty"`
+	// Ordering and paging; used by List only.
+	Sort      string `protobuf:"bytes,20,opt,name=sort,proto3" json:"sort,omitempty"` // id (default), pid, name, added_at, last_seen, cpu, rss
+	Desc      bool   `protobuf:"varint,21,opt,name=desc,proto3" json:"desc,omitempty"`
+	Limit     uint32 `protobuf:"varint,22,opt,name=limit,proto3" json:"limit,omitempty"`                         // 0 = no limit
+	PageToken string `protobuf:"bytes,23,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token of the previous response
+	// Proc fields to populate (e.g. "pid", "name", "metrics"); id is always
+	// set. Empty returns every field.
+	Fields        []string `protobuf:"bytes,24,rep,name=fields,proto3" json:"fields,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -321,6 +357,171 @@ func (x *ListRequest) GetNames() []string {
 	return nil
 }
 
+func (x *ListRequest) GetAnnotations() []*AnnotationSelector {
+	if x != nil {
+		return x.Annotations
+	}
+	return nil
+}
+
+func (x *ListRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *ListRequest) GetNameGlobs() []string {
+	if x != nil {
+		return x.NameGlobs
+	}
+	return nil
+}
+
+func (x *ListRequest) GetNameRegex() string {
+	if x != nil {
+		return x.NameRegex
+	}
+	return ""
+}
+
+func (x *ListRequest) GetTagGlobs() []string {
+	if x != nil {
+		return x.TagGlobs
+	}
+	return nil
+}
+
+func (x *ListRequest) GetTagRegex() string {
+	if x != nil {
+		return x.TagRegex
+	}
+	return ""
+}
+
+func (x *ListRequest) GetGroupGlobs() []string {
+	if x != nil {
+		return x.GroupGlobs
+	}
+	return nil
+}
+
+func (x *ListRequest) GetGroupRegex() string {
+	if x != nil {
+		return x.GroupRegex
+	}
+	return ""
+}
+
+func (x *ListRequest) GetCmdGlobs() []string {
+	if x != nil {
+		return x.CmdGlobs
+	}
+	return nil
+}
+
+func (x *ListRequest) GetCmdRegex() string {
+	if x != nil {
+		return x.CmdRegex
+	}
+	return ""
+}
+
+func (x *ListRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListRequest) GetDesc() bool {
+	if x != nil {
+		return x.Desc
+	}
+	return false
+}
+
+func (x *ListRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListRequest) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type AnnotationSelector struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Op            string                 `protobuf:"bytes,2,opt,name=op,proto3" json:"op,omitempty"` // "exists" (default), "equals", or "prefix"
+	Value         string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnnotationSelector) Reset() {
+	*x = AnnotationSelector{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnnotationSelector) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnnotationSelector) ProtoMessage() {}
+
+func (x *AnnotationSelector) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnnotationSelector.ProtoReflect.Descriptor instead.
+func (*AnnotationSelector) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AnnotationSelector) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *AnnotationSelector) GetOp() string {
+	if x != nil {
+		return x.Op
+	}
+	return ""
+}
+
+func (x *AnnotationSelector) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
 type Proc struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -332,14 +533,24 @@ type Proc struct {
 	Groups        []string               `protobuf:"bytes,7,rep,name=groups,proto3" json:"groups,omitempty"`
 	AddedAtUnix   int64                  `protobuf:"varint,8,opt,name=added_at_unix,json=addedAtUnix,proto3" json:"added_at_unix,omitempty"`
 	LastSeenUnix  int64                  `protobuf:"varint,9,opt,name=last_seen_unix,json=lastSeenUnix,proto3" json:"last_seen_unix,omitempty"`
-	Name          string                 `protobuf:"bytes,10,opt,name=name,proto3" json:"name,omitempty"` // Metrics will be added later (cpu%, rss, io)
+	Name          string                 `protobuf:"bytes,10,opt,name=name,proto3" json:"name,omitempty"`
+	Restart       *RestartPolicy         `protobuf:"bytes,11,opt,name=restart,proto3" json:"restart,omitempty"`    // set only for processes spawned by the daemon
+	Restarts      uint32                 `protobuf:"varint,12,opt,name=restarts,proto3" json:"restarts,omitempty"` // how many times the supervisor relaunched it
+	Metrics       *ProcMetrics           `protobuf:"bytes,13,opt,name=metrics,proto3" json:"metrics,omitempty"`    // latest /proc sample; unset until sampled or when dead
+	Exit          *ExitStatus            `protobuf:"bytes,14,opt,name=exit,proto3" json:"exit,omitempty"`          // how the process last terminated; unset if never seen to exit
+	Annotations   map[string]string      `protobuf:"bytes,15,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ParentId      uint64                 `protobuf:"varint,16,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`                // entry this one was registered under by track_children; 0 otherwise
+	TrackChildren bool                   `protobuf:"varint,17,opt,name=track_children,json=trackChildren,proto3" json:"track_children,omitempty"` // descendants are registered as child entries
+	Children      []*ProcNode            `protobuf:"bytes,18,rep,name=children,proto3" json:"children,omitempty"`                                 // process tree below pid as of the last liveness tick
+	Cgroup        *Cgroup                `protobuf:"bytes,19,opt,name=cgroup,proto3" json:"cgroup,omitempty"`                                     // set when the process runs in its own cgroup v2 leaf
+	Manifest      string                 `protobuf:"bytes,20,opt,name=manifest,proto3" json:"manifest,omitempty"`                                 // manifest that declared the entry; empty otherwise
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Proc) Reset() {
 	*x = Proc{}
-	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[5]
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -351,7 +562,7 @@ func (x *Proc) String() string {
 func (*Proc) ProtoMessage() {}
 
 func (x *Proc) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[5]
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -364,7 +575,7 @@ func (x *Proc) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Proc.ProtoReflect.Descriptor instead.
 func (*Proc) Descriptor() ([]byte, []int) {
-	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{5}
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *Proc) GetId() uint64 {
@@ -437,75 +648,102 @@ func (x *Proc) GetName() string {
 	return ""
 }
 
-type ListResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Procs         []*Proc                `protobuf:"bytes,1,rep,name=procs,proto3" json:"procs,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *Proc) GetRestart() *RestartPolicy {
+	if x != nil {
+		return x.Restart
+	}
+	return nil
 }
 
-func (x *ListResponse) Reset() {
-	*x = ListResponse{}
-	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[6]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *Proc) GetRestarts() uint32 {
+	if x != nil {
+		return x.Restarts
+	}
+	return 0
 }
 
-func (x *ListResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *Proc) GetMetrics() *ProcMetrics {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
 }
 
-func (*ListResponse) ProtoMessage() {}
+func (x *Proc) GetExit() *ExitStatus {
+	if x != nil {
+		return x.Exit
+	}
+	return nil
+}
 
-func (x *ListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[6]
+func (x *Proc) GetAnnotations() map[string]string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Annotations
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use ListResponse.ProtoReflect.Descriptor instead.
-func (*ListResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{6}
+func (x *Proc) GetParentId() uint64 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
 }
 
-func (x *ListResponse) GetProcs() []*Proc {
+func (x *Proc) GetTrackChildren() bool {
 	if x != nil {
-		return x.Procs
+		return x.TrackChildren
+	}
+	return false
+}
+
+func (x *Proc) GetChildren() []*ProcNode {
+	if x != nil {
+		return x.Children
 	}
 	return nil
 }
 
-type KillRequest struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Types that are valid to be assigned to Target:
-	//
-	//	*KillRequest_Id
-	//	*KillRequest_Pid
-	Target        isKillRequest_Target `protobuf_oneof:"target"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *Proc) GetCgroup() *Cgroup {
+	if x != nil {
+		return x.Cgroup
+	}
+	return nil
 }
 
-func (x *KillRequest) Reset() {
-	*x = KillRequest{}
+func (x *Proc) GetManifest() string {
+	if x != nil {
+		return x.Manifest
+	}
+	return ""
+}
+
+// Cgroup is the cgroup v2 leaf of a spawned process. Zero limits leave a
+// resource unlimited.
+type Cgroup struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Path           string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`                                              // leaf directory; chosen by the daemon
+	MemoryMaxBytes uint64                 `protobuf:"varint,2,opt,name=memory_max_bytes,json=memoryMaxBytes,proto3" json:"memory_max_bytes,omitempty"` // memory.max
+	CpuMax         float64                `protobuf:"fixed64,3,opt,name=cpu_max,json=cpuMax,proto3" json:"cpu_max,omitempty"`                          // cpu.max in CPUs, e.g. 0.5
+	PidsMax        uint32                 `protobuf:"varint,4,opt,name=pids_max,json=pidsMax,proto3" json:"pids_max,omitempty"`                        // pids.max
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Cgroup) Reset() {
+	*x = Cgroup{}
 	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *KillRequest) String() string {
+func (x *Cgroup) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*KillRequest) ProtoMessage() {}
+func (*Cgroup) ProtoMessage() {}
 
-func (x *KillRequest) ProtoReflect() protoreflect.Message {
+func (x *Cgroup) ProtoReflect() protoreflect.Message {
 	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -517,72 +755,64 @@ func (x *KillRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use KillRequest.ProtoReflect.Descriptor instead.
-func (*KillRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Cgroup.ProtoReflect.Descriptor instead.
+func (*Cgroup) Descriptor() ([]byte, []int) {
 	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *KillRequest) GetTarget() isKillRequest_Target {
+func (x *Cgroup) GetPath() string {
 	if x != nil {
-		return x.Target
+		return x.Path
 	}
-	return nil
+	return ""
 }
 
-func (x *KillRequest) GetId() uint64 {
+func (x *Cgroup) GetMemoryMaxBytes() uint64 {
 	if x != nil {
-		if x, ok := x.Target.(*KillRequest_Id); ok {
-			return x.Id
-		}
+		return x.MemoryMaxBytes
 	}
 	return 0
 }
 
-func (x *KillRequest) GetPid() int32 {
+func (x *Cgroup) GetCpuMax() float64 {
 	if x != nil {
-		if x, ok := x.Target.(*KillRequest_Pid); ok {
-			return x.Pid
-		}
+		return x.CpuMax
 	}
 	return 0
 }
 
-type isKillRequest_Target interface {
-	isKillRequest_Target()
-}
-
-type KillRequest_Id struct {
-	Id uint64 `protobuf:"varint,1,opt,name=id,proto3,oneof"`
-}
-
-type KillRequest_Pid struct {
-	Pid int32 `protobuf:"varint,2,opt,name=pid,proto3,oneof"`
+func (x *Cgroup) GetPidsMax() uint32 {
+	if x != nil {
+		return x.PidsMax
+	}
+	return 0
 }
 
-func (*KillRequest_Id) isKillRequest_Target() {}
-
-func (*KillRequest_Pid) isKillRequest_Target() {}
-
-type KillResponse struct {
+// ProcNode is a descendant process discovered through /proc ppid links.
+type ProcNode struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pid           int32                  `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
+	Cmd           string                 `protobuf:"bytes,2,opt,name=cmd,proto3" json:"cmd,omitempty"`
+	Id            uint64                 `protobuf:"varint,3,opt,name=id,proto3" json:"id,omitempty"` // registry entry for pid; 0 if it is not tracked
+	Children      []*ProcNode            `protobuf:"bytes,4,rep,name=children,proto3" json:"children,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *KillResponse) Reset() {
-	*x = KillResponse{}
+func (x *ProcNode) Reset() {
+	*x = ProcNode{}
 	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *KillResponse) String() string {
+func (x *ProcNode) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*KillResponse) ProtoMessage() {}
+func (*ProcNode) ProtoMessage() {}
 
-func (x *KillResponse) ProtoReflect() protoreflect.Message {
+func (x *ProcNode) ProtoReflect() protoreflect.Message {
 	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -594,33 +824,2111 @@ func (x *KillResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use KillResponse.ProtoReflect.Descriptor instead.
-func (*KillResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ProcNode.ProtoReflect.Descriptor instead.
+func (*ProcNode) Descriptor() ([]byte, []int) {
 	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{8}
 }
 
-type RmRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ProcNode) GetPid() int32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
 }
 
-func (x *RmRequest) Reset() {
-	*x = RmRequest{}
+func (x *ProcNode) GetCmd() string {
+	if x != nil {
+		return x.Cmd
+	}
+	return ""
+}
+
+func (x *ProcNode) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ProcNode) GetChildren() []*ProcNode {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+// ExitStatus records how a process terminated.
+type ExitStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AtUnix        int64                  `protobuf:"varint,1,opt,name=at_unix,json=atUnix,proto3" json:"at_unix,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"` // "exited", "signaled", or "unknown" (died while unobserved)
+	Code          int32                  `protobuf:"varint,3,opt,name=code,proto3" json:"code,omitempty"`    // exit code; -1 unless reason is "exited"
+	Signal        string                 `protobuf:"bytes,4,opt,name=signal,proto3" json:"signal,omitempty"` // terminating signal name, e.g. "SIGSEGV"
+	CoreDumped    bool                   `protobuf:"varint,5,opt,name=core_dumped,json=coreDumped,proto3" json:"core_dumped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExitStatus) Reset() {
+	*x = ExitStatus{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExitStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExitStatus) ProtoMessage() {}
+
+func (x *ExitStatus) ProtoReflect() protoreflect.Message {
 	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExitStatus.ProtoReflect.Descriptor instead.
+func (*ExitStatus) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ExitStatus) GetAtUnix() int64 {
+	if x != nil {
+		return x.AtUnix
+	}
+	return 0
+}
+
+func (x *ExitStatus) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ExitStatus) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *ExitStatus) GetSignal() string {
+	if x != nil {
+		return x.Signal
+	}
+	return ""
+}
+
+func (x *ExitStatus) GetCoreDumped() bool {
+	if x != nil {
+		return x.CoreDumped
+	}
+	return false
+}
+
+// ProcMetrics is resource usage sampled by the daemon on every liveness tick.
+type ProcMetrics struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CpuPercent    float64                `protobuf:"fixed64,1,opt,name=cpu_percent,json=cpuPercent,proto3" json:"cpu_percent,omitempty"` // since the previous tick; 100 = one core
+	RssBytes      uint64                 `protobuf:"varint,2,opt,name=rss_bytes,json=rssBytes,proto3" json:"rss_bytes,omitempty"`
+	VmsBytes      uint64                 `protobuf:"varint,3,opt,name=vms_bytes,json=vmsBytes,proto3" json:"vms_bytes,omitempty"`
+	Threads       uint32                 `protobuf:"varint,4,opt,name=threads,proto3" json:"threads,omitempty"`
+	OpenFds       uint32                 `protobuf:"varint,5,opt,name=open_fds,json=openFds,proto3" json:"open_fds,omitempty"`
+	ReadBytes     uint64                 `protobuf:"varint,6,opt,name=read_bytes,json=readBytes,proto3" json:"read_bytes,omitempty"` // storage I/O counters (may be 0 without permission)
+	WriteBytes    uint64                 `protobuf:"varint,7,opt,name=write_bytes,json=writeBytes,proto3" json:"write_bytes,omitempty"`
+	SampledAtUnix int64                  `protobuf:"varint,8,opt,name=sampled_at_unix,json=sampledAtUnix,proto3" json:"sampled_at_unix,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProcMetrics) Reset() {
+	*x = ProcMetrics{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcMetrics) ProtoMessage() {}
+
+func (x *ProcMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcMetrics.ProtoReflect.Descriptor instead.
+func (*ProcMetrics) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ProcMetrics) GetCpuPercent() float64 {
+	if x != nil {
+		return x.CpuPercent
+	}
+	return 0
+}
+
+func (x *ProcMetrics) GetRssBytes() uint64 {
+	if x != nil {
+		return x.RssBytes
+	}
+	return 0
+}
+
+func (x *ProcMetrics) GetVmsBytes() uint64 {
+	if x != nil {
+		return x.VmsBytes
+	}
+	return 0
+}
+
+func (x *ProcMetrics) GetThreads() uint32 {
+	if x != nil {
+		return x.Threads
+	}
+	return 0
+}
+
+func (x *ProcMetrics) GetOpenFds() uint32 {
+	if x != nil {
+		return x.OpenFds
+	}
+	return 0
+}
+
+func (x *ProcMetrics) GetReadBytes() uint64 {
+	if x != nil {
+		return x.ReadBytes
+	}
+	return 0
+}
+
+func (x *ProcMetrics) GetWriteBytes() uint64 {
+	if x != nil {
+		return x.WriteBytes
+	}
+	return 0
+}
+
+func (x *ProcMetrics) GetSampledAtUnix() int64 {
+	if x != nil {
+		return x.SampledAtUnix
+	}
+	return 0
+}
+
+type ListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Procs         []*Proc                `protobuf:"bytes,1,rep,name=procs,proto3" json:"procs,omitempty"`
+	Total         uint32                 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`                                       // entries matching the filter across all pages
+	NextPageToken string                 `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListResponse) Reset() {
+	*x = ListResponse{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListResponse) ProtoMessage() {}
+
+func (x *ListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListResponse.ProtoReflect.Descriptor instead.
+func (*ListResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListResponse) GetProcs() []*Proc {
+	if x != nil {
+		return x.Procs
+	}
+	return nil
+}
+
+func (x *ListResponse) GetTotal() uint32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type KillRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Target:
+	//
+	//	*KillRequest_Id
+	//	*KillRequest_Pid
+	Target        isKillRequest_Target `protobuf_oneof:"target"`
+	Signal        string               `protobuf:"bytes,3,opt,name=signal,proto3" json:"signal,omitempty"`                   // e.g. "SIGTERM", "HUP", "9"; empty sends SIGTERM
+	GraceMs       int64                `protobuf:"varint,4,opt,name=grace_ms,json=graceMs,proto3" json:"grace_ms,omitempty"` // wait this long for the target to exit; 0 returns right away
+	Escalate      bool                 `protobuf:"varint,5,opt,name=escalate,proto3" json:"escalate,omitempty"`              // send SIGKILL if the target outlives the grace period
+	NoGroup       bool                 `protobuf:"varint,6,opt,name=no_group,json=noGroup,proto3" json:"no_group,omitempty"` // signal only the process, not its whole process group or cgroup
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KillRequest) Reset() {
+	*x = KillRequest{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KillRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KillRequest) ProtoMessage() {}
+
+func (x *KillRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KillRequest.ProtoReflect.Descriptor instead.
+func (*KillRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *KillRequest) GetTarget() isKillRequest_Target {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *KillRequest) GetId() uint64 {
+	if x != nil {
+		if x, ok := x.Target.(*KillRequest_Id); ok {
+			return x.Id
+		}
+	}
+	return 0
+}
+
+func (x *KillRequest) GetPid() int32 {
+	if x != nil {
+		if x, ok := x.Target.(*KillRequest_Pid); ok {
+			return x.Pid
+		}
+	}
+	return 0
+}
+
+func (x *KillRequest) GetSignal() string {
+	if x != nil {
+		return x.Signal
+	}
+	return ""
+}
+
+func (x *KillRequest) GetGraceMs() int64 {
+	if x != nil {
+		return x.GraceMs
+	}
+	return 0
+}
+
+func (x *KillRequest) GetEscalate() bool {
+	if x != nil {
+		return x.Escalate
+	}
+	return false
+}
+
+func (x *KillRequest) GetNoGroup() bool {
+	if x != nil {
+		return x.NoGroup
+	}
+	return false
+}
+
+type isKillRequest_Target interface {
+	isKillRequest_Target()
+}
+
+type KillRequest_Id struct {
+	Id uint64 `protobuf:"varint,1,opt,name=id,proto3,oneof"`
+}
+
+type KillRequest_Pid struct {
+	Pid int32 `protobuf:"varint,2,opt,name=pid,proto3,oneof"`
+}
+
+func (*KillRequest_Id) isKillRequest_Target() {}
+
+func (*KillRequest_Pid) isKillRequest_Target() {}
+
+type KillResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Outcome       string                 `protobuf:"bytes,1,opt,name=outcome,proto3" json:"outcome,omitempty"` // "exited", "escalated" (needed SIGKILL), or "running"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KillResponse) Reset() {
+	*x = KillResponse{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KillResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KillResponse) ProtoMessage() {}
+
+func (x *KillResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KillResponse.ProtoReflect.Descriptor instead.
+func (*KillResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *KillResponse) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
+
+// SignalRequest delivers an arbitrary signal without changing the registry.
+type SignalRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Signal        string                 `protobuf:"bytes,2,opt,name=signal,proto3" json:"signal,omitempty"`                                  // e.g. "SIGHUP", "usr1", "19"; required
+	ProcessGroup  bool                   `protobuf:"varint,3,opt,name=process_group,json=processGroup,proto3" json:"process_group,omitempty"` // signal the entry's process group instead of its PID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignalRequest) Reset() {
+	*x = SignalRequest{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignalRequest) ProtoMessage() {}
+
+func (x *SignalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignalRequest.ProtoReflect.Descriptor instead.
+func (*SignalRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SignalRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SignalRequest) GetSignal() string {
+	if x != nil {
+		return x.Signal
+	}
+	return ""
+}
+
+func (x *SignalRequest) GetProcessGroup() bool {
+	if x != nil {
+		return x.ProcessGroup
+	}
+	return false
+}
+
+type SignalResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignalResponse) Reset() {
+	*x = SignalResponse{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignalResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignalResponse) ProtoMessage() {}
+
+func (x *SignalResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignalResponse.ProtoReflect.Descriptor instead.
+func (*SignalResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{15}
+}
+
+type RmRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"` // recorded in the history: "rm" (default) or "kill"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RmRequest) Reset() {
+	*x = RmRequest{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RmRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RmRequest) ProtoMessage() {}
+
+func (x *RmRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RmRequest.ProtoReflect.Descriptor instead.
+func (*RmRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RmRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RmRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type RmResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RmResponse) Reset() {
+	*x = RmResponse{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RmResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RmResponse) ProtoMessage() {}
+
+func (x *RmResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RmResponse.ProtoReflect.Descriptor instead.
+func (*RmResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{17}
+}
+
+// UpdateLabelsRequest adds and removes tags/groups on every matching entry.
+// Removals are applied before additions.
+type UpdateLabelsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *ListRequest           `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"` // an empty filter matches every entry
+	AddTags       []string               `protobuf:"bytes,2,rep,name=add_tags,json=addTags,proto3" json:"add_tags,omitempty"`
+	RemoveTags    []string               `protobuf:"bytes,3,rep,name=remove_tags,json=removeTags,proto3" json:"remove_tags,omitempty"`
+	AddGroups     []string               `protobuf:"bytes,4,rep,name=add_groups,json=addGroups,proto3" json:"add_groups,omitempty"`
+	RemoveGroups  []string               `protobuf:"bytes,5,rep,name=remove_groups,json=removeGroups,proto3" json:"remove_groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateLabelsRequest) Reset() {
+	*x = UpdateLabelsRequest{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateLabelsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateLabelsRequest) ProtoMessage() {}
+
+func (x *UpdateLabelsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateLabelsRequest.ProtoReflect.Descriptor instead.
+func (*UpdateLabelsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *UpdateLabelsRequest) GetFilter() *ListRequest {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *UpdateLabelsRequest) GetAddTags() []string {
+	if x != nil {
+		return x.AddTags
+	}
+	return nil
+}
+
+func (x *UpdateLabelsRequest) GetRemoveTags() []string {
+	if x != nil {
+		return x.RemoveTags
+	}
+	return nil
+}
+
+func (x *UpdateLabelsRequest) GetAddGroups() []string {
+	if x != nil {
+		return x.AddGroups
+	}
+	return nil
+}
+
+func (x *UpdateLabelsRequest) GetRemoveGroups() []string {
+	if x != nil {
+		return x.RemoveGroups
+	}
+	return nil
+}
+
+type UpdateLabelsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Procs         []*Proc                `protobuf:"bytes,1,rep,name=procs,proto3" json:"procs,omitempty"`      // matching entries after the update
+	Updated       uint32                 `protobuf:"varint,2,opt,name=updated,proto3" json:"updated,omitempty"` // how many of them actually changed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateLabelsResponse) Reset() {
+	*x = UpdateLabelsResponse{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateLabelsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateLabelsResponse) ProtoMessage() {}
+
+func (x *UpdateLabelsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateLabelsResponse.ProtoReflect.Descriptor instead.
+func (*UpdateLabelsResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *UpdateLabelsResponse) GetProcs() []*Proc {
+	if x != nil {
+		return x.Procs
+	}
+	return nil
+}
+
+func (x *UpdateLabelsResponse) GetUpdated() uint32 {
+	if x != nil {
+		return x.Updated
+	}
+	return 0
+}
+
+type SetNameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"` // new unique name; empty clears it
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetNameRequest) Reset() {
+	*x = SetNameRequest{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetNameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNameRequest) ProtoMessage() {}
+
+func (x *SetNameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNameRequest.ProtoReflect.Descriptor instead.
+func (*SetNameRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SetNameRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SetNameRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type SetNameResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Proc          *Proc                  `protobuf:"bytes,1,opt,name=proc,proto3" json:"proc,omitempty"` // entry after the change
+	PreviousName  string                 `protobuf:"bytes,2,opt,name=previous_name,json=previousName,proto3" json:"previous_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetNameResponse) Reset() {
+	*x = SetNameResponse{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetNameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNameResponse) ProtoMessage() {}
+
+func (x *SetNameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNameResponse.ProtoReflect.Descriptor instead.
+func (*SetNameResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SetNameResponse) GetProc() *Proc {
+	if x != nil {
+		return x.Proc
+	}
+	return nil
+}
+
+func (x *SetNameResponse) GetPreviousName() string {
+	if x != nil {
+		return x.PreviousName
+	}
+	return ""
+}
+
+type RenameTagRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameTagRequest) Reset() {
+	*x = RenameTagRequest{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameTagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameTagRequest) ProtoMessage() {}
+
+func (x *RenameTagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameTagRequest.ProtoReflect.Descriptor instead.
+func (*RenameTagRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RenameTagRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *RenameTagRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+type RenameTagResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Updated       uint32                 `protobuf:"varint,1,opt,name=updated,proto3" json:"updated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameTagResponse) Reset() {
+	*x = RenameTagResponse{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameTagResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameTagResponse) ProtoMessage() {}
+
+func (x *RenameTagResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameTagResponse.ProtoReflect.Descriptor instead.
+func (*RenameTagResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *RenameTagResponse) GetUpdated() uint32 {
+	if x != nil {
+		return x.Updated
+	}
+	return 0
+}
+
+type RenameGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameGroupRequest) Reset() {
+	*x = RenameGroupRequest{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameGroupRequest) ProtoMessage() {}
+
+func (x *RenameGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameGroupRequest.ProtoReflect.Descriptor instead.
+func (*RenameGroupRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RenameGroupRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *RenameGroupRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+type RenameGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Updated       uint32                 `protobuf:"varint,1,opt,name=updated,proto3" json:"updated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameGroupResponse) Reset() {
+	*x = RenameGroupResponse{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameGroupResponse) ProtoMessage() {}
+
+func (x *RenameGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameGroupResponse.ProtoReflect.Descriptor instead.
+func (*RenameGroupResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *RenameGroupResponse) GetUpdated() uint32 {
+	if x != nil {
+		return x.Updated
+	}
+	return 0
+}
+
+type ResetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetRequest) Reset() {
+	*x = ResetRequest{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetRequest) ProtoMessage() {}
+
+func (x *ResetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetRequest.ProtoReflect.Descriptor instead.
+func (*ResetRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{26}
+}
+
+type ResetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetResponse) Reset() {
+	*x = ResetResponse{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetResponse) ProtoMessage() {}
+
+func (x *ResetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetResponse.ProtoReflect.Descriptor instead.
+func (*ResetResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{27}
+}
+
+// RestartPolicy controls how the daemon supervises spawned processes.
+type RestartPolicy struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mode          string                 `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`                                        // "never" (default), "on-failure", "always"
+	MaxRestarts   uint32                 `protobuf:"varint,2,opt,name=max_restarts,json=maxRestarts,proto3" json:"max_restarts,omitempty"`      // 0 = unlimited
+	WindowMs      int64                  `protobuf:"varint,3,opt,name=window_ms,json=windowMs,proto3" json:"window_ms,omitempty"`               // max_restarts is counted within this sliding window
+	BackoffMs     int64                  `protobuf:"varint,4,opt,name=backoff_ms,json=backoffMs,proto3" json:"backoff_ms,omitempty"`            // initial delay before a restart, doubled each time
+	MaxBackoffMs  int64                  `protobuf:"varint,5,opt,name=max_backoff_ms,json=maxBackoffMs,proto3" json:"max_backoff_ms,omitempty"` // upper bound for the restart delay
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestartPolicy) Reset() {
+	*x = RestartPolicy{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestartPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestartPolicy) ProtoMessage() {}
+
+func (x *RestartPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestartPolicy.ProtoReflect.Descriptor instead.
+func (*RestartPolicy) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RestartPolicy) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *RestartPolicy) GetMaxRestarts() uint32 {
+	if x != nil {
+		return x.MaxRestarts
+	}
+	return 0
+}
+
+func (x *RestartPolicy) GetWindowMs() int64 {
+	if x != nil {
+		return x.WindowMs
+	}
+	return 0
+}
+
+func (x *RestartPolicy) GetBackoffMs() int64 {
+	if x != nil {
+		return x.BackoffMs
+	}
+	return 0
+}
+
+func (x *RestartPolicy) GetMaxBackoffMs() int64 {
+	if x != nil {
+		return x.MaxBackoffMs
+	}
+	return 0
+}
+
+type SpawnRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Argv          []string               `protobuf:"bytes,1,rep,name=argv,proto3" json:"argv,omitempty"` // argv[0] is resolved via the daemon PATH
+	Env           []string               `protobuf:"bytes,2,rep,name=env,proto3" json:"env,omitempty"`   // KEY=VALUE; empty inherits the daemon environment
+	Cwd           string                 `protobuf:"bytes,3,opt,name=cwd,proto3" json:"cwd,omitempty"`
+	Tags          []string               `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+	Groups        []string               `protobuf:"bytes,5,rep,name=groups,proto3" json:"groups,omitempty"`
+	Name          string                 `protobuf:"bytes,6,opt,name=name,proto3" json:"name,omitempty"`
+	Restart       *RestartPolicy         `protobuf:"bytes,7,opt,name=restart,proto3" json:"restart,omitempty"`
+	Annotations   map[string]string      `protobuf:"bytes,8,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	TrackChildren bool                   `protobuf:"varint,9,opt,name=track_children,json=trackChildren,proto3" json:"track_children,omitempty"`
+	Cgroup        *Cgroup                `protobuf:"bytes,10,opt,name=cgroup,proto3" json:"cgroup,omitempty"` // run in a dedicated cgroup v2 leaf with these limits; path is ignored
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpawnRequest) Reset() {
+	*x = SpawnRequest{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpawnRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpawnRequest) ProtoMessage() {}
+
+func (x *SpawnRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpawnRequest.ProtoReflect.Descriptor instead.
+func (*SpawnRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SpawnRequest) GetArgv() []string {
+	if x != nil {
+		return x.Argv
+	}
+	return nil
+}
+
+func (x *SpawnRequest) GetEnv() []string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *SpawnRequest) GetCwd() string {
+	if x != nil {
+		return x.Cwd
+	}
+	return ""
+}
+
+func (x *SpawnRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *SpawnRequest) GetGroups() []string {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+func (x *SpawnRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SpawnRequest) GetRestart() *RestartPolicy {
+	if x != nil {
+		return x.Restart
+	}
+	return nil
+}
+
+func (x *SpawnRequest) GetAnnotations() map[string]string {
+	if x != nil {
+		return x.Annotations
+	}
+	return nil
+}
+
+func (x *SpawnRequest) GetTrackChildren() bool {
+	if x != nil {
+		return x.TrackChildren
+	}
+	return false
+}
+
+func (x *SpawnRequest) GetCgroup() *Cgroup {
+	if x != nil {
+		return x.Cgroup
+	}
+	return nil
+}
+
+type SpawnResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Pid           int32                  `protobuf:"varint,2,opt,name=pid,proto3" json:"pid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpawnResponse) Reset() {
+	*x = SpawnResponse{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpawnResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpawnResponse) ProtoMessage() {}
+
+func (x *SpawnResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpawnResponse.ProtoReflect.Descriptor instead.
+func (*SpawnResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *SpawnResponse) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SpawnResponse) GetPid() int32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+type LogsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Stderr        bool                   `protobuf:"varint,2,opt,name=stderr,proto3" json:"stderr,omitempty"` // stdout when false
+	Follow        bool                   `protobuf:"varint,3,opt,name=follow,proto3" json:"follow,omitempty"` // keep streaming appended output until the client cancels
+	Tail          int32                  `protobuf:"varint,4,opt,name=tail,proto3" json:"tail,omitempty"`     // only the last N lines; <= 0 sends the whole live file
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogsRequest) Reset() {
+	*x = LogsRequest{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogsRequest) ProtoMessage() {}
+
+func (x *LogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogsRequest.ProtoReflect.Descriptor instead.
+func (*LogsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *LogsRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *LogsRequest) GetStderr() bool {
+	if x != nil {
+		return x.Stderr
+	}
+	return false
+}
+
+func (x *LogsRequest) GetFollow() bool {
+	if x != nil {
+		return x.Follow
+	}
+	return false
+}
+
+func (x *LogsRequest) GetTail() int32 {
+	if x != nil {
+		return x.Tail
+	}
+	return 0
+}
+
+type LogChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogChunk) Reset() {
+	*x = LogChunk{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogChunk) ProtoMessage() {}
+
+func (x *LogChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogChunk.ProtoReflect.Descriptor instead.
+func (*LogChunk) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *LogChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type WatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *ListRequest           `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"` // only events for matching entries; empty matches all
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *WatchRequest) GetFilter() *ListRequest {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type WatchEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"` // "added", "removed", "died", "revived", "labels", "renamed", "reset"
+	Proc          *Proc                  `protobuf:"bytes,2,opt,name=proc,proto3" json:"proc,omitempty"` // entry after the change (last state for "removed"); unset for "reset"
+	AtUnixMs      int64                  `protobuf:"varint,3,opt,name=at_unix_ms,json=atUnixMs,proto3" json:"at_unix_ms,omitempty"`
+	PreviousName  string                 `protobuf:"bytes,4,opt,name=previous_name,json=previousName,proto3" json:"previous_name,omitempty"` // set for "renamed"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchEvent) Reset() {
+	*x = WatchEvent{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEvent) ProtoMessage() {}
+
+func (x *WatchEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEvent.ProtoReflect.Descriptor instead.
+func (*WatchEvent) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *WatchEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *WatchEvent) GetProc() *Proc {
+	if x != nil {
+		return x.Proc
+	}
+	return nil
+}
+
+func (x *WatchEvent) GetAtUnixMs() int64 {
+	if x != nil {
+		return x.AtUnixMs
+	}
+	return 0
+}
+
+func (x *WatchEvent) GetPreviousName() string {
+	if x != nil {
+		return x.PreviousName
+	}
+	return ""
+}
+
+type StatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *ListRequest           `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	WindowMs      int64                  `protobuf:"varint,2,opt,name=window_ms,json=windowMs,proto3" json:"window_ms,omitempty"` // only samples this recent; <= 0 uses the whole retained history
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatsRequest) Reset() {
+	*x = StatsRequest{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsRequest) ProtoMessage() {}
+
+func (x *StatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsRequest.ProtoReflect.Descriptor instead.
+func (*StatsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *StatsRequest) GetFilter() *ListRequest {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *StatsRequest) GetWindowMs() int64 {
+	if x != nil {
+		return x.WindowMs
+	}
+	return 0
+}
+
+type MetricSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Min           float64                `protobuf:"fixed64,1,opt,name=min,proto3" json:"min,omitempty"`
+	Avg           float64                `protobuf:"fixed64,2,opt,name=avg,proto3" json:"avg,omitempty"`
+	Max           float64                `protobuf:"fixed64,3,opt,name=max,proto3" json:"max,omitempty"`
+	P95           float64                `protobuf:"fixed64,4,opt,name=p95,proto3" json:"p95,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetricSummary) Reset() {
+	*x = MetricSummary{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetricSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricSummary) ProtoMessage() {}
+
+func (x *MetricSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricSummary.ProtoReflect.Descriptor instead.
+func (*MetricSummary) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *MetricSummary) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *MetricSummary) GetAvg() float64 {
+	if x != nil {
+		return x.Avg
+	}
+	return 0
+}
+
+func (x *MetricSummary) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *MetricSummary) GetP95() float64 {
+	if x != nil {
+		return x.P95
+	}
+	return 0
+}
+
+type MetricSample struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AtUnixMs      int64                  `protobuf:"varint,1,opt,name=at_unix_ms,json=atUnixMs,proto3" json:"at_unix_ms,omitempty"`
+	CpuPercent    float64                `protobuf:"fixed64,2,opt,name=cpu_percent,json=cpuPercent,proto3" json:"cpu_percent,omitempty"`
+	RssBytes      uint64                 `protobuf:"varint,3,opt,name=rss_bytes,json=rssBytes,proto3" json:"rss_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetricSample) Reset() {
+	*x = MetricSample{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetricSample) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricSample) ProtoMessage() {}
+
+func (x *MetricSample) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricSample.ProtoReflect.Descriptor instead.
+func (*MetricSample) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *MetricSample) GetAtUnixMs() int64 {
+	if x != nil {
+		return x.AtUnixMs
+	}
+	return 0
+}
+
+func (x *MetricSample) GetCpuPercent() float64 {
+	if x != nil {
+		return x.CpuPercent
+	}
+	return 0
+}
+
+func (x *MetricSample) GetRssBytes() uint64 {
+	if x != nil {
+		return x.RssBytes
+	}
+	return 0
+}
+
+type ProcStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Proc          *Proc                  `protobuf:"bytes,1,opt,name=proc,proto3" json:"proc,omitempty"`
+	CpuPercent    *MetricSummary         `protobuf:"bytes,2,opt,name=cpu_percent,json=cpuPercent,proto3" json:"cpu_percent,omitempty"`
+	RssBytes      *MetricSummary         `protobuf:"bytes,3,opt,name=rss_bytes,json=rssBytes,proto3" json:"rss_bytes,omitempty"`
+	Samples       []*MetricSample        `protobuf:"bytes,4,rep,name=samples,proto3" json:"samples,omitempty"` // oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProcStats) Reset() {
+	*x = ProcStats{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcStats) ProtoMessage() {}
+
+func (x *ProcStats) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcStats.ProtoReflect.Descriptor instead.
+func (*ProcStats) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ProcStats) GetProc() *Proc {
+	if x != nil {
+		return x.Proc
+	}
+	return nil
+}
+
+func (x *ProcStats) GetCpuPercent() *MetricSummary {
+	if x != nil {
+		return x.CpuPercent
+	}
+	return nil
+}
+
+func (x *ProcStats) GetRssBytes() *MetricSummary {
+	if x != nil {
+		return x.RssBytes
+	}
+	return nil
+}
+
+func (x *ProcStats) GetSamples() []*MetricSample {
+	if x != nil {
+		return x.Samples
+	}
+	return nil
+}
+
+type StatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stats         []*ProcStats           `protobuf:"bytes,1,rep,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatsResponse) Reset() {
+	*x = StatsResponse{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsResponse) ProtoMessage() {}
+
+func (x *StatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsResponse.ProtoReflect.Descriptor instead.
+func (*StatsResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *StatsResponse) GetStats() []*ProcStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+// ApplyRequest reconciles the entries of a manifest with the processes it
+// declares, matched by name.
+type ApplyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Manifest      string                 `protobuf:"bytes,1,opt,name=manifest,proto3" json:"manifest,omitempty"`               // recorded on the entries the manifest starts
+	Processes     []*SpawnRequest        `protobuf:"bytes,2,rep,name=processes,proto3" json:"processes,omitempty"`             // name is required; env is added to the daemon environment
+	Prune         bool                   `protobuf:"varint,3,opt,name=prune,proto3" json:"prune,omitempty"`                    // stop and remove entries of the manifest that are no longer declared
+	DryRun        bool                   `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`    // report the plan without acting on it
+	GraceMs       int64                  `protobuf:"varint,5,opt,name=grace_ms,json=graceMs,proto3" json:"grace_ms,omitempty"` // SIGTERM grace before SIGKILL when stopping changed or pruned processes
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyRequest) Reset() {
+	*x = ApplyRequest{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyRequest) ProtoMessage() {}
+
+func (x *ApplyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyRequest.ProtoReflect.Descriptor instead.
+func (*ApplyRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ApplyRequest) GetManifest() string {
+	if x != nil {
+		return x.Manifest
+	}
+	return ""
+}
+
+func (x *ApplyRequest) GetProcesses() []*SpawnRequest {
+	if x != nil {
+		return x.Processes
+	}
+	return nil
+}
+
+func (x *ApplyRequest) GetPrune() bool {
+	if x != nil {
+		return x.Prune
+	}
+	return false
+}
+
+func (x *ApplyRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *ApplyRequest) GetGraceMs() int64 {
+	if x != nil {
+		return x.GraceMs
+	}
+	return 0
+}
+
+type ApplyAction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Action        string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"` // "create", "restart", "update", "unchanged", "prune" or "conflict"
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"` // what differs, e.g. "command, env"
+	Id            uint64                 `protobuf:"varint,4,opt,name=id,proto3" json:"id,omitempty"`        // entry acted on; the new entry after a create or restart, 0 for a planned create
+	Pid           int32                  `protobuf:"varint,5,opt,name=pid,proto3" json:"pid,omitempty"`
+	Error         string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"` // set when the action failed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyAction) Reset() {
+	*x = ApplyAction{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyAction) ProtoMessage() {}
+
+func (x *ApplyAction) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyAction.ProtoReflect.Descriptor instead.
+func (*ApplyAction) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ApplyAction) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ApplyAction) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ApplyAction) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ApplyAction) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ApplyAction) GetPid() int32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *ApplyAction) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ApplyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Actions       []*ApplyAction         `protobuf:"bytes,1,rep,name=actions,proto3" json:"actions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyResponse) Reset() {
+	*x = ApplyResponse{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyResponse) ProtoMessage() {}
+
+func (x *ApplyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyResponse.ProtoReflect.Descriptor instead.
+func (*ApplyResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ApplyResponse) GetActions() []*ApplyAction {
+	if x != nil {
+		return x.Actions
+	}
+	return nil
+}
+
+// HistoryRequest selects tombstones of removed entries, newest first.
+type HistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *ListRequest           `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`                                 // matched against the final state of each entry
+	SinceUnixMs   int64                  `protobuf:"varint,2,opt,name=since_unix_ms,json=sinceUnixMs,proto3" json:"since_unix_ms,omitempty"` // only entries removed at or after this time; 0 = all retained
+	Reasons       []string               `protobuf:"bytes,3,rep,name=reasons,proto3" json:"reasons,omitempty"`                               // "rm", "kill", "reset", "gc", "import" or "died"; empty = all
+	Limit         uint32                 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`                                  // newest matches only; 0 = all
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryRequest) Reset() {
+	*x = HistoryRequest{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RmRequest) String() string {
+func (x *HistoryRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RmRequest) ProtoMessage() {}
+func (*HistoryRequest) ProtoMessage() {}
 
-func (x *RmRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[9]
+func (x *HistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -631,39 +2939,64 @@ func (x *RmRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RmRequest.ProtoReflect.Descriptor instead.
-func (*RmRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use HistoryRequest.ProtoReflect.Descriptor instead.
+func (*HistoryRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{43}
 }
 
-func (x *RmRequest) GetId() uint64 {
+func (x *HistoryRequest) GetFilter() *ListRequest {
 	if x != nil {
-		return x.Id
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *HistoryRequest) GetSinceUnixMs() int64 {
+	if x != nil {
+		return x.SinceUnixMs
 	}
 	return 0
 }
 
-type RmResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *HistoryRequest) GetReasons() []string {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
 }
 
-func (x *RmResponse) Reset() {
-	*x = RmResponse{}
-	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[10]
+func (x *HistoryRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type Tombstone struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Seq             uint64                 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	RemovedAtUnixMs int64                  `protobuf:"varint,2,opt,name=removed_at_unix_ms,json=removedAtUnixMs,proto3" json:"removed_at_unix_ms,omitempty"`
+	Reason          string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Proc            *Proc                  `protobuf:"bytes,4,opt,name=proc,proto3" json:"proc,omitempty"` // final state of the entry
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Tombstone) Reset() {
+	*x = Tombstone{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RmResponse) String() string {
+func (x *Tombstone) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RmResponse) ProtoMessage() {}
+func (*Tombstone) ProtoMessage() {}
 
-func (x *RmResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[10]
+func (x *Tombstone) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -674,34 +3007,61 @@ func (x *RmResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RmResponse.ProtoReflect.Descriptor instead.
-func (*RmResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use Tombstone.ProtoReflect.Descriptor instead.
+func (*Tombstone) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{44}
 }
 
-type RenameTagRequest struct {
+func (x *Tombstone) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *Tombstone) GetRemovedAtUnixMs() int64 {
+	if x != nil {
+		return x.RemovedAtUnixMs
+	}
+	return 0
+}
+
+func (x *Tombstone) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Tombstone) GetProc() *Proc {
+	if x != nil {
+		return x.Proc
+	}
+	return nil
+}
+
+type HistoryResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
-	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Entries       []*Tombstone           `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RenameTagRequest) Reset() {
-	*x = RenameTagRequest{}
-	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[11]
+func (x *HistoryResponse) Reset() {
+	*x = HistoryResponse{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RenameTagRequest) String() string {
+func (x *HistoryResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RenameTagRequest) ProtoMessage() {}
+func (*HistoryResponse) ProtoMessage() {}
 
-func (x *RenameTagRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[11]
+func (x *HistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -712,47 +3072,41 @@ func (x *RenameTagRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RenameTagRequest.ProtoReflect.Descriptor instead.
-func (*RenameTagRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{11}
-}
-
-func (x *RenameTagRequest) GetFrom() string {
-	if x != nil {
-		return x.From
-	}
-	return ""
+// Deprecated: Use HistoryResponse.ProtoReflect.Descriptor instead.
+func (*HistoryResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{45}
 }
 
-func (x *RenameTagRequest) GetTo() string {
+func (x *HistoryResponse) GetEntries() []*Tombstone {
 	if x != nil {
-		return x.To
+		return x.Entries
 	}
-	return ""
+	return nil
 }
 
-type RenameTagResponse struct {
+// ExportRequest selects the entries to export.
+type ExportRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Updated       uint32                 `protobuf:"varint,1,opt,name=updated,proto3" json:"updated,omitempty"`
+	Filter        *ListRequest           `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RenameTagResponse) Reset() {
-	*x = RenameTagResponse{}
-	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[12]
+func (x *ExportRequest) Reset() {
+	*x = ExportRequest{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RenameTagResponse) String() string {
+func (x *ExportRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RenameTagResponse) ProtoMessage() {}
+func (*ExportRequest) ProtoMessage() {}
 
-func (x *RenameTagResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[12]
+func (x *ExportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -763,41 +3117,41 @@ func (x *RenameTagResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RenameTagResponse.ProtoReflect.Descriptor instead.
-func (*RenameTagResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use ExportRequest.ProtoReflect.Descriptor instead.
+func (*ExportRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{46}
 }
 
-func (x *RenameTagResponse) GetUpdated() uint32 {
+func (x *ExportRequest) GetFilter() *ListRequest {
 	if x != nil {
-		return x.Updated
+		return x.Filter
 	}
-	return 0
+	return nil
 }
 
-type RenameGroupRequest struct {
+type ExportResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
-	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Document      []byte                 `protobuf:"bytes,1,opt,name=document,proto3" json:"document,omitempty"` // JSON with version, exported_at and procs; see Import
+	Count         uint32                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`      // entries in the document
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RenameGroupRequest) Reset() {
-	*x = RenameGroupRequest{}
-	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[13]
+func (x *ExportResponse) Reset() {
+	*x = ExportResponse{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RenameGroupRequest) String() string {
+func (x *ExportResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RenameGroupRequest) ProtoMessage() {}
+func (*ExportResponse) ProtoMessage() {}
 
-func (x *RenameGroupRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[13]
+func (x *ExportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -808,47 +3162,52 @@ func (x *RenameGroupRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RenameGroupRequest.ProtoReflect.Descriptor instead.
-func (*RenameGroupRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use ExportResponse.ProtoReflect.Descriptor instead.
+func (*ExportResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{47}
 }
 
-func (x *RenameGroupRequest) GetFrom() string {
+func (x *ExportResponse) GetDocument() []byte {
 	if x != nil {
-		return x.From
+		return x.Document
 	}
-	return ""
+	return nil
 }
 
-func (x *RenameGroupRequest) GetTo() string {
+func (x *ExportResponse) GetCount() uint32 {
 	if x != nil {
-		return x.To
+		return x.Count
 	}
-	return ""
+	return 0
 }
 
-type RenameGroupResponse struct {
+// ImportRequest adds the entries of an exported document under new IDs.
+type ImportRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Updated       uint32                 `protobuf:"varint,1,opt,name=updated,proto3" json:"updated,omitempty"`
+	Document      []byte                 `protobuf:"bytes,1,opt,name=document,proto3" json:"document,omitempty"`                       // as returned by Export; older versions are migrated
+	Replace       bool                   `protobuf:"varint,2,opt,name=replace,proto3" json:"replace,omitempty"`                        // remove every existing entry first instead of merging
+	OnConflict    string                 `protobuf:"bytes,3,opt,name=on_conflict,json=onConflict,proto3" json:"on_conflict,omitempty"` // taken names: "rename" (default), "skip", "replace" or "fail"
+	Readopt       bool                   `protobuf:"varint,4,opt,name=readopt,proto3" json:"readopt,omitempty"`                        // track entries whose process is still running again
+	Respawn       bool                   `protobuf:"varint,5,opt,name=respawn,proto3" json:"respawn,omitempty"`                        // start entries with a spawn spec whose process is not running
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RenameGroupResponse) Reset() {
-	*x = RenameGroupResponse{}
-	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[14]
+func (x *ImportRequest) Reset() {
+	*x = ImportRequest{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RenameGroupResponse) String() string {
+func (x *ImportRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RenameGroupResponse) ProtoMessage() {}
+func (*ImportRequest) ProtoMessage() {}
 
-func (x *RenameGroupResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[14]
+func (x *ImportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -859,39 +3218,77 @@ func (x *RenameGroupResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RenameGroupResponse.ProtoReflect.Descriptor instead.
-func (*RenameGroupResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use ImportRequest.ProtoReflect.Descriptor instead.
+func (*ImportRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{48}
 }
 
-func (x *RenameGroupResponse) GetUpdated() uint32 {
+func (x *ImportRequest) GetDocument() []byte {
 	if x != nil {
-		return x.Updated
+		return x.Document
 	}
-	return 0
+	return nil
 }
 
-type ResetRequest struct {
+func (x *ImportRequest) GetReplace() bool {
+	if x != nil {
+		return x.Replace
+	}
+	return false
+}
+
+func (x *ImportRequest) GetOnConflict() string {
+	if x != nil {
+		return x.OnConflict
+	}
+	return ""
+}
+
+func (x *ImportRequest) GetReadopt() bool {
+	if x != nil {
+		return x.Readopt
+	}
+	return false
+}
+
+func (x *ImportRequest) GetRespawn() bool {
+	if x != nil {
+		return x.Respawn
+	}
+	return false
+}
+
+type ImportResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromId        uint64                 `protobuf:"varint,1,opt,name=from_id,json=fromId,proto3" json:"from_id,omitempty"` // ID in the document
+	Id            uint64                 `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`                       // new ID; 0 when skipped
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Action        string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"` // "added", "renamed", "replaced" or "skipped"
+	Alive         bool                   `protobuf:"varint,5,opt,name=alive,proto3" json:"alive,omitempty"`
+	Pid           int32                  `protobuf:"varint,6,opt,name=pid,proto3" json:"pid,omitempty"`
+	Respawned     bool                   `protobuf:"varint,7,opt,name=respawned,proto3" json:"respawned,omitempty"`
+	ReplacedId    uint64                 `protobuf:"varint,8,opt,name=replaced_id,json=replacedId,proto3" json:"replaced_id,omitempty"` // entry removed to free the name
+	Note          string                 `protobuf:"bytes,9,opt,name=note,proto3" json:"note,omitempty"`                                // why the entry was skipped or renamed
+	Error         string                 `protobuf:"bytes,10,opt,name=error,proto3" json:"error,omitempty"`                             // set when respawning failed
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ResetRequest) Reset() {
-	*x = ResetRequest{}
-	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[15]
+func (x *ImportResult) Reset() {
+	*x = ImportResult{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ResetRequest) String() string {
+func (x *ImportResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResetRequest) ProtoMessage() {}
+func (*ImportResult) ProtoMessage() {}
 
-func (x *ResetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[15]
+func (x *ImportResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -902,32 +3299,104 @@ func (x *ResetRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResetRequest.ProtoReflect.Descriptor instead.
-func (*ResetRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use ImportResult.ProtoReflect.Descriptor instead.
+func (*ImportResult) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{49}
 }
 
-type ResetResponse struct {
+func (x *ImportResult) GetFromId() uint64 {
+	if x != nil {
+		return x.FromId
+	}
+	return 0
+}
+
+func (x *ImportResult) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ImportResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ImportResult) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ImportResult) GetAlive() bool {
+	if x != nil {
+		return x.Alive
+	}
+	return false
+}
+
+func (x *ImportResult) GetPid() int32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *ImportResult) GetRespawned() bool {
+	if x != nil {
+		return x.Respawned
+	}
+	return false
+}
+
+func (x *ImportResult) GetReplacedId() uint64 {
+	if x != nil {
+		return x.ReplacedId
+	}
+	return 0
+}
+
+func (x *ImportResult) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *ImportResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ImportResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*ImportResult        `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Removed       uint32                 `protobuf:"varint,2,opt,name=removed,proto3" json:"removed,omitempty"` // entries removed by replace or the replace conflict policy
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ResetResponse) Reset() {
-	*x = ResetResponse{}
-	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[16]
+func (x *ImportResponse) Reset() {
+	*x = ImportResponse{}
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ResetResponse) String() string {
+func (x *ImportResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResetResponse) ProtoMessage() {}
+func (*ImportResponse) ProtoMessage() {}
 
-func (x *ResetResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[16]
+func (x *ImportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_goproc_v1_goproc_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -938,9 +3407,23 @@ func (x *ResetResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResetResponse.ProtoReflect.Descriptor instead.
-func (*ResetResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use ImportResponse.ProtoReflect.Descriptor instead.
+func (*ImportResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_goproc_v1_goproc_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ImportResponse) GetResults() []*ImportResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *ImportResponse) GetRemoved() uint32 {
+	if x != nil {
+		return x.Removed
+	}
+	return 0
 }
 
 var File_api_proto_goproc_v1_goproc_proto protoreflect.FileDescriptor
@@ -950,15 +3433,20 @@ const file_api_proto_goproc_v1_goproc_proto_rawDesc = "" +
 	" api/proto/goproc/v1/goproc.proto\x12\tgoproc.v1\"\r\n" +
 	"\vPingRequest\"\x1e\n" +
 	"\fPingResponse\x12\x0e\n" +
-	"\x02ok\x18\x01 \x01(\tR\x02ok\"^\n" +
+	"\x02ok\x18\x01 \x01(\tR\x02ok\"\x8f\x02\n" +
 	"\n" +
 	"AddRequest\x12\x10\n" +
 	"\x03pid\x18\x01 \x01(\x05R\x03pid\x12\x12\n" +
 	"\x04tags\x18\x02 \x03(\tR\x04tags\x12\x16\n" +
 	"\x06groups\x18\x03 \x03(\tR\x06groups\x12\x12\n" +
-	"\x04name\x18\x04 \x01(\tR\x04name\"\x1d\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12H\n" +
+	"\vannotations\x18\x05 \x03(\v2&.goproc.v1.AddRequest.AnnotationsEntryR\vannotations\x12%\n" +
+	"\x0etrack_children\x18\x06 \x01(\bR\rtrackChildren\x1a>\n" +
+	"\x10AnnotationsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x1d\n" +
 	"\vAddResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x04R\x02id\"\xfd\x01\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\xbd\x05\n" +
 	"\vListRequest\x12\x10\n" +
 	"\x03ids\x18\x01 \x03(\x04R\x03ids\x12\x12\n" +
 	"\x04pids\x18\x02 \x03(\x05R\x04pids\x12\x19\n" +
@@ -972,7 +3460,32 @@ const file_api_proto_goproc_v1_goproc_proto_rawDesc = "" +
 	"alive_only\x18\a \x01(\bR\taliveOnly\x12\x1f\n" +
 	"\vtext_search\x18\b \x01(\tR\n" +
 	"textSearch\x12\x14\n" +
-	"\x05names\x18\t \x03(\tR\x05names\"\xee\x01\n" +
+	"\x05names\x18\t \x03(\tR\x05names\x12?\n" +
+	"\vannotations\x18\n" +
+	" \x03(\v2\x1d.goproc.v1.AnnotationSelectorR\vannotations\x12\x14\n" +
+	"\x05query\x18\v \x01(\tR\x05query\x12\x1d\n" +
+	"\n" +
+	"name_globs\x18\f \x03(\tR\tnameGlobs\x12\x1d\n" +
+	"\n" +
+	"name_regex\x18\r \x01(\tR\tnameRegex\x12\x1b\n" +
+	"\ttag_globs\x18\x0e \x03(\tR\btagGlobs\x12\x1b\n" +
+	"\ttag_regex\x18\x0f \x01(\tR\btagRegex\x12\x1f\n" +
+	"\vgroup_globs\x18\x10 \x03(\tR\n" +
+	"groupGlobs\x12\x1f\n" +
+	"\vgroup_regex\x18\x11 \x01(\tR\n" +
+	"groupRegex\x12\x1b\n" +
+	"\tcmd_globs\x18\x12 \x03(\tR\bcmdGlobs\x12\x1b\n" +
+	"\tcmd_regex\x18\x13 \x01(\tR\bcmdRegex\x12\x12\n" +
+	"\x04sort\x18\x14 \x01(\tR\x04sort\x12\x12\n" +
+	"\x04desc\x18\x15 \x01(\bR\x04desc\x12\x14\n" +
+	"\x05limit\x18\x16 \x01(\rR\x05limit\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x17 \x01(\tR\tpageToken\x12\x16\n" +
+	"\x06fields\x18\x18 \x03(\tR\x06fields\"L\n" +
+	"\x12AnnotationSelector\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x0e\n" +
+	"\x02op\x18\x02 \x01(\tR\x02op\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\"\xdb\x05\n" +
 	"\x04Proc\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x10\n" +
 	"\x03pid\x18\x02 \x01(\x05R\x03pid\x12\x12\n" +
@@ -984,18 +3497,91 @@ const file_api_proto_goproc_v1_goproc_proto_rawDesc = "" +
 	"\radded_at_unix\x18\b \x01(\x03R\vaddedAtUnix\x12$\n" +
 	"\x0elast_seen_unix\x18\t \x01(\x03R\flastSeenUnix\x12\x12\n" +
 	"\x04name\x18\n" +
-	" \x01(\tR\x04name\"5\n" +
+	" \x01(\tR\x04name\x122\n" +
+	"\arestart\x18\v \x01(\v2\x18.goproc.v1.RestartPolicyR\arestart\x12\x1a\n" +
+	"\brestarts\x18\f \x01(\rR\brestarts\x120\n" +
+	"\ametrics\x18\r \x01(\v2\x16.goproc.v1.ProcMetricsR\ametrics\x12)\n" +
+	"\x04exit\x18\x0e \x01(\v2\x15.goproc.v1.ExitStatusR\x04exit\x12B\n" +
+	"\vannotations\x18\x0f \x03(\v2 .goproc.v1.Proc.AnnotationsEntryR\vannotations\x12\x1b\n" +
+	"\tparent_id\x18\x10 \x01(\x04R\bparentId\x12%\n" +
+	"\x0etrack_children\x18\x11 \x01(\bR\rtrackChildren\x12/\n" +
+	"\bchildren\x18\x12 \x03(\v2\x13.goproc.v1.ProcNodeR\bchildren\x12)\n" +
+	"\x06cgroup\x18\x13 \x01(\v2\x11.goproc.v1.CgroupR\x06cgroup\x12\x1a\n" +
+	"\bmanifest\x18\x14 \x01(\tR\bmanifest\x1a>\n" +
+	"\x10AnnotationsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"z\n" +
+	"\x06Cgroup\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12(\n" +
+	"\x10memory_max_bytes\x18\x02 \x01(\x04R\x0ememoryMaxBytes\x12\x17\n" +
+	"\acpu_max\x18\x03 \x01(\x01R\x06cpuMax\x12\x19\n" +
+	"\bpids_max\x18\x04 \x01(\rR\apidsMax\"o\n" +
+	"\bProcNode\x12\x10\n" +
+	"\x03pid\x18\x01 \x01(\x05R\x03pid\x12\x10\n" +
+	"\x03cmd\x18\x02 \x01(\tR\x03cmd\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\x04R\x02id\x12/\n" +
+	"\bchildren\x18\x04 \x03(\v2\x13.goproc.v1.ProcNodeR\bchildren\"\x8a\x01\n" +
+	"\n" +
+	"ExitStatus\x12\x17\n" +
+	"\aat_unix\x18\x01 \x01(\x03R\x06atUnix\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\x05R\x04code\x12\x16\n" +
+	"\x06signal\x18\x04 \x01(\tR\x06signal\x12\x1f\n" +
+	"\vcore_dumped\x18\x05 \x01(\bR\n" +
+	"coreDumped\"\x85\x02\n" +
+	"\vProcMetrics\x12\x1f\n" +
+	"\vcpu_percent\x18\x01 \x01(\x01R\n" +
+	"cpuPercent\x12\x1b\n" +
+	"\trss_bytes\x18\x02 \x01(\x04R\brssBytes\x12\x1b\n" +
+	"\tvms_bytes\x18\x03 \x01(\x04R\bvmsBytes\x12\x18\n" +
+	"\athreads\x18\x04 \x01(\rR\athreads\x12\x19\n" +
+	"\bopen_fds\x18\x05 \x01(\rR\aopenFds\x12\x1d\n" +
+	"\n" +
+	"read_bytes\x18\x06 \x01(\x04R\treadBytes\x12\x1f\n" +
+	"\vwrite_bytes\x18\a \x01(\x04R\n" +
+	"writeBytes\x12&\n" +
+	"\x0fsampled_at_unix\x18\b \x01(\x03R\rsampledAtUnix\"s\n" +
 	"\fListResponse\x12%\n" +
-	"\x05procs\x18\x01 \x03(\v2\x0f.goproc.v1.ProcR\x05procs\"=\n" +
+	"\x05procs\x18\x01 \x03(\v2\x0f.goproc.v1.ProcR\x05procs\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\rR\x05total\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageToken\"\xa7\x01\n" +
 	"\vKillRequest\x12\x10\n" +
 	"\x02id\x18\x01 \x01(\x04H\x00R\x02id\x12\x12\n" +
-	"\x03pid\x18\x02 \x01(\x05H\x00R\x03pidB\b\n" +
-	"\x06target\"\x0e\n" +
-	"\fKillResponse\"\x1b\n" +
+	"\x03pid\x18\x02 \x01(\x05H\x00R\x03pid\x12\x16\n" +
+	"\x06signal\x18\x03 \x01(\tR\x06signal\x12\x19\n" +
+	"\bgrace_ms\x18\x04 \x01(\x03R\agraceMs\x12\x1a\n" +
+	"\bescalate\x18\x05 \x01(\bR\bescalate\x12\x19\n" +
+	"\bno_group\x18\x06 \x01(\bR\anoGroupB\b\n" +
+	"\x06target\"(\n" +
+	"\fKillResponse\x12\x18\n" +
+	"\aoutcome\x18\x01 \x01(\tR\aoutcome\"\\\n" +
+	"\rSignalRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x16\n" +
+	"\x06signal\x18\x02 \x01(\tR\x06signal\x12#\n" +
+	"\rprocess_group\x18\x03 \x01(\bR\fprocessGroup\"\x10\n" +
+	"\x0eSignalResponse\"3\n" +
 	"\tRmRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x04R\x02id\"\f\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\f\n" +
 	"\n" +
-	"RmResponse\"6\n" +
+	"RmResponse\"\xc5\x01\n" +
+	"\x13UpdateLabelsRequest\x12.\n" +
+	"\x06filter\x18\x01 \x01(\v2\x16.goproc.v1.ListRequestR\x06filter\x12\x19\n" +
+	"\badd_tags\x18\x02 \x03(\tR\aaddTags\x12\x1f\n" +
+	"\vremove_tags\x18\x03 \x03(\tR\n" +
+	"removeTags\x12\x1d\n" +
+	"\n" +
+	"add_groups\x18\x04 \x03(\tR\taddGroups\x12#\n" +
+	"\rremove_groups\x18\x05 \x03(\tR\fremoveGroups\"W\n" +
+	"\x14UpdateLabelsResponse\x12%\n" +
+	"\x05procs\x18\x01 \x03(\v2\x0f.goproc.v1.ProcR\x05procs\x12\x18\n" +
+	"\aupdated\x18\x02 \x01(\rR\aupdated\"4\n" +
+	"\x0eSetNameRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"[\n" +
+	"\x0fSetNameResponse\x12#\n" +
+	"\x04proc\x18\x01 \x01(\v2\x0f.goproc.v1.ProcR\x04proc\x12#\n" +
+	"\rprevious_name\x18\x02 \x01(\tR\fpreviousName\"6\n" +
 	"\x10RenameTagRequest\x12\x12\n" +
 	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
 	"\x02to\x18\x02 \x01(\tR\x02to\"-\n" +
@@ -1007,7 +3593,125 @@ const file_api_proto_goproc_v1_goproc_proto_rawDesc = "" +
 	"\x13RenameGroupResponse\x12\x18\n" +
 	"\aupdated\x18\x01 \x01(\rR\aupdated\"\x0e\n" +
 	"\fResetRequest\"\x0f\n" +
-	"\rResetResponse2\xee\x03\n" +
+	"\rResetResponse\"\xa8\x01\n" +
+	"\rRestartPolicy\x12\x12\n" +
+	"\x04mode\x18\x01 \x01(\tR\x04mode\x12!\n" +
+	"\fmax_restarts\x18\x02 \x01(\rR\vmaxRestarts\x12\x1b\n" +
+	"\twindow_ms\x18\x03 \x01(\x03R\bwindowMs\x12\x1d\n" +
+	"\n" +
+	"backoff_ms\x18\x04 \x01(\x03R\tbackoffMs\x12$\n" +
+	"\x0emax_backoff_ms\x18\x05 \x01(\x03R\fmaxBackoffMs\"\x98\x03\n" +
+	"\fSpawnRequest\x12\x12\n" +
+	"\x04argv\x18\x01 \x03(\tR\x04argv\x12\x10\n" +
+	"\x03env\x18\x02 \x03(\tR\x03env\x12\x10\n" +
+	"\x03cwd\x18\x03 \x01(\tR\x03cwd\x12\x12\n" +
+	"\x04tags\x18\x04 \x03(\tR\x04tags\x12\x16\n" +
+	"\x06groups\x18\x05 \x03(\tR\x06groups\x12\x12\n" +
+	"\x04name\x18\x06 \x01(\tR\x04name\x122\n" +
+	"\arestart\x18\a \x01(\v2\x18.goproc.v1.RestartPolicyR\arestart\x12J\n" +
+	"\vannotations\x18\b \x03(\v2(.goproc.v1.SpawnRequest.AnnotationsEntryR\vannotations\x12%\n" +
+	"\x0etrack_children\x18\t \x01(\bR\rtrackChildren\x12)\n" +
+	"\x06cgroup\x18\n" +
+	" \x01(\v2\x11.goproc.v1.CgroupR\x06cgroup\x1a>\n" +
+	"\x10AnnotationsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"1\n" +
+	"\rSpawnResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x10\n" +
+	"\x03pid\x18\x02 \x01(\x05R\x03pid\"a\n" +
+	"\vLogsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x16\n" +
+	"\x06stderr\x18\x02 \x01(\bR\x06stderr\x12\x16\n" +
+	"\x06follow\x18\x03 \x01(\bR\x06follow\x12\x12\n" +
+	"\x04tail\x18\x04 \x01(\x05R\x04tail\"\x1e\n" +
+	"\bLogChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\">\n" +
+	"\fWatchRequest\x12.\n" +
+	"\x06filter\x18\x01 \x01(\v2\x16.goproc.v1.ListRequestR\x06filter\"\x88\x01\n" +
+	"\n" +
+	"WatchEvent\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12#\n" +
+	"\x04proc\x18\x02 \x01(\v2\x0f.goproc.v1.ProcR\x04proc\x12\x1c\n" +
+	"\n" +
+	"at_unix_ms\x18\x03 \x01(\x03R\batUnixMs\x12#\n" +
+	"\rprevious_name\x18\x04 \x01(\tR\fpreviousName\"[\n" +
+	"\fStatsRequest\x12.\n" +
+	"\x06filter\x18\x01 \x01(\v2\x16.goproc.v1.ListRequestR\x06filter\x12\x1b\n" +
+	"\twindow_ms\x18\x02 \x01(\x03R\bwindowMs\"W\n" +
+	"\rMetricSummary\x12\x10\n" +
+	"\x03min\x18\x01 \x01(\x01R\x03min\x12\x10\n" +
+	"\x03avg\x18\x02 \x01(\x01R\x03avg\x12\x10\n" +
+	"\x03max\x18\x03 \x01(\x01R\x03max\x12\x10\n" +
+	"\x03p95\x18\x04 \x01(\x01R\x03p95\"j\n" +
+	"\fMetricSample\x12\x1c\n" +
+	"\n" +
+	"at_unix_ms\x18\x01 \x01(\x03R\batUnixMs\x12\x1f\n" +
+	"\vcpu_percent\x18\x02 \x01(\x01R\n" +
+	"cpuPercent\x12\x1b\n" +
+	"\trss_bytes\x18\x03 \x01(\x04R\brssBytes\"\xd5\x01\n" +
+	"\tProcStats\x12#\n" +
+	"\x04proc\x18\x01 \x01(\v2\x0f.goproc.v1.ProcR\x04proc\x129\n" +
+	"\vcpu_percent\x18\x02 \x01(\v2\x18.goproc.v1.MetricSummaryR\n" +
+	"cpuPercent\x125\n" +
+	"\trss_bytes\x18\x03 \x01(\v2\x18.goproc.v1.MetricSummaryR\brssBytes\x121\n" +
+	"\asamples\x18\x04 \x03(\v2\x17.goproc.v1.MetricSampleR\asamples\";\n" +
+	"\rStatsResponse\x12*\n" +
+	"\x05stats\x18\x01 \x03(\v2\x14.goproc.v1.ProcStatsR\x05stats\"\xab\x01\n" +
+	"\fApplyRequest\x12\x1a\n" +
+	"\bmanifest\x18\x01 \x01(\tR\bmanifest\x125\n" +
+	"\tprocesses\x18\x02 \x03(\v2\x17.goproc.v1.SpawnRequestR\tprocesses\x12\x14\n" +
+	"\x05prune\x18\x03 \x01(\bR\x05prune\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\x12\x19\n" +
+	"\bgrace_ms\x18\x05 \x01(\x03R\agraceMs\"\x89\x01\n" +
+	"\vApplyAction\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x0e\n" +
+	"\x02id\x18\x04 \x01(\x04R\x02id\x12\x10\n" +
+	"\x03pid\x18\x05 \x01(\x05R\x03pid\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\"A\n" +
+	"\rApplyResponse\x120\n" +
+	"\aactions\x18\x01 \x03(\v2\x16.goproc.v1.ApplyActionR\aactions\"\x94\x01\n" +
+	"\x0eHistoryRequest\x12.\n" +
+	"\x06filter\x18\x01 \x01(\v2\x16.goproc.v1.ListRequestR\x06filter\x12\"\n" +
+	"\rsince_unix_ms\x18\x02 \x01(\x03R\vsinceUnixMs\x12\x18\n" +
+	"\areasons\x18\x03 \x03(\tR\areasons\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\rR\x05limit\"\x87\x01\n" +
+	"\tTombstone\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x04R\x03seq\x12+\n" +
+	"\x12removed_at_unix_ms\x18\x02 \x01(\x03R\x0fremovedAtUnixMs\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12#\n" +
+	"\x04proc\x18\x04 \x01(\v2\x0f.goproc.v1.ProcR\x04proc\"A\n" +
+	"\x0fHistoryResponse\x12.\n" +
+	"\aentries\x18\x01 \x03(\v2\x14.goproc.v1.TombstoneR\aentries\"?\n" +
+	"\rExportRequest\x12.\n" +
+	"\x06filter\x18\x01 \x01(\v2\x16.goproc.v1.ListRequestR\x06filter\"B\n" +
+	"\x0eExportResponse\x12\x1a\n" +
+	"\bdocument\x18\x01 \x01(\fR\bdocument\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\rR\x05count\"\x9a\x01\n" +
+	"\rImportRequest\x12\x1a\n" +
+	"\bdocument\x18\x01 \x01(\fR\bdocument\x12\x18\n" +
+	"\areplace\x18\x02 \x01(\bR\areplace\x12\x1f\n" +
+	"\von_conflict\x18\x03 \x01(\tR\n" +
+	"onConflict\x12\x18\n" +
+	"\areadopt\x18\x04 \x01(\bR\areadopt\x12\x18\n" +
+	"\arespawn\x18\x05 \x01(\bR\arespawn\"\xf4\x01\n" +
+	"\fImportResult\x12\x17\n" +
+	"\afrom_id\x18\x01 \x01(\x04R\x06fromId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x16\n" +
+	"\x06action\x18\x04 \x01(\tR\x06action\x12\x14\n" +
+	"\x05alive\x18\x05 \x01(\bR\x05alive\x12\x10\n" +
+	"\x03pid\x18\x06 \x01(\x05R\x03pid\x12\x1c\n" +
+	"\trespawned\x18\a \x01(\bR\trespawned\x12\x1f\n" +
+	"\vreplaced_id\x18\b \x01(\x04R\n" +
+	"replacedId\x12\x12\n" +
+	"\x04note\x18\t \x01(\tR\x04note\x12\x14\n" +
+	"\x05error\x18\n" +
+	" \x01(\tR\x05error\"]\n" +
+	"\x0eImportResponse\x121\n" +
+	"\aresults\x18\x01 \x03(\v2\x17.goproc.v1.ImportResultR\aresults\x12\x18\n" +
+	"\aremoved\x18\x02 \x01(\rR\aremoved2\xa6\t\n" +
 	"\x06GoProc\x127\n" +
 	"\x04Ping\x12\x16.goproc.v1.PingRequest\x1a\x17.goproc.v1.PingResponse\x124\n" +
 	"\x03Add\x12\x15.goproc.v1.AddRequest\x1a\x16.goproc.v1.AddResponse\x127\n" +
@@ -1016,7 +3720,18 @@ const file_api_proto_goproc_v1_goproc_proto_rawDesc = "" +
 	"\x02Rm\x12\x14.goproc.v1.RmRequest\x1a\x15.goproc.v1.RmResponse\x12F\n" +
 	"\tRenameTag\x12\x1b.goproc.v1.RenameTagRequest\x1a\x1c.goproc.v1.RenameTagResponse\x12L\n" +
 	"\vRenameGroup\x12\x1d.goproc.v1.RenameGroupRequest\x1a\x1e.goproc.v1.RenameGroupResponse\x12:\n" +
-	"\x05Reset\x12\x17.goproc.v1.ResetRequest\x1a\x18.goproc.v1.ResetResponseB%Z#goproc/api/proto/goproc/v1;goprocv1b\x06proto3"
+	"\x05Reset\x12\x17.goproc.v1.ResetRequest\x1a\x18.goproc.v1.ResetResponse\x12:\n" +
+	"\x05Spawn\x12\x17.goproc.v1.SpawnRequest\x1a\x18.goproc.v1.SpawnResponse\x125\n" +
+	"\x04Logs\x12\x16.goproc.v1.LogsRequest\x1a\x13.goproc.v1.LogChunk0\x01\x129\n" +
+	"\x05Watch\x12\x17.goproc.v1.WatchRequest\x1a\x15.goproc.v1.WatchEvent0\x01\x12:\n" +
+	"\x05Stats\x12\x17.goproc.v1.StatsRequest\x1a\x18.goproc.v1.StatsResponse\x12=\n" +
+	"\x06Signal\x12\x18.goproc.v1.SignalRequest\x1a\x19.goproc.v1.SignalResponse\x12O\n" +
+	"\fUpdateLabels\x12\x1e.goproc.v1.UpdateLabelsRequest\x1a\x1f.goproc.v1.UpdateLabelsResponse\x12@\n" +
+	"\aSetName\x12\x19.goproc.v1.SetNameRequest\x1a\x1a.goproc.v1.SetNameResponse\x12:\n" +
+	"\x05Apply\x12\x17.goproc.v1.ApplyRequest\x1a\x18.goproc.v1.ApplyResponse\x12@\n" +
+	"\aHistory\x12\x19.goproc.v1.HistoryRequest\x1a\x1a.goproc.v1.HistoryResponse\x12=\n" +
+	"\x06Export\x12\x18.goproc.v1.ExportRequest\x1a\x19.goproc.v1.ExportResponse\x12=\n" +
+	"\x06Import\x12\x18.goproc.v1.ImportRequest\x1a\x19.goproc.v1.ImportResponseB%Z#goproc/api/proto/goproc/v1;goprocv1b\x06proto3"
 
 var (
 	file_api_proto_goproc_v1_goproc_proto_rawDescOnce sync.Once
@@ -1030,49 +3745,138 @@ func file_api_proto_goproc_v1_goproc_proto_rawDescGZIP() []byte {
 	return file_api_proto_goproc_v1_goproc_proto_rawDescData
 }
 
-var file_api_proto_goproc_v1_goproc_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_api_proto_goproc_v1_goproc_proto_msgTypes = make([]protoimpl.MessageInfo, 54)
 var file_api_proto_goproc_v1_goproc_proto_goTypes = []any{
-	(*PingRequest)(nil),         // 0: goproc.v1.PingRequest
-	(*PingResponse)(nil),        // 1: goproc.v1.PingResponse
-	(*AddRequest)(nil),          // 2: goproc.v1.AddRequest
-	(*AddResponse)(nil),         // 3: goproc.v1.AddResponse
-	(*ListRequest)(nil),         // 4: goproc.v1.ListRequest
-	(*Proc)(nil),                // 5: goproc.v1.Proc
-	(*ListResponse)(nil),        // 6: goproc.v1.ListResponse
-	(*KillRequest)(nil),         // 7: goproc.v1.KillRequest
-	(*KillResponse)(nil),        // 8: goproc.v1.KillResponse
-	(*RmRequest)(nil),           // 9: goproc.v1.RmRequest
-	(*RmResponse)(nil),          // 10: goproc.v1.RmResponse
-	(*RenameTagRequest)(nil),    // 11: goproc.v1.RenameTagRequest
-	(*RenameTagResponse)(nil),   // 12: goproc.v1.RenameTagResponse
-	(*RenameGroupRequest)(nil),  // 13: goproc.v1.RenameGroupRequest
-	(*RenameGroupResponse)(nil), // 14: goproc.v1.RenameGroupResponse
-	(*ResetRequest)(nil),        // 15: goproc.v1.ResetRequest
-	(*ResetResponse)(nil),       // 16: goproc.v1.ResetResponse
+	(*PingRequest)(nil),          // 0: goproc.v1.PingRequest
+	(*PingResponse)(nil),         // 1: goproc.v1.PingResponse
+	(*AddRequest)(nil),           // 2: goproc.v1.AddRequest
+	(*AddResponse)(nil),          // 3: goproc.v1.AddResponse
+	(*ListRequest)(nil),          // 4: goproc.v1.ListRequest
+	(*AnnotationSelector)(nil),   // 5: goproc.v1.AnnotationSelector
+	(*Proc)(nil),                 // 6: goproc.v1.Proc
+	(*Cgroup)(nil),               // 7: goproc.v1.Cgroup
+	(*ProcNode)(nil),             // 8: goproc.v1.ProcNode
+	(*ExitStatus)(nil),           // 9: goproc.v1.ExitStatus
+	(*ProcMetrics)(nil),          // 10: goproc.v1.ProcMetrics
+	(*ListResponse)(nil),         // 11: goproc.v1.ListResponse
+	(*KillRequest)(nil),          // 12: goproc.v1.KillRequest
+	(*KillResponse)(nil),         // 13: goproc.v1.KillResponse
+	(*SignalRequest)(nil),        // 14: goproc.v1.SignalRequest
+	(*SignalResponse)(nil),       // 15: goproc.v1.SignalResponse
+	(*RmRequest)(nil),            // 16: goproc.v1.RmRequest
+	(*RmResponse)(nil),           // 17: goproc.v1.RmResponse
+	(*UpdateLabelsRequest)(nil),  // 18: goproc.v1.UpdateLabelsRequest
+	(*UpdateLabelsResponse)(nil), // 19: goproc.v1.UpdateLabelsResponse
+	(*SetNameRequest)(nil),       // 20: goproc.v1.SetNameRequest
+	(*SetNameResponse)(nil),      // 21: goproc.v1.SetNameResponse
+	(*RenameTagRequest)(nil),     // 22: goproc.v1.RenameTagRequest
+	(*RenameTagResponse)(nil),    // 23: goproc.v1.RenameTagResponse
+	(*RenameGroupRequest)(nil),   // 24: goproc.v1.RenameGroupRequest
+	(*RenameGroupResponse)(nil),  // 25: goproc.v1.RenameGroupResponse
+	(*ResetRequest)(nil),         // 26: goproc.v1.ResetRequest
+	(*ResetResponse)(nil),        // 27: goproc.v1.ResetResponse
+	(*RestartPolicy)(nil),        // 28: goproc.v1.RestartPolicy
+	(*SpawnRequest)(nil),         // 29: goproc.v1.SpawnRequest
+	(*SpawnResponse)(nil),        // 30: goproc.v1.SpawnResponse
+	(*LogsRequest)(nil),          // 31: goproc.v1.LogsRequest
+	(*LogChunk)(nil),             // 32: goproc.v1.LogChunk
+	(*WatchRequest)(nil),         // 33: goproc.v1.WatchRequest
+	(*WatchEvent)(nil),           // 34: goproc.v1.WatchEvent
+	(*StatsRequest)(nil),         // 35: goproc.v1.StatsRequest
+	(*MetricSummary)(nil),        // 36: goproc.v1.MetricSummary
+	(*MetricSample)(nil),         // 37: goproc.v1.MetricSample
+	(*ProcStats)(nil),            // 38: goproc.v1.ProcStats
+	(*StatsResponse)(nil),        // 39: goproc.v1.StatsResponse
+	(*ApplyRequest)(nil),         // 40: goproc.v1.ApplyRequest
+	(*ApplyAction)(nil),          // 41: goproc.v1.ApplyAction
+	(*ApplyResponse)(nil),        // 42: goproc.v1.ApplyResponse
+	(*HistoryRequest)(nil),       // 43: goproc.v1.HistoryRequest
+	(*Tombstone)(nil),            // 44: goproc.v1.Tombstone
+	(*HistoryResponse)(nil),      // 45: goproc.v1.HistoryResponse
+	(*ExportRequest)(nil),        // 46: goproc.v1.ExportRequest
+	(*ExportResponse)(nil),       // 47: goproc.v1.ExportResponse
+	(*ImportRequest)(nil),        // 48: goproc.v1.ImportRequest
+	(*ImportResult)(nil),         // 49: goproc.v1.ImportResult
+	(*ImportResponse)(nil),       // 50: goproc.v1.ImportResponse
+	nil,                          // 51: goproc.v1.AddRequest.AnnotationsEntry
+	nil,                          // 52: goproc.v1.Proc.AnnotationsEntry
+	nil,                          // 53: goproc.v1.SpawnRequest.AnnotationsEntry
 }
 var file_api_proto_goproc_v1_goproc_proto_depIdxs = []int32{
-	5,  // 0: goproc.v1.ListResponse.procs:type_name -> goproc.v1.Proc
-	0,  // 1: goproc.v1.GoProc.Ping:input_type -> goproc.v1.PingRequest
-	2,  // 2: goproc.v1.GoProc.Add:input_type -> goproc.v1.AddRequest
-	4,  // 3: goproc.v1.GoProc.List:input_type -> goproc.v1.ListRequest
-	7,  // 4: goproc.v1.GoProc.Kill:input_type -> goproc.v1.KillRequest
-	9,  // 5: goproc.v1.GoProc.Rm:input_type -> goproc.v1.RmRequest
-	11, // 6: goproc.v1.GoProc.RenameTag:input_type -> goproc.v1.RenameTagRequest
-	13, // 7: goproc.v1.GoProc.RenameGroup:input_type -> goproc.v1.RenameGroupRequest
-	15, // 8: goproc.v1.GoProc.Reset:input_type -> goproc.v1.ResetRequest
-	1,  // 9: goproc.v1.GoProc.Ping:output_type -> goproc.v1.PingResponse
-	3,  // 10: goproc.v1.GoProc.Add:output_type -> goproc.v1.AddResponse
-	6,  // 11: goproc.v1.GoProc.List:output_type -> goproc.v1.ListResponse
-	8,  // 12: goproc.v1.GoProc.Kill:output_type -> goproc.v1.KillResponse
-	10, // 13: goproc.v1.GoProc.Rm:output_type -> goproc.v1.RmResponse
-	12, // 14: goproc.v1.GoProc.RenameTag:output_type -> goproc.v1.RenameTagResponse
-	14, // 15: goproc.v1.GoProc.RenameGroup:output_type -> goproc.v1.RenameGroupResponse
-	16, // 16: goproc.v1.GoProc.Reset:output_type -> goproc.v1.ResetResponse
-	9,  // [9:17] is the sub-list for method output_type
-	1,  // [1:9] is the sub-list for method input_type
-	1,  // [1:1] is the sub-list for extension type_name
-	1,  // [1:1] is the sub-list for extension extendee
-	0,  // [0:1] is the sub-list for field type_name
+	51, // 0: goproc.v1.AddRequest.annotations:type_name -> goproc.v1.AddRequest.AnnotationsEntry
+	5,  // 1: goproc.v1.ListRequest.annotations:type_name -> goproc.v1.AnnotationSelector
+	28, // 2: goproc.v1.Proc.restart:type_name -> goproc.v1.RestartPolicy
+	10, // 3: goproc.v1.Proc.metrics:type_name -> goproc.v1.ProcMetrics
+	9,  // 4: goproc.v1.Proc.exit:type_name -> goproc.v1.ExitStatus
+	52, // 5: goproc.v1.Proc.annotations:type_name -> goproc.v1.Proc.AnnotationsEntry
+	8,  // 6: goproc.v1.Proc.children:type_name -> goproc.v1.ProcNode
+	7,  // 7: goproc.v1.Proc.cgroup:type_name -> goproc.v1.Cgroup
+	8,  // 8: goproc.v1.ProcNode.children:type_name -> goproc.v1.ProcNode
+	6,  // 9: goproc.v1.ListResponse.procs:type_name -> goproc.v1.Proc
+	4,  // 10: goproc.v1.UpdateLabelsRequest.filter:type_name -> goproc.v1.ListRequest
+	6,  // 11: goproc.v1.UpdateLabelsResponse.procs:type_name -> goproc.v1.Proc
+	6,  // 12: goproc.v1.SetNameResponse.proc:type_name -> goproc.v1.Proc
+	28, // 13: goproc.v1.SpawnRequest.restart:type_name -> goproc.v1.RestartPolicy
+	53, // 14: goproc.v1.SpawnRequest.annotations:type_name -> goproc.v1.SpawnRequest.AnnotationsEntry
+	7,  // 15: goproc.v1.SpawnRequest.cgroup:type_name -> goproc.v1.Cgroup
+	4,  // 16: goproc.v1.WatchRequest.filter:type_name -> goproc.v1.ListRequest
+	6,  // 17: goproc.v1.WatchEvent.proc:type_name -> goproc.v1.Proc
+	4,  // 18: goproc.v1.StatsRequest.filter:type_name -> goproc.v1.ListRequest
+	6,  // 19: goproc.v1.ProcStats.proc:type_name -> goproc.v1.Proc
+	36, // 20: goproc.v1.ProcStats.cpu_percent:type_name -> goproc.v1.MetricSummary
+	36, // 21: goproc.v1.ProcStats.rss_bytes:type_name -> goproc.v1.MetricSummary
+	37, // 22: goproc.v1.ProcStats.samples:type_name -> goproc.v1.MetricSample
+	38, // 23: goproc.v1.StatsResponse.stats:type_name -> goproc.v1.ProcStats
+	29, // 24: goproc.v1.ApplyRequest.processes:type_name -> goproc.v1.SpawnRequest
+	41, // 25: goproc.v1.ApplyResponse.actions:type_name -> goproc.v1.ApplyAction
+	4,  // 26: goproc.v1.HistoryRequest.filter:type_name -> goproc.v1.ListRequest
+	6,  // 27: goproc.v1.Tombstone.proc:type_name -> goproc.v1.Proc
+	44, // 28: goproc.v1.HistoryResponse.entries:type_name -> goproc.v1.Tombstone
+	4,  // 29: goproc.v1.ExportRequest.filter:type_name -> goproc.v1.ListRequest
+	49, // 30: goproc.v1.ImportResponse.results:type_name -> goproc.v1.ImportResult
+	0,  // 31: goproc.v1.GoProc.Ping:input_type -> goproc.v1.PingRequest
+	2,  // 32: goproc.v1.GoProc.Add:input_type -> goproc.v1.AddRequest
+	4,  // 33: goproc.v1.GoProc.List:input_type -> goproc.v1.ListRequest
+	12, // 34: goproc.v1.GoProc.Kill:input_type -> goproc.v1.KillRequest
+	16, // 35: goproc.v1.GoProc.Rm:input_type -> goproc.v1.RmRequest
+	22, // 36: goproc.v1.GoProc.RenameTag:input_type -> goproc.v1.RenameTagRequest
+	24, // 37: goproc.v1.GoProc.RenameGroup:input_type -> goproc.v1.RenameGroupRequest
+	26, // 38: goproc.v1.GoProc.Reset:input_type -> goproc.v1.ResetRequest
+	29, // 39: goproc.v1.GoProc.Spawn:input_type -> goproc.v1.SpawnRequest
+	31, // 40: goproc.v1.GoProc.Logs:input_type -> goproc.v1.LogsRequest
+	33, // 41: goproc.v1.GoProc.Watch:input_type -> goproc.v1.WatchRequest
+	35, // 42: goproc.v1.GoProc.Stats:input_type -> goproc.v1.StatsRequest
+	14, // 43: goproc.v1.GoProc.Signal:input_type -> goproc.v1.SignalRequest
+	18, // 44: goproc.v1.GoProc.UpdateLabels:input_type -> goproc.v1.UpdateLabelsRequest
+	20, // 45: goproc.v1.GoProc.SetName:input_type -> goproc.v1.SetNameRequest
+	40, // 46: goproc.v1.GoProc.Apply:input_type -> goproc.v1.ApplyRequest
+	43, // 47: goproc.v1.GoProc.History:input_type -> goproc.v1.HistoryRequest
+	46, // 48: goproc.v1.GoProc.Export:input_type -> goproc.v1.ExportRequest
+	48, // 49: goproc.v1.GoProc.Import:input_type -> goproc.v1.ImportRequest
+	1,  // 50: goproc.v1.GoProc.Ping:output_type -> goproc.v1.PingResponse
+	3,  // 51: goproc.v1.GoProc.Add:output_type -> goproc.v1.AddResponse
+	11, // 52: goproc.v1.GoProc.List:output_type -> goproc.v1.ListResponse
+	13, // 53: goproc.v1.GoProc.Kill:output_type -> goproc.v1.KillResponse
+	17, // 54: goproc.v1.GoProc.Rm:output_type -> goproc.v1.RmResponse
+	23, // 55: goproc.v1.GoProc.RenameTag:output_type -> goproc.v1.RenameTagResponse
+	25, // 56: goproc.v1.GoProc.RenameGroup:output_type -> goproc.v1.RenameGroupResponse
+	27, // 57: goproc.v1.GoProc.Reset:output_type -> goproc.v1.ResetResponse
+	30, // 58: goproc.v1.GoProc.Spawn:output_type -> goproc.v1.SpawnResponse
+	32, // 59: goproc.v1.GoProc.Logs:output_type -> goproc.v1.LogChunk
+	34, // 60: goproc.v1.GoProc.Watch:output_type -> goproc.v1.WatchEvent
+	39, // 61: goproc.v1.GoProc.Stats:output_type -> goproc.v1.StatsResponse
+	15, // 62: goproc.v1.GoProc.Signal:output_type -> goproc.v1.SignalResponse
+	19, // 63: goproc.v1.GoProc.UpdateLabels:output_type -> goproc.v1.UpdateLabelsResponse
+	21, // 64: goproc.v1.GoProc.SetName:output_type -> goproc.v1.SetNameResponse
+	42, // 65: goproc.v1.GoProc.Apply:output_type -> goproc.v1.ApplyResponse
+	45, // 66: goproc.v1.GoProc.History:output_type -> goproc.v1.HistoryResponse
+	47, // 67: goproc.v1.GoProc.Export:output_type -> goproc.v1.ExportResponse
+	50, // 68: goproc.v1.GoProc.Import:output_type -> goproc.v1.ImportResponse
+	50, // [50:69] is the sub-list for method output_type
+	31, // [31:50] is the sub-list for method input_type
+	31, // [31:31] is the sub-list for extension type_name
+	31, // [31:31] is the sub-list for extension extendee
+	0,  // [0:31] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_goproc_v1_goproc_proto_init() }
@@ -1080,7 +3884,7 @@ func file_api_proto_goproc_v1_goproc_proto_init() {
 	if File_api_proto_goproc_v1_goproc_proto != nil {
 		return
 	}
-	file_api_proto_goproc_v1_goproc_proto_msgTypes[7].OneofWrappers = []any{
+	file_api_proto_goproc_v1_goproc_proto_msgTypes[12].OneofWrappers = []any{
 		(*KillRequest_Id)(nil),
 		(*KillRequest_Pid)(nil),
 	}
@@ -1090,7 +3894,7 @@ func file_api_proto_goproc_v1_goproc_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_goproc_v1_goproc_proto_rawDesc), len(file_api_proto_goproc_v1_goproc_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   17,
+			NumMessages:   54,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
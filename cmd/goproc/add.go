@@ -2,7 +2,7 @@ package main
 
 import (
 	"fmt"
-	"os"
+	"io"
 	"strconv"
 	"time"
 
@@ -19,12 +19,16 @@ var (
 	addTags   []string
 	addGroups []string
 	addName   string
+	addAnnots []string
+	addTrack  bool
 )
 
 func init() {
 	cmdAdd.Flags().StringSliceVar(&addTags, "tag", nil, "Tag to assign to the process (repeatable)")
 	cmdAdd.Flags().StringSliceVar(&addGroups, "group", nil, "Group to assign to the process (repeatable)")
 	cmdAdd.Flags().StringVar(&addName, "name", "", "Unique name to assign to the process")
+	cmdAdd.Flags().StringArrayVar(&addAnnots, "annotate", nil, "Annotation KEY=VALUE to store on the entry (repeatable)")
+	cmdAdd.Flags().BoolVar(&addTrack, "track-children", false, "Register processes it forks as child entries linked to this one")
 }
 
 var cmdAdd = &cobra.Command{
@@ -35,25 +39,38 @@ var cmdAdd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		pid, err := strconv.Atoi(args[0])
 		if err != nil {
-			return fmt.Errorf("invalid pid %q", args[0])
+			return usageErrorf("invalid pid %q", args[0])
+		}
+
+		annotations, err := app.ParseAnnotations(addAnnots)
+		if err != nil {
+			return err
 		}
 
 		res, err := controller().Add(cmd.Context(), app.AddParams{
-			PID:     pid,
-			Tags:    addTags,
-			Groups:  addGroups,
-			Name:    addName,
-			Timeout: 2 * time.Second,
+			PID:           pid,
+			Tags:          addTags,
+			Groups:        addGroups,
+			Name:          addName,
+			Annotations:   annotations,
+			TrackChildren: addTrack,
+			Timeout:       2 * time.Second,
 		})
 		if err != nil {
 			return err
 		}
-		if res.AlreadyExists {
-			fmt.Fprintln(os.Stdout, res.ExistingReason)
-			return nil
-		}
-
-		fmt.Fprintf(os.Stdout, "Process %d registered with id %d\n", pid, res.ID)
-		return nil
+		return render(cmd, view{
+			data: res,
+			text: func(w io.Writer) {
+				if res.AlreadyExists {
+					fmt.Fprintln(w, res.ExistingReason)
+					return
+				}
+				fmt.Fprintf(w, "Process %d registered with id %d\n", pid, res.ID)
+			},
+			table: func(bool) ([]string, [][]string) {
+				return []string{"ID", "PID", "ALREADY_EXISTS"}, [][]string{{fmt.Sprint(res.ID), fmt.Sprint(pid), fmt.Sprint(res.AlreadyExists)}}
+			},
+		})
 	},
 }
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"goproc/internal/app"
+	"goproc/internal/manifest"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	applyFile    string
+	applyPrune   bool
+	applyDryRun  bool
+	applyGrace   time.Duration
+	applyTimeout int
+)
+
+func init() {
+	rootCmd.AddCommand(cmdApply)
+	cmdApply.Flags().StringVarP(&applyFile, "filename", "f", "", "Manifest to apply (YAML or JSON; - reads standard input)")
+	cmdApply.Flags().BoolVar(&applyPrune, "prune", false, "Stop and remove processes of this manifest that it no longer declares")
+	cmdApply.Flags().BoolVar(&applyDryRun, "dry-run", false, "Print the plan without changing anything")
+	cmdApply.Flags().DurationVar(&applyGrace, "grace", 5*time.Second, "How long replaced or pruned processes get to exit after SIGTERM before SIGKILL")
+	cmdApply.Flags().IntVar(&applyTimeout, "timeout", 5, "Timeout in seconds for the apply RPC; the grace period is added on top")
+}
+
+var cmdApply = &cobra.Command{
+	Use:   "apply -f <manifest>",
+	Short: "Start, restart and prune processes to match a manifest",
+	Long: "Reads a manifest of named processes and reconciles the daemon with it by name: missing processes are started, " +
+		"processes whose command, env, cwd, restart policy or cgroup changed are restarted, label changes are applied in place, " +
+		"and with --prune, processes the manifest no longer declares are stopped and removed. " +
+		"Only entries started by the same manifest are ever restarted or pruned.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if applyFile == "" {
+			return usageErrorf("a manifest is required (-f <file>, or -f - for standard input)")
+		}
+		m, err := manifest.Load(applyFile)
+		if err != nil {
+			return usageErrorf("%v", err)
+		}
+		res, err := controller().Apply(cmd.Context(), app.ApplyParams{
+			Manifest:  m.Name,
+			Processes: m.SpawnParams(),
+			Prune:     applyPrune,
+			DryRun:    applyDryRun,
+			Grace:     applyGrace,
+			Timeout:   time.Duration(applyTimeout) * time.Second,
+		})
+		// Failed actions are part of the result; print them before the error.
+		if len(res.Actions) == 0 && err != nil {
+			return err
+		}
+		res.Actions = nonNil(res.Actions)
+		if rerr := render(cmd, view{
+			data:  res,
+			items: res.Actions,
+			text:  func(w io.Writer) { writeApplyText(w, m.Name, res) },
+			table: func(bool) ([]string, [][]string) { return applyTable(res) },
+		}); rerr != nil && err == nil {
+			err = rerr
+		}
+		return err
+	},
+}
+
+func writeApplyText(w io.Writer, name string, res app.ApplyResult) {
+	if len(res.Actions) == 0 {
+		fmt.Fprintf(w, "Manifest %s declares no processes\n", name)
+		return
+	}
+	for _, act := range res.Actions {
+		fmt.Fprintf(w, "%-9s %s", act.Action, act.Name)
+		if act.Reason != "" {
+			fmt.Fprintf(w, " (%s)", act.Reason)
+		}
+		if act.ID != 0 {
+			fmt.Fprintf(w, " [id=%d] pid=%d", act.ID, act.PID)
+		}
+		if act.Error != "" {
+			fmt.Fprintf(w, ": %s", act.Error)
+		}
+		fmt.Fprintln(w)
+	}
+	if res.DryRun {
+		fmt.Fprintln(w, "Dry run: nothing was changed")
+	}
+}
+
+func applyTable(res app.ApplyResult) ([]string, [][]string) {
+	rows := make([][]string, 0, len(res.Actions))
+	for _, act := range res.Actions {
+		id, pid := "-", "-"
+		if act.ID != 0 {
+			id, pid = fmt.Sprint(act.ID), fmt.Sprint(act.PID)
+		}
+		rows = append(rows, []string{act.Action, act.Name, id, pid, dashIfEmpty(act.Reason), dashIfEmpty(act.Error)})
+	}
+	return []string{"ACTION", "NAME", "ID", "PID", "REASON", "ERROR"}, rows
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"goproc/internal/app"
+	"goproc/internal/query"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Exit codes are part of the CLI contract; scripts may rely on them.
+const (
+	exitOK          = 0
+	exitError       = 1 // any failure not listed below
+	exitUsage       = 2 // invalid flags, arguments, selectors or queries, or an ambiguous selection
+	exitUnavailable = 3 // the daemon is not running or cannot be reached
+	exitNotFound    = 4 // the selected process does not exist
+	exitConflict    = 5 // the PID or name is already registered
+	exitPartial     = 6 // a bulk operation failed for some processes
+	exitTimeout     = 7 // the daemon did not answer in time
+)
+
+// usageError marks errors caused by how the command was invoked.
+type usageError struct{ err error }
+
+func (e *usageError) Error() string { return e.err.Error() }
+func (e *usageError) Unwrap() error { return e.err }
+
+func usageErrorf(format string, args ...any) error {
+	return &usageError{err: fmt.Errorf(format, args...)}
+}
+
+// exitCode maps an error returned by a command to its exit code.
+func exitCode(err error) int {
+	var usage *usageError
+	var qerr *query.Error
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &usage), errors.As(err, &qerr),
+		errors.Is(err, app.ErrInvalidParams), errors.Is(err, app.ErrAmbiguous):
+		return exitUsage
+	case errors.Is(err, app.ErrDaemonNotRunning):
+		return exitUnavailable
+	case errors.Is(err, app.ErrNoMatch):
+		return exitNotFound
+	case errors.Is(err, app.ErrPartialFailure):
+		return exitPartial
+	case errors.Is(err, context.DeadlineExceeded):
+		return exitTimeout
+	}
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return exitUsage
+	case codes.Unavailable:
+		return exitUnavailable
+	case codes.NotFound:
+		return exitNotFound
+	case codes.AlreadyExists:
+		return exitConflict
+	case codes.DeadlineExceeded:
+		return exitTimeout
+	}
+	return exitError
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"goproc/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportTags    []string
+	exportGroups  []string
+	exportQuery   string
+	exportAlive   bool
+	exportTimeout int
+)
+
+func init() {
+	rootCmd.AddCommand(cmdExport)
+	cmdExport.Flags().StringSliceVar(&exportTags, "tag", nil, "Export processes that have any of these tags")
+	cmdExport.Flags().StringSliceVar(&exportGroups, "group", nil, "Export processes that are in any of these groups")
+	cmdExport.Flags().StringVarP(&exportQuery, "query", "q", "", "Query expression, e.g. 'group:prod and name~^api-'")
+	cmdExport.Flags().BoolVar(&exportAlive, "alive", false, "Only export processes currently considered alive")
+	cmdExport.Flags().IntVar(&exportTimeout, "timeout", 3, "Timeout in seconds for contacting the daemon")
+}
+
+var cmdExport = &cobra.Command{
+	Use:   "export [id|name]",
+	Short: "Write registry entries as a JSON document for import",
+	Long: "Writes the matching entries (every entry without selectors) to standard output as a JSON document that `goproc import` reads, " +
+		"on this machine or another one. It holds everything the daemon persists about each entry: labels, spawn spec, restart policy, cgroup limits and last exit. " +
+		"The document is always JSON; --output does not apply.",
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var filters app.ListFilters
+		if len(args) == 1 {
+			filters = selectorFilters(args[0])
+		}
+		filters.TagsAny = exportTags
+		filters.GroupsAny = exportGroups
+		filters.Query = exportQuery
+		filters.AliveOnly = exportAlive
+
+		res, err := controller().Export(cmd.Context(), app.ExportParams{
+			Filters: filters,
+			Timeout: time.Duration(exportTimeout) * time.Second,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := cmd.OutOrStdout().Write(res.Document); err != nil {
+			return fmt.Errorf("write export: %w", err)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"goproc/internal/app"
+	"goproc/internal/units"
+)
+
+// formatAnnotations renders annotations as sorted key=value pairs.
+func formatAnnotations(annotations map[string]string) string {
+	pairs := make([]string, 0, len(annotations))
+	for k, v := range annotations {
+		pairs = append(pairs, k+"="+v)
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// formatCgroup renders a cgroup leaf and the limits set on it.
+func formatCgroup(cg *app.Cgroup) string {
+	limits := make([]string, 0, 3)
+	if cg.MemoryMax > 0 {
+		limits = append(limits, "memory="+units.FormatBytes(cg.MemoryMax))
+	}
+	if cg.CPUMax > 0 {
+		limits = append(limits, "cpu="+strconv.FormatFloat(cg.CPUMax, 'f', -1, 64))
+	}
+	if cg.PIDsMax > 0 {
+		limits = append(limits, "pids="+strconv.Itoa(cg.PIDsMax))
+	}
+	return fmt.Sprintf("cgroup=%s limits=[%s]", cg.Path, strings.Join(limits, ","))
+}
+
+// formatMetrics renders the resource columns shown by list --wide.
+func formatMetrics(m *app.Metrics) string {
+	if m == nil {
+		return "cpu=- rss=- vms=- threads=- fds=- read=- write=-"
+	}
+	return fmt.Sprintf(
+		"cpu=%.1f%% rss=%s vms=%s threads=%d fds=%d read=%s write=%s",
+		m.CPUPercent,
+		units.FormatBytes(m.RSS),
+		units.FormatBytes(m.VMS),
+		m.Threads,
+		m.FDs,
+		units.FormatBytes(m.ReadBytes),
+		units.FormatBytes(m.WriteBytes),
+	)
+}
+
+// sparkline draws values as block characters, averaging them into at most
+// width buckets. The scale runs from the series minimum to its maximum.
+func sparkline(values []float64, width int) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	if len(values) > width {
+		buckets := make([]float64, width)
+		for i := range buckets {
+			lo, hi := i*len(values)/width, (i+1)*len(values)/width
+			var sum float64
+			for _, v := range values[lo:hi] {
+				sum += v
+			}
+			buckets[i] = sum / float64(hi-lo)
+		}
+		values = buckets
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	ticks := []rune("▁▂▃▄▅▆▇█")
+	out := make([]rune, len(values))
+	for i, v := range values {
+		level := 0
+		if hi > lo {
+			level = int((v - lo) / (hi - lo) * float64(len(ticks)-1))
+		}
+		out[i] = ticks[level]
+	}
+	return string(out)
+}
@@ -2,7 +2,7 @@ package main
 
 import (
 	"fmt"
-	"os"
+	"io"
 	"strings"
 	"time"
 
@@ -36,34 +36,37 @@ var cmdGroup = &cobra.Command{
 			return err
 		}
 
-		if res.RenameInfo != nil {
-			fmt.Fprintf(os.Stdout, "Renamed group %q -> %q on %d process(es)\n", res.RenameInfo.From, res.RenameInfo.To, res.RenameInfo.Updated)
-		}
-
-		if len(res.Processes) == 0 {
-			if res.Message != "" {
-				fmt.Fprintln(os.Stdout, res.Message)
-			}
-			return nil
-		}
-
-		for _, proc := range res.Processes {
-			name := proc.Name
-			if name == "" {
-				name = "-"
-			}
-			fmt.Fprintf(
-				os.Stdout,
-				"[id=%d] pid=%d name=%s alive=%t cmd=%s tags=[%s] groups=[%s]\n",
-				proc.ID,
-				proc.PID,
-				name,
-				proc.Alive,
-				proc.Cmd,
-				strings.Join(proc.Tags, ","),
-				strings.Join(proc.Groups, ","),
-			)
-		}
-		return nil
+		res.Processes = nonNil(res.Processes)
+		return render(cmd, view{
+			data:  res,
+			items: res.Processes,
+			text: func(w io.Writer) {
+				if res.RenameInfo != nil {
+					fmt.Fprintf(w, "Renamed group %q -> %q on %d process(es)\n", res.RenameInfo.From, res.RenameInfo.To, res.RenameInfo.Updated)
+				}
+				if len(res.Processes) == 0 {
+					if res.Message != "" {
+						fmt.Fprintln(w, res.Message)
+					}
+					return
+				}
+				for _, proc := range res.Processes {
+					fmt.Fprintf(
+						w,
+						"[id=%d] pid=%d name=%s alive=%t cmd=%s tags=[%s] groups=[%s]\n",
+						proc.ID,
+						proc.PID,
+						dashIfEmpty(proc.Name),
+						proc.Alive,
+						proc.Cmd,
+						strings.Join(proc.Tags, ","),
+						strings.Join(proc.Groups, ","),
+					)
+				}
+			},
+			table: func(wide bool) ([]string, [][]string) {
+				return processTable(res.Processes, wide)
+			},
+		})
 	},
 }
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"goproc/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyTags    []string
+	historyGroups  []string
+	historyQuery   string
+	historySince   string
+	historyReasons []string
+	historyLimit   int
+	historyTimeout int
+)
+
+func init() {
+	rootCmd.AddCommand(cmdHistory)
+	cmdHistory.Flags().StringSliceVar(&historyTags, "tag", nil, "Match entries that had any of these tags")
+	cmdHistory.Flags().StringSliceVar(&historyGroups, "group", nil, "Match entries that were in any of these groups")
+	cmdHistory.Flags().StringVarP(&historyQuery, "query", "q", "", "Query expression matched against the final state, e.g. 'group:prod and name~^api-'")
+	cmdHistory.Flags().StringVar(&historySince, "since", "", "Only entries removed within this duration (e.g. 24h) or since this time (RFC 3339 or YYYY-MM-DD)")
+	cmdHistory.Flags().StringSliceVar(&historyReasons, "reason", nil, "Only entries removed for these reasons: rm, kill, reset, gc, import, or died for processes that died")
+	cmdHistory.Flags().IntVar(&historyLimit, "limit", 0, "Show at most this many of the newest entries (0 = all)")
+	cmdHistory.Flags().IntVar(&historyTimeout, "timeout", 3, "Timeout in seconds for contacting the daemon")
+}
+
+var cmdHistory = &cobra.Command{
+	Use:   "history [id|name]",
+	Short: "Show processes that were removed from the registry or died",
+	Long: "Lists the final state of removed entries, newest first: when and why they were removed (rm, kill, reset, gc for entries the daemon dropped itself, or import for entries an import replaced) and how the process ended. " +
+		"Processes that die are listed too, with reason died, while their entry stays registered. " +
+		"Selectors match the state the entry had when it was removed. The daemon keeps entries for history_max_age, up to history_max_entries.",
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		since, err := parseSince(historySince, time.Now())
+		if err != nil {
+			return usageErrorf("invalid --since: %v", err)
+		}
+		var filters app.ListFilters
+		if len(args) == 1 {
+			filters = selectorFilters(args[0])
+		}
+		filters.TagsAny = historyTags
+		filters.GroupsAny = historyGroups
+		filters.Query = historyQuery
+
+		entries, err := controller().History(cmd.Context(), app.HistoryParams{
+			Filters: filters,
+			Since:   since,
+			Reasons: historyReasons,
+			Limit:   historyLimit,
+			Timeout: time.Duration(historyTimeout) * time.Second,
+		})
+		if err != nil {
+			return err
+		}
+		entries = nonNil(entries)
+		return render(cmd, view{
+			data:  entries,
+			items: entries,
+			text: func(w io.Writer) {
+				if len(entries) == 0 {
+					fmt.Fprintln(w, "No removed processes in the history")
+					return
+				}
+				for _, e := range entries {
+					writeHistoryLine(w, e)
+				}
+			},
+			table: func(bool) ([]string, [][]string) {
+				rows := make([][]string, 0, len(entries))
+				for _, e := range entries {
+					rows = append(rows, []string{
+						e.RemovedAt.Format(time.RFC3339),
+						e.Reason,
+						strconv.FormatUint(e.Process.ID, 10),
+						strconv.Itoa(int(e.Process.PID)),
+						dashIfEmpty(e.Process.Name),
+						historyExit(e.Process),
+						strings.Join(e.Process.Groups, ","),
+						e.Process.Cmd,
+					})
+				}
+				return []string{"REMOVED", "REASON", "ID", "PID", "NAME", "EXIT", "GROUPS", "CMD"}, rows
+			},
+		})
+	},
+}
+
+func writeHistoryLine(w io.Writer, e app.HistoryEntry) {
+	p := e.Process
+	fmt.Fprintf(w, "%s %-5s [id=%d] pid=%d name=%s cmd=%s tags=[%s] groups=[%s] exit=%s",
+		e.RemovedAt.Format(time.RFC3339),
+		e.Reason,
+		p.ID,
+		p.PID,
+		dashIfEmpty(p.Name),
+		p.Cmd,
+		strings.Join(p.Tags, ","),
+		strings.Join(p.Groups, ","),
+		historyExit(p),
+	)
+	if p.Exit != nil {
+		fmt.Fprintf(w, " exited_at=%s", p.Exit.At.Format(time.RFC3339))
+	}
+	if p.Manifest != "" {
+		fmt.Fprintf(w, " manifest=%s", p.Manifest)
+	}
+	fmt.Fprintln(w)
+}
+
+// historyExit describes how a removed process ended; "running" means it was
+// still alive when its entry was removed.
+func historyExit(p app.Process) string {
+	switch {
+	case p.Alive:
+		return "running"
+	case p.Exit != nil:
+		return p.Exit.String()
+	default:
+		return "-"
+	}
+}
+
+// parseSince accepts a duration before now, an RFC 3339 time or a date.
+// Empty means no bound.
+func parseSince(raw string, now time.Time) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(raw); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("duration %q must not be negative", raw)
+		}
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, raw, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is neither a duration nor a time", raw)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"goproc/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	importMerge      bool
+	importReplace    bool
+	importOnConflict string
+	importReadopt    bool
+	importRespawn    bool
+	importTimeout    int
+)
+
+func init() {
+	rootCmd.AddCommand(cmdImport)
+	cmdImport.Flags().BoolVar(&importMerge, "merge", false, "Add the entries to the existing ones (the default)")
+	cmdImport.Flags().BoolVar(&importReplace, "replace", false, "Remove every existing entry first; their processes keep running, untracked")
+	cmdImport.Flags().StringVar(&importOnConflict, "on-conflict", "rename", "What to do with an entry whose name is taken: rename (to name-2, ...), skip, replace (remove the entry holding it) or fail (import nothing)")
+	cmdImport.Flags().BoolVar(&importReadopt, "readopt", false, "Track entries whose process is still running on this machine again")
+	cmdImport.Flags().BoolVar(&importRespawn, "respawn", false, "Start the commands of entries the daemon had started that are not running")
+	cmdImport.Flags().IntVar(&importTimeout, "timeout", 5, "Timeout in seconds for the import RPC")
+}
+
+var cmdImport = &cobra.Command{
+	Use:   "import <file|->",
+	Short: "Add the entries of a `goproc export` document to the registry",
+	Long: "Reads a document written by `goproc export` (- reads standard input) and adds its entries under new IDs; IDs handed out since the last reset are never reused, " +
+		"and links between parent and child entries follow the new IDs. " +
+		"Imported entries are dead and detached from their PIDs, which belonged to another machine or run, unless --readopt finds the very same process still running. " +
+		"With --respawn, entries started with run or apply are started again, under their restart policy. " +
+		"Documents from older versions are migrated.",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if importMerge && importReplace {
+			return usageErrorf("--merge and --replace are mutually exclusive")
+		}
+		var (
+			doc []byte
+			err error
+		)
+		if args[0] == "-" {
+			doc, err = io.ReadAll(cmd.InOrStdin())
+		} else {
+			doc, err = os.ReadFile(args[0])
+		}
+		if err != nil {
+			return usageErrorf("read import document: %v", err)
+		}
+		res, err := controller().Import(cmd.Context(), app.ImportParams{
+			Document:   doc,
+			Replace:    importReplace,
+			OnConflict: importOnConflict,
+			Readopt:    importReadopt,
+			Respawn:    importRespawn,
+			Timeout:    time.Duration(importTimeout) * time.Second,
+		})
+		// Failed respawns are part of the result; print them before the error.
+		if len(res.Entries) == 0 && err != nil {
+			return err
+		}
+		res.Entries = nonNil(res.Entries)
+		if rerr := render(cmd, view{
+			data:  res,
+			items: res.Entries,
+			text:  func(w io.Writer) { writeImportText(w, res) },
+			table: func(bool) ([]string, [][]string) { return importTable(res) },
+		}); rerr != nil && err == nil {
+			err = rerr
+		}
+		return err
+	},
+}
+
+func writeImportText(w io.Writer, res app.ImportResult) {
+	if res.Removed > 0 {
+		fmt.Fprintf(w, "Removed %d existing processes\n", res.Removed)
+	}
+	if len(res.Entries) == 0 {
+		fmt.Fprintln(w, "The document holds no processes")
+		return
+	}
+	for _, e := range res.Entries {
+		fmt.Fprintf(w, "%-8s %s [from id=%d]", e.Action, dashIfEmpty(e.Name), e.FromID)
+		if e.ID != 0 {
+			fmt.Fprintf(w, " [id=%d] pid=%d %s", e.ID, e.PID, importState(e))
+		}
+		if e.Note != "" {
+			fmt.Fprintf(w, " (%s)", e.Note)
+		}
+		if e.Error != "" {
+			fmt.Fprintf(w, ": respawn failed: %s", e.Error)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func importTable(res app.ImportResult) ([]string, [][]string) {
+	rows := make([][]string, 0, len(res.Entries))
+	for _, e := range res.Entries {
+		id, pid, state := "-", "-", "-"
+		if e.ID != 0 {
+			id, pid, state = strconv.FormatUint(e.ID, 10), strconv.Itoa(e.PID), importState(e)
+		}
+		rows = append(rows, []string{
+			e.Action,
+			strconv.FormatUint(e.FromID, 10),
+			id,
+			dashIfEmpty(e.Name),
+			pid,
+			state,
+			dashIfEmpty(e.Note),
+			dashIfEmpty(e.Error),
+		})
+	}
+	return []string{"ACTION", "FROM", "ID", "NAME", "PID", "STATE", "NOTE", "ERROR"}, rows
+}
+
+// importState describes the process of an imported entry.
+func importState(e app.ImportEntry) string {
+	switch {
+	case e.Respawned:
+		return "respawned"
+	case e.Alive:
+		return "readopted"
+	default:
+		return "dead"
+	}
+}
@@ -2,7 +2,7 @@ package main
 
 import (
 	"fmt"
-	"os"
+	"io"
 	"time"
 
 	"goproc/internal/app"
@@ -11,13 +11,20 @@ import (
 )
 
 var (
-	killTags    []string
-	killGroups  []string
-	killNames   []string
-	killPIDs    []int
-	killIDs     []int
-	killAll     bool
-	killTimeout int
+	killTags        []string
+	killGroups      []string
+	killNames       []string
+	killPIDs        []int
+	killIDs         []int
+	killQuery       string
+	killNameGlobs   []string
+	killTagGlobs    []string
+	killSearchRegex string
+	killAll         bool
+	killTimeout     int
+	killSignal      string
+	killGrace       time.Duration
+	killNoGroup     bool
 )
 
 func init() {
@@ -27,14 +34,21 @@ func init() {
 	cmdKill.Flags().StringSliceVar(&killNames, "name", nil, "Match processes with these exact names")
 	cmdKill.Flags().IntSliceVar(&killPIDs, "pid", nil, "Filter by PID (repeatable)")
 	cmdKill.Flags().IntSliceVar(&killIDs, "id", nil, "Filter by registry ID (repeatable)")
+	cmdKill.Flags().StringArrayVar(&killNameGlobs, "name-glob", nil, "Match names against a glob such as 'worker-*' (repeatable, any may match)")
+	cmdKill.Flags().StringArrayVar(&killTagGlobs, "tag-glob", nil, "Match processes with a tag matching a glob such as 'env-*' (repeatable, any may match)")
+	cmdKill.Flags().StringVar(&killSearchRegex, "search-regex", "", "Regular expression to match against the command, e.g. 'python .*manage.py'")
+	cmdKill.Flags().StringVarP(&killQuery, "query", "q", "", "Query expression, e.g. 'tag:db and (group:prod or name~^api-) and rss>500MB'")
 	cmdKill.Flags().BoolVar(&killAll, "all", false, "Kill every process that matches the selector")
 	cmdKill.Flags().IntVar(&killTimeout, "timeout", 5, "Timeout in seconds for kill/remove operations")
+	cmdKill.Flags().StringVar(&killSignal, "signal", "TERM", "Signal to send first (name like TERM/INT/HUP or a number)")
+	cmdKill.Flags().DurationVar(&killGrace, "grace", 5*time.Second, "How long to wait for exit before sending SIGKILL (0 sends the signal without waiting out a grace period)")
+	cmdKill.Flags().BoolVar(&killNoGroup, "no-group", false, "Signal only the process instead of its whole process group")
 }
 
 var cmdKill = &cobra.Command{
 	Use:   "kill",
 	Short: "Terminate processes managed by the daemon",
-	Long:  "Selects processes via the same filters as `list` and sends a SIGTERM (via the daemon) before removing them from the registry.",
+	Long:  "Selects processes via the same filters as `list` and asks the daemon to send --signal (SIGTERM by default) to each process group. The daemon waits up to --grace for the processes to exit and escalates to SIGKILL if they do not. Processes that exited are removed from the registry; survivors are kept.",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		res, err := controller().Kill(cmd.Context(), app.KillParams{
 			Filters: app.ListFilters{
@@ -43,31 +57,74 @@ var cmdKill = &cobra.Command{
 				Names:     killNames,
 				PIDs:      killPIDs,
 				IDs:       killIDs,
+				Query:     killQuery,
+				NameGlobs: killNameGlobs,
+				TagGlobs:  killTagGlobs,
+				CmdRegex:  killSearchRegex,
 			},
 			AllowAll:        killAll,
 			Timeout:         time.Duration(killTimeout) * time.Second,
 			RequireSelector: true,
+			Signal:          killSignal,
+			Grace:           killGrace,
+			Escalate:        killGrace > 0,
+			NoGroup:         killNoGroup,
 		})
-		if res.Message != "" {
-			fmt.Fprintln(os.Stdout, res.Message)
-		}
-		for _, event := range res.Events {
-			name := event.Proc.Name
-			if name == "" {
-				name = "-"
+		// Partial failures still carry per-process events worth printing
+		// before the error.
+		if res.Message != "" || len(res.Events) > 0 {
+			res.Events = nonNil(res.Events)
+			if rerr := render(cmd, view{
+				data:  res,
+				items: res.Events,
+				text:  func(w io.Writer) { writeKillText(w, res) },
+				table: func(bool) ([]string, [][]string) { return killTable(res) },
+			}); rerr != nil && err == nil {
+				err = rerr
 			}
-			switch event.Kind {
-			case "success":
-				fmt.Fprintf(os.Stdout, "Killed and removed [id=%d] pid=%d name=%s\n", event.Proc.ID, event.Proc.PID, name)
-			case "kill_failure":
-				fmt.Fprintf(os.Stdout, "Failed to kill [id=%d] pid=%d name=%s: %v\n", event.Proc.ID, event.Proc.PID, name, event.Err)
-			case "remove_failure":
-				fmt.Fprintf(os.Stdout, "Killed [id=%d] pid=%d name=%s but failed to remove from registry: %v\n", event.Proc.ID, event.Proc.PID, name, event.Err)
+		}
+		return err
+	},
+}
+
+func writeKillText(w io.Writer, res app.KillResult) {
+	if res.Message != "" {
+		fmt.Fprintln(w, res.Message)
+	}
+	for _, event := range res.Events {
+		name := dashIfEmpty(event.Proc.Name)
+		switch event.Kind {
+		case "success":
+			how := ""
+			if event.Outcome == app.KillOutcomeEscalated {
+				how = " (escalated to SIGKILL)"
 			}
+			fmt.Fprintf(w, "Killed and removed [id=%d] pid=%d name=%s%s\n", event.Proc.ID, event.Proc.PID, name, how)
+		case "still_running":
+			fmt.Fprintf(w, "Signaled [id=%d] pid=%d name=%s but it is still running; kept in registry\n", event.Proc.ID, event.Proc.PID, name)
+		case "kill_failure":
+			fmt.Fprintf(w, "Failed to kill [id=%d] pid=%d name=%s: %v\n", event.Proc.ID, event.Proc.PID, name, event.Err)
+		case "remove_failure":
+			fmt.Fprintf(w, "Killed [id=%d] pid=%d name=%s but failed to remove from registry: %v\n", event.Proc.ID, event.Proc.PID, name, event.Err)
 		}
-		if err != nil {
-			return err
+	}
+}
+
+func killTable(res app.KillResult) ([]string, [][]string) {
+	rows := make([][]string, 0, len(res.Events))
+	for _, event := range res.Events {
+		errText := "-"
+		if event.Err != nil {
+			errText = event.Err.Error()
 		}
-		return nil
-	},
+		rows = append(rows, []string{
+			fmt.Sprint(event.Proc.ID),
+			fmt.Sprint(event.Proc.PID),
+			dashIfEmpty(event.Proc.Name),
+			event.Kind,
+			dashIfEmpty(event.Outcome),
+			errText,
+		})
+	}
+	return []string{"ID", "PID", "NAME", "RESULT", "OUTCOME", "ERROR"}, rows
 }
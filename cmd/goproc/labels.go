@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"goproc/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+// labelSelectors holds the selector flags of one label subcommand.
+type labelSelectors struct {
+	tags    []string
+	groups  []string
+	names   []string
+	pids    []int
+	ids     []int
+	query   string
+	all     bool
+	timeout int
+}
+
+func init() {
+	cmdTag.AddCommand(labelCommand("tag", "add"), labelCommand("tag", "rm"))
+	cmdGroup.AddCommand(labelCommand("group", "add"), labelCommand("group", "rm"))
+}
+
+// labelCommand builds `goproc <kind> add|rm <label>... [selectors]`, where
+// kind is "tag" or "group".
+func labelCommand(kind, verb string) *cobra.Command {
+	var sel labelSelectors
+	action, preposition := "Add", "to"
+	if verb == "rm" {
+		action, preposition = "Remove", "from"
+	}
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s <%s>...", verb, kind),
+		Short: fmt.Sprintf("%s %ss %s the selected processes", action, kind, preposition),
+		Long: fmt.Sprintf("%s one or more %ss %s every process matching the selectors (same flags as `kill`). "+
+			"Selectors are required unless --all is given, which applies the change to every registered process.", action, kind, preposition),
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var changes app.LabelChanges
+			switch kind + " " + verb {
+			case "tag add":
+				changes.AddTags = args
+			case "tag rm":
+				changes.RemoveTags = args
+			case "group add":
+				changes.AddGroups = args
+			default:
+				changes.RemoveGroups = args
+			}
+			res, err := controller().UpdateLabels(cmd.Context(), app.UpdateLabelsParams{
+				Filters: app.ListFilters{
+					TagsAny:   sel.tags,
+					GroupsAny: sel.groups,
+					Names:     sel.names,
+					PIDs:      sel.pids,
+					IDs:       sel.ids,
+					Query:     sel.query,
+				},
+				AllowAll: sel.all,
+				Changes:  changes,
+				Timeout:  time.Duration(sel.timeout) * time.Second,
+			})
+			if err != nil {
+				return err
+			}
+			res.Processes = nonNil(res.Processes)
+			return render(cmd, view{
+				data:  res,
+				items: res.Processes,
+				text: func(w io.Writer) {
+					if res.Message != "" {
+						fmt.Fprintln(w, res.Message)
+						return
+					}
+					fmt.Fprintf(w, "Updated %d of %d matching process(es)\n", res.Updated, len(res.Processes))
+					for _, proc := range res.Processes {
+						fmt.Fprintf(
+							w,
+							"[id=%d] pid=%d name=%s tags=[%s] groups=[%s]\n",
+							proc.ID,
+							proc.PID,
+							dashIfEmpty(proc.Name),
+							strings.Join(proc.Tags, ","),
+							strings.Join(proc.Groups, ","),
+						)
+					}
+				},
+				table: func(wide bool) ([]string, [][]string) {
+					return processTable(res.Processes, wide)
+				},
+			})
+		},
+	}
+	cmd.Flags().StringSliceVar(&sel.tags, "tag", nil, "Match processes that have any of these tags")
+	cmd.Flags().StringSliceVar(&sel.groups, "group", nil, "Match processes that belong to any of these groups")
+	cmd.Flags().StringSliceVar(&sel.names, "name", nil, "Match processes with these exact names")
+	cmd.Flags().IntSliceVar(&sel.pids, "pid", nil, "Filter by PID (repeatable)")
+	cmd.Flags().IntSliceVar(&sel.ids, "id", nil, "Filter by registry ID (repeatable)")
+	cmd.Flags().StringVarP(&sel.query, "query", "q", "", "Query expression, e.g. 'tag:db and (group:prod or name~^api-) and rss>500MB'")
+	cmd.Flags().BoolVar(&sel.all, "all", false, "Apply to every registered process when no selector is given")
+	cmd.Flags().IntVar(&sel.timeout, "timeout", 3, "Timeout in seconds for daemon request")
+	return cmd
+}
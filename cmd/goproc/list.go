@@ -2,7 +2,7 @@ package main
 
 import (
 	"fmt"
-	"os"
+	"io"
 	"strings"
 	"time"
 
@@ -12,15 +12,25 @@ import (
 )
 
 var (
-	listTagsAny    []string
-	listTagsAll    []string
-	listGroupsAny  []string
-	listGroupsAll  []string
-	listNames      []string
-	listAliveOnly  bool
-	listPIDs       []int
-	listIDs        []int
-	listTextSearch string
+	listTagsAny     []string
+	listTagsAll     []string
+	listGroupsAny   []string
+	listGroupsAll   []string
+	listNames       []string
+	listAliveOnly   bool
+	listPIDs        []int
+	listIDs         []int
+	listTextSearch  string
+	listWide        bool
+	listAnnots      []string
+	listQuery       string
+	listNameGlobs   []string
+	listTagGlobs    []string
+	listSearchRegex string
+	listSort        string
+	listDesc        bool
+	listLimit       int
+	listPage        string
 )
 
 func init() {
@@ -34,6 +44,16 @@ func init() {
 	cmdList.Flags().IntSliceVar(&listPIDs, "pid", nil, "Filter by PID (repeatable)")
 	cmdList.Flags().IntSliceVar(&listIDs, "id", nil, "Filter by registry ID (repeatable)")
 	cmdList.Flags().StringVar(&listTextSearch, "search", "", "Substring to match against command")
+	cmdList.Flags().StringArrayVar(&listAnnots, "annotation", nil, "Match annotations: KEY (present), KEY=VALUE, or KEY=PREFIX* (repeatable, all must match)")
+	cmdList.Flags().StringArrayVar(&listNameGlobs, "name-glob", nil, "Match names against a glob such as 'worker-*' (repeatable, any may match)")
+	cmdList.Flags().StringArrayVar(&listTagGlobs, "tag-glob", nil, "Match processes with a tag matching a glob such as 'env-*' (repeatable, any may match)")
+	cmdList.Flags().StringVar(&listSearchRegex, "search-regex", "", "Regular expression to match against the command, e.g. 'python .*manage.py'")
+	cmdList.Flags().StringVarP(&listQuery, "query", "q", "", "Query expression, e.g. 'tag:db and (group:prod or name~^api-) and rss>500MB'")
+	cmdList.Flags().StringVar(&listSort, "sort", "id", "Sort by id, pid, name, added_at, last_seen, cpu, or rss")
+	cmdList.Flags().BoolVar(&listDesc, "desc", false, "Sort in descending order")
+	cmdList.Flags().IntVar(&listLimit, "limit", 0, "Show at most this many processes (0 = all)")
+	cmdList.Flags().StringVar(&listPage, "page", "", "Continue from the page token printed by a previous --limit listing")
+	cmdList.Flags().BoolVar(&listWide, "wide", false, "Also show CPU, memory, thread, FD and IO metrics")
 }
 
 var cmdList = &cobra.Command{
@@ -41,8 +61,12 @@ var cmdList = &cobra.Command{
 	Short: "List all processes managed by the daemon",
 	Long:  `Fetches the process registry from the daemon via gRPC.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		procs, err := controller().List(cmd.Context(), app.ListParams{
-			Timeout: 3 * time.Second,
+		page, err := controller().ListPage(cmd.Context(), app.ListParams{
+			Timeout:   3 * time.Second,
+			Sort:      listSort,
+			Desc:      listDesc,
+			Limit:     listLimit,
+			PageToken: listPage,
 			Filters: app.ListFilters{
 				TagsAny:    listTagsAny,
 				TagsAll:    listTagsAll,
@@ -53,34 +77,82 @@ var cmdList = &cobra.Command{
 				TextSearch: listTextSearch,
 				PIDs:       listPIDs,
 				IDs:        listIDs,
+
+				Annotations: listAnnots,
+				Query:       listQuery,
+				NameGlobs:   listNameGlobs,
+				TagGlobs:    listTagGlobs,
+				CmdRegex:    listSearchRegex,
 			},
 		})
 		if err != nil {
 			return err
 		}
+		procs := nonNil(page.Processes)
 
-		if len(procs) == 0 {
-			fmt.Fprintln(os.Stdout, "No processes registered")
-			return nil
+		err = render(cmd, view{
+			data:  procs,
+			items: procs,
+			text: func(w io.Writer) {
+				if len(procs) == 0 {
+					fmt.Fprintln(w, "No processes registered")
+					return
+				}
+				for _, proc := range procs {
+					writeListLine(w, proc)
+				}
+			},
+			table: func(wide bool) ([]string, [][]string) {
+				return processTable(procs, wide || listWide)
+			},
+		})
+		if err != nil {
+			return err
 		}
-
-		for _, proc := range procs {
-			name := proc.Name
-			if name == "" {
-				name = "-"
+		if page.NextPageToken != "" {
+			// Keep stdout parseable in the machine formats.
+			footer := cmd.OutOrStdout()
+			if machineOutput() {
+				footer = cmd.ErrOrStderr()
 			}
-			fmt.Fprintf(
-				os.Stdout,
-				"[id=%d] pid=%d name=%s alive=%t cmd=%s tags=[%s] groups=[%s]\n",
-				proc.ID,
-				proc.PID,
-				name,
-				proc.Alive,
-				proc.Cmd,
-				strings.Join(proc.Tags, ","),
-				strings.Join(proc.Groups, ","),
-			)
+			fmt.Fprintf(footer, "Showing %d of %d processes; next page: --page %s\n", len(procs), page.Total, page.NextPageToken)
 		}
 		return nil
 	},
 }
+
+func writeListLine(w io.Writer, proc app.Process) {
+	fmt.Fprintf(
+		w,
+		"[id=%d] pid=%d name=%s alive=%t cmd=%s tags=[%s] groups=[%s]",
+		proc.ID,
+		proc.PID,
+		dashIfEmpty(proc.Name),
+		proc.Alive,
+		proc.Cmd,
+		strings.Join(proc.Tags, ","),
+		strings.Join(proc.Groups, ","),
+	)
+	if len(proc.Annotations) > 0 {
+		fmt.Fprintf(w, " annotations=[%s]", formatAnnotations(proc.Annotations))
+	}
+	if proc.ParentID != 0 {
+		fmt.Fprintf(w, " parent=%d", proc.ParentID)
+	}
+	if proc.Cgroup != nil {
+		fmt.Fprintf(w, " %s", formatCgroup(proc.Cgroup))
+	}
+	if proc.Manifest != "" {
+		fmt.Fprintf(w, " manifest=%s", proc.Manifest)
+	}
+	if proc.Restart != nil && proc.Restart.Mode != "" && proc.Restart.Mode != "never" {
+		fmt.Fprintf(w, " restart=%s restarts=%d", proc.Restart.Mode, proc.Restarts)
+	}
+	if !proc.Alive && proc.Exit != nil {
+		fmt.Fprintf(w, " exit=%s exited_at=%s", proc.Exit.String(), proc.Exit.At.Format(time.RFC3339))
+	}
+	if listWide {
+		fmt.Fprintf(w, " %s", formatMetrics(proc.Metrics))
+	}
+	fmt.Fprintln(w)
+}
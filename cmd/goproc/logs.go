@@ -0,0 +1,46 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"goproc/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	logsFollow  bool
+	logsTail    int
+	logsStderr  bool
+	logsTimeout int
+)
+
+func init() {
+	rootCmd.AddCommand(cmdLogs)
+	cmdLogs.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Keep streaming new output until interrupted")
+	cmdLogs.Flags().IntVarP(&logsTail, "tail", "n", 0, "Only print the last N lines (0 = whole file)")
+	cmdLogs.Flags().BoolVar(&logsStderr, "stderr", false, "Show stderr instead of stdout")
+	cmdLogs.Flags().IntVar(&logsTimeout, "timeout", 3, "Timeout in seconds for contacting the daemon")
+}
+
+var cmdLogs = &cobra.Command{
+	Use:   "logs <id|name>",
+	Short: "Print captured output of a process started with run",
+	Long:  "Streams the stdout (or stderr) log file the daemon keeps for a spawned process. The selector is a registry ID or a process name.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		return controller().Logs(ctx, app.LogsParams{
+			Filters: selectorFilters(args[0]),
+			Stderr:  logsStderr,
+			Follow:  logsFollow,
+			Tail:    logsTail,
+			Timeout: time.Duration(logsTimeout) * time.Second,
+		}, cmd.OutOrStdout())
+	},
+}
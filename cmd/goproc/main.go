@@ -2,7 +2,8 @@ package main
 
 import (
 	"context"
-	"log"
+	"io"
+	"os"
 	"time"
 
 	"goproc/internal/app"
@@ -15,19 +16,44 @@ var (
 		Use:   "goproc [command]",
 		Short: "goproc: small process watcher",
 		Long:  `goproc is a small process watcher that can be used to monitor and manage processes.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			commandStarted = true
+			if err := validateOutput(); err != nil {
+				return err
+			}
+			// Arguments are valid from here on; runtime errors should not
+			// bury the message under the usage text.
+			cmd.SilenceUsage = true
+			return nil
+		},
 	}
-	configPath string
+	// commandStarted is set once flags and arguments have been accepted, so
+	// errors before that point are reported as usage errors.
+	commandStarted bool
+	configPath     string
 )
 
 type controllerAPI interface {
 	Ping(ctx context.Context, timeout time.Duration) (string, error)
 	Add(ctx context.Context, params app.AddParams) (app.AddResult, error)
-	List(ctx context.Context, params app.ListParams) ([]app.Process, error)
+	Spawn(ctx context.Context, params app.SpawnParams) (app.SpawnResult, error)
+	ListPage(ctx context.Context, params app.ListParams) (app.ListPageResult, error)
+	Logs(ctx context.Context, params app.LogsParams, w io.Writer) error
+	Watch(ctx context.Context, params app.WatchParams, fn func(app.Event) error) error
+	Stats(ctx context.Context, params app.StatsParams) ([]app.ProcStats, error)
+	History(ctx context.Context, params app.HistoryParams) ([]app.HistoryEntry, error)
+	Export(ctx context.Context, params app.ExportParams) (app.ExportResult, error)
+	Import(ctx context.Context, params app.ImportParams) (app.ImportResult, error)
 	Remove(ctx context.Context, params app.RemoveParams) (app.RemoveResult, error)
 	Kill(ctx context.Context, params app.KillParams) (app.KillResult, error)
+	Signal(ctx context.Context, params app.SignalParams) (app.SignalResult, error)
 	Tag(ctx context.Context, params app.TagParams) (app.TagResult, error)
 	Group(ctx context.Context, params app.GroupParams) (app.GroupResult, error)
+	UpdateLabels(ctx context.Context, params app.UpdateLabelsParams) (app.UpdateLabelsResult, error)
+	SetName(ctx context.Context, params app.SetNameParams) (app.SetNameResult, error)
+	Apply(ctx context.Context, params app.ApplyParams) (app.ApplyResult, error)
 	Reset(ctx context.Context, params app.ResetParams) error
+	InspectSnapshot(params app.InspectSnapshotParams) (app.SnapshotInfo, error)
 	Status() (app.DaemonStatus, error)
 	StopDaemon(force bool) error
 	StartDaemon() (*app.DaemonHandle, error)
@@ -41,6 +67,9 @@ var _ controllerAPI = (*app.App)(nil)
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Path to JSON config file")
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &usageError{err: err}
+	})
 }
 
 func controller() controllerAPI {
@@ -49,6 +78,9 @@ func controller() controllerAPI {
 
 func main() {
 	if err := rootCmd.Execute(); err != nil {
-		log.Fatal(err)
+		if !commandStarted {
+			err = &usageError{err: err}
+		}
+		os.Exit(exitCode(err))
 	}
 }
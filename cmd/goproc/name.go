@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"goproc/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	nameClear   bool
+	nameTimeout int
+)
+
+func init() {
+	rootCmd.AddCommand(cmdName)
+	cmdName.Flags().BoolVar(&nameClear, "clear", false, "Remove the name instead of setting one")
+	cmdName.Flags().IntVar(&nameTimeout, "timeout", 3, "Timeout in seconds for daemon request")
+}
+
+var cmdName = &cobra.Command{
+	Use:   "name <id|name> [new-name]",
+	Short: "Set, change or clear the name of a registered process",
+	Long:  "Renames the process selected by registry ID or current name. Names must be unique; a name held by another entry is rejected. Pass --clear instead of a new name to remove it.",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var newName string
+		if len(args) == 2 {
+			newName = args[1]
+		} else if !nameClear {
+			return usageErrorf("provide a new name or pass --clear")
+		}
+		res, err := controller().SetName(cmd.Context(), app.SetNameParams{
+			Filters: selectorFilters(args[0]),
+			Name:    newName,
+			Clear:   nameClear,
+			Timeout: time.Duration(nameTimeout) * time.Second,
+		})
+		if err != nil {
+			return err
+		}
+		return render(cmd, view{
+			data: res,
+			text: func(w io.Writer) {
+				fmt.Fprintf(w, "[id=%d] pid=%d name %s -> %s\n", res.Process.ID, res.Process.PID, dashIfEmpty(res.PreviousName), dashIfEmpty(res.Process.Name))
+			},
+			table: func(bool) ([]string, [][]string) {
+				return []string{"ID", "PID", "PREVIOUS", "NAME"}, [][]string{{
+					fmt.Sprint(res.Process.ID),
+					fmt.Sprint(res.Process.PID),
+					dashIfEmpty(res.PreviousName),
+					dashIfEmpty(res.Process.Name),
+				}}
+			},
+		})
+	},
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"goproc/internal/app"
+	"goproc/internal/units"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats selectable with --output.
+const (
+	outputText  = "text"
+	outputJSON  = "json"
+	outputJSONL = "jsonl"
+	outputYAML  = "yaml"
+	outputTable = "table"
+	outputWide  = "wide"
+)
+
+var (
+	outputFormat   string
+	outputTemplate string
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", outputText, "Output format: text, json, jsonl, yaml, table, or wide")
+	rootCmd.PersistentFlags().StringVar(&outputTemplate, "template", "", "Render the result with a Go text/template instead (e.g. '{{range .}}{{.ID}} {{.Name}}{{\"\\n\"}}{{end}}')")
+}
+
+// validateOutput checks the global output flags before a command runs.
+func validateOutput() error {
+	switch outputFormat {
+	case outputText, outputJSON, outputJSONL, outputYAML, outputTable, outputWide:
+	default:
+		return usageErrorf("invalid --output %q (expected text, json, jsonl, yaml, table, or wide)", outputFormat)
+	}
+	if outputTemplate != "" {
+		if _, err := parseOutputTemplate(); err != nil {
+			return usageErrorf("invalid --template: %v", err)
+		}
+	}
+	return nil
+}
+
+// machineOutput reports whether stdout is reserved for structured output, so
+// hints meant for people go to stderr instead.
+func machineOutput() bool {
+	return outputTemplate != "" || outputFormat != outputText
+}
+
+// view describes how one command result is rendered in every format.
+type view struct {
+	// data is what json, yaml and --template serialize.
+	data any
+	// items, when set, is a slice that jsonl emits one element per line;
+	// otherwise jsonl writes data on a single line.
+	items any
+	// text is the human-readable format.
+	text func(w io.Writer)
+	// table returns a header and rows; wide may add columns. Without it
+	// table and wide fall back to text.
+	table func(wide bool) ([]string, [][]string)
+}
+
+// render writes v to the command's stdout in the selected format.
+func render(cmd *cobra.Command, v view) error {
+	w := cmd.OutOrStdout()
+	if outputTemplate != "" {
+		tmpl, err := parseOutputTemplate()
+		if err != nil {
+			return err
+		}
+		return tmpl.Execute(w, v.data)
+	}
+	switch outputFormat {
+	case outputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v.data)
+	case outputJSONL:
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		if v.items == nil {
+			return enc.Encode(v.data)
+		}
+		items := reflect.ValueOf(v.items)
+		for i := 0; i < items.Len(); i++ {
+			if err := enc.Encode(items.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case outputYAML:
+		return writeYAML(w, v.data)
+	case outputTable, outputWide:
+		if v.table != nil {
+			header, rows := v.table(outputFormat == outputWide)
+			return writeTable(w, header, rows)
+		}
+	}
+	v.text(w)
+	return nil
+}
+
+func parseOutputTemplate() (*template.Template, error) {
+	return template.New("output").Funcs(template.FuncMap{
+		"join": strings.Join,
+		"json": func(v any) (string, error) {
+			raw, err := json.Marshal(v)
+			return string(raw), err
+		},
+	}).Parse(outputTemplate)
+}
+
+// writeYAML renders data with the same field names and order as JSON by
+// decoding the JSON encoding into a YAML node tree.
+func writeYAML(w io.Writer, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return err
+	}
+	resetYAMLStyle(&doc)
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// resetYAMLStyle drops the flow and quoting styles inherited from JSON,
+// keeping quotes only where a plain scalar would change type.
+func resetYAMLStyle(n *yaml.Node) {
+	n.Style = 0
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!str" {
+		var probe any
+		if yaml.Unmarshal([]byte(n.Value), &probe) != nil || reflect.TypeOf(probe) != reflect.TypeOf("") {
+			n.Style = yaml.DoubleQuotedStyle
+		}
+	}
+	for _, c := range n.Content {
+		resetYAMLStyle(c)
+	}
+}
+
+func writeTable(w io.Writer, header []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// processTable is the table layout shared by commands that print processes;
+// wide adds the latest metrics sample.
+func processTable(procs []app.Process, wide bool) ([]string, [][]string) {
+	header := []string{"ID", "PID", "NAME", "ALIVE", "TAGS", "GROUPS", "CMD"}
+	if wide {
+		header = append(header, "CPU", "RSS", "THREADS", "FDS", "EXIT")
+	}
+	rows := make([][]string, 0, len(procs))
+	for _, p := range procs {
+		row := []string{
+			fmt.Sprint(p.ID),
+			fmt.Sprint(p.PID),
+			dashIfEmpty(p.Name),
+			fmt.Sprint(p.Alive),
+			dashIfEmpty(strings.Join(p.Tags, ",")),
+			dashIfEmpty(strings.Join(p.Groups, ",")),
+			p.Cmd,
+		}
+		if wide {
+			cpu, rss, threads, fds := "-", "-", "-", "-"
+			if m := p.Metrics; m != nil {
+				cpu = fmt.Sprintf("%.1f%%", m.CPUPercent)
+				rss = units.FormatBytes(m.RSS)
+				threads = fmt.Sprint(m.Threads)
+				fds = fmt.Sprint(m.FDs)
+			}
+			exit := "-"
+			if p.Exit != nil {
+				exit = p.Exit.String()
+			}
+			row = append(row, cpu, rss, threads, fds, exit)
+		}
+		rows = append(rows, row)
+	}
+	return header, rows
+}
+
+func dashIfEmpty(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// nonNil keeps empty lists as [] rather than null in structured output.
+func nonNil[T any](xs []T) []T {
+	if xs == nil {
+		return []T{}
+	}
+	return xs
+}
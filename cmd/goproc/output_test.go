@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"goproc/internal/app"
+	"goproc/internal/query"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func withOutput(t *testing.T, format, tmpl string) {
+	t.Helper()
+	oldFormat, oldTemplate := outputFormat, outputTemplate
+	outputFormat, outputTemplate = format, tmpl
+	t.Cleanup(func() { outputFormat, outputTemplate = oldFormat, oldTemplate })
+}
+
+func TestPingOutputFormats(t *testing.T) {
+	withController(t, &stubController{
+		pingFunc: func(ctx context.Context, timeout time.Duration) (string, error) {
+			return "pong", nil
+		},
+	})
+	cases := []struct {
+		format, tmpl, want string
+	}{
+		{format: outputJSON, want: "{\n  \"response\": \"pong\"\n}\n"},
+		{format: outputJSONL, want: "{\"response\":\"pong\"}\n"},
+		{format: outputYAML, want: "response: pong\n"},
+		{format: outputTable, want: "RESPONSE\npong\n"},
+		{format: outputText, tmpl: "{{.Response}}!", want: "pong!"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.format+tc.tmpl, func(t *testing.T) {
+			withOutput(t, tc.format, tc.tmpl)
+			buf, restore := withPingOutput(t)
+			defer restore()
+
+			if err := cmdPing.RunE(cmdPing, nil); err != nil {
+				t.Fatalf("RunE error: %v", err)
+			}
+			if got := buf.String(); got != tc.want {
+				t.Fatalf("unexpected output %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidateOutput(t *testing.T) {
+	withOutput(t, "xml", "")
+	if err := validateOutput(); exitCode(err) != exitUsage {
+		t.Fatalf("expected usage error for unknown format, got %v", err)
+	}
+	withOutput(t, outputText, "{{.Missing")
+	if err := validateOutput(); exitCode(err) != exitUsage {
+		t.Fatalf("expected usage error for bad template, got %v", err)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	_, qerr := query.Parse("rss>")
+	cases := []struct {
+		err  error
+		want int
+	}{
+		{nil, exitOK},
+		{errors.New("boom"), exitError},
+		{usageErrorf("bad flag"), exitUsage},
+		{qerr, exitUsage},
+		{fmt.Errorf("wrapped: %w", app.ErrInvalidParams), exitUsage},
+		{app.ErrDaemonNotRunning, exitUnavailable},
+		{app.ErrNoMatch, exitNotFound},
+		{app.ErrPartialFailure, exitPartial},
+		{context.DeadlineExceeded, exitTimeout},
+		{fmt.Errorf("daemon add RPC failed: %w", status.Error(codes.AlreadyExists, "taken")), exitConflict},
+		{fmt.Errorf("daemon list RPC failed: %w", status.Error(codes.NotFound, "gone")), exitNotFound},
+	}
+	for _, tc := range cases {
+		if got := exitCode(tc.err); got != tc.want {
+			t.Errorf("exitCode(%v) = %d, want %d", tc.err, got, tc.want)
+		}
+	}
+}
+
+// captureOutput redirects the stdout of cmd for the rest of the test.
+func captureOutput(t *testing.T, cmd *cobra.Command) *bytes.Buffer {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	orig := cmd.OutOrStdout()
+	cmd.SetOut(buf)
+	t.Cleanup(func() { cmd.SetOut(orig) })
+	return buf
+}
+
+func TestSignalOutputFormats(t *testing.T) {
+	withController(t, &stubController{
+		signalFunc: func(ctx context.Context, params app.SignalParams) (app.SignalResult, error) {
+			return app.SignalResult{
+				Signal: "SIGHUP",
+				Events: []app.SignalEvent{
+					{Proc: app.Process{ID: 1, PID: 10, Name: "api"}},
+					{Proc: app.Process{ID: 2, PID: 20}, Err: errors.New("gone")},
+				},
+				TotalMatches: 2,
+				TotalAlive:   2,
+				Successes:    1,
+			}, fmt.Errorf("partially successful: %w", app.ErrPartialFailure)
+		},
+	})
+	cases := []struct {
+		format, tmpl, want string
+	}{
+		{format: outputText, want: "Sent SIGHUP to [id=1] pid=10 name=api\nFailed to signal [id=2] pid=20 name=-: gone\n"},
+		{format: outputTable, want: "ID  TARGET  NAME  SIGNAL  RESULT  ERROR\n1   pid=10  api   SIGHUP  sent    -\n2   pid=20  -     SIGHUP  failed  gone\n"},
+		{format: outputText, tmpl: `{{.Signal}} {{.Successes}}/{{.TotalAlive}}{{range .Events}} {{.Proc.ID}}{{end}}`, want: "SIGHUP 1/2 1 2"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.format+tc.tmpl, func(t *testing.T) {
+			withOutput(t, tc.format, tc.tmpl)
+			buf := captureOutput(t, cmdSignal)
+			if err := cmdSignal.RunE(cmdSignal, []string{"HUP"}); !errors.Is(err, app.ErrPartialFailure) {
+				t.Fatalf("RunE error = %v, want partial failure", err)
+			}
+			if got := buf.String(); got != tc.want {
+				t.Fatalf("unexpected output %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	withOutput(t, outputJSONL, "")
+	buf := captureOutput(t, cmdSignal)
+	_ = cmdSignal.RunE(cmdSignal, []string{"HUP"})
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || strings.Contains(lines[0], `"error"`) || !strings.HasSuffix(lines[1], `"error":"gone"}`) {
+		t.Fatalf("unexpected jsonl output %q", buf.String())
+	}
+}
+
+func TestNameOutput(t *testing.T) {
+	withController(t, &stubController{
+		setNameFunc: func(ctx context.Context, params app.SetNameParams) (app.SetNameResult, error) {
+			return app.SetNameResult{Process: app.Process{ID: 3, PID: 30, Name: params.Name}, PreviousName: "old"}, nil
+		},
+	})
+	withOutput(t, outputText, "{{.PreviousName}}>{{.Process.Name}}")
+	buf := captureOutput(t, cmdName)
+	if err := cmdName.RunE(cmdName, []string{"old", "new"}); err != nil {
+		t.Fatalf("RunE error: %v", err)
+	}
+	if got := buf.String(); got != "old>new" {
+		t.Fatalf("unexpected output %q", got)
+	}
+	if err := cmdName.RunE(cmdName, []string{"old"}); exitCode(err) != exitUsage {
+		t.Fatalf("missing name: got %v, want a usage error", err)
+	}
+}
+
+func TestStatsOutputJSON(t *testing.T) {
+	withController(t, &stubController{
+		statsFunc: func(ctx context.Context, params app.StatsParams) ([]app.ProcStats, error) {
+			return nil, nil
+		},
+	})
+	withOutput(t, outputJSON, "")
+	buf := captureOutput(t, cmdStats)
+	if err := cmdStats.RunE(cmdStats, nil); err != nil {
+		t.Fatalf("RunE error: %v", err)
+	}
+	if got := buf.String(); got != "[]\n" {
+		t.Fatalf("unexpected output %q", got)
+	}
+}
+
+func TestWatchOutputFormats(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 3, 0, time.UTC)
+	withController(t, &stubController{
+		watchFunc: func(ctx context.Context, params app.WatchParams, fn func(app.Event) error) error {
+			for _, ev := range []app.Event{
+				{Type: "died", Process: &app.Process{ID: 12, PID: 4242, Name: "db"}, At: at},
+				{Type: "reset", At: at},
+			} {
+				if err := fn(ev); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+	cases := []struct {
+		format, tmpl string
+		json         bool
+		want         string
+	}{
+		{format: outputText, want: "2024-05-01T12:00:03Z died [id=12] pid=4242 name=db alive=false tags=[] groups=[]\n2024-05-01T12:00:03Z reset\n"},
+		{format: outputText, tmpl: "{{.Type}};", want: "died;reset;"},
+		{format: outputYAML, want: "---\ntype: died\n"},
+		{format: outputText, json: true, want: `{"type":"died","process":{"id":12,`},
+	}
+	cmdWatch.SetContext(context.Background())
+	for _, tc := range cases {
+		withOutput(t, tc.format, tc.tmpl)
+		watchJSON = tc.json
+		buf := captureOutput(t, cmdWatch)
+		if err := cmdWatch.RunE(cmdWatch, nil); err != nil {
+			t.Fatalf("%s: RunE error: %v", tc.format, err)
+		}
+		if got := buf.String(); !strings.HasPrefix(got, tc.want) {
+			t.Errorf("%s (json=%v): unexpected output %q", tc.format, tc.json, got)
+		}
+		if tc.json && strings.Count(buf.String(), "\n") != 2 {
+			t.Errorf("--json wrote %q, want one line per event", buf.String())
+		}
+	}
+	watchJSON = false
+}
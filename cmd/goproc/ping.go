@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -31,7 +32,17 @@ var cmdPing = &cobra.Command{
 		}
 
 		// Нормальный ответ — "pong"
-		fmt.Fprintln(cmd.OutOrStdout(), msg)
-		return nil
+		return render(cmd, view{
+			data: pingResult{Response: msg},
+			text: func(w io.Writer) { fmt.Fprintln(w, msg) },
+			table: func(bool) ([]string, [][]string) {
+				return []string{"RESPONSE"}, [][]string{{msg}}
+			},
+		})
 	},
 }
+
+// pingResult is the structured form of the ping reply.
+type pingResult struct {
+	Response string `json:"response"`
+}
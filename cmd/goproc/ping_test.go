@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"io"
 	"testing"
 	"time"
 
@@ -11,7 +12,11 @@ import (
 )
 
 type stubController struct {
-	pingFunc func(ctx context.Context, timeout time.Duration) (string, error)
+	pingFunc    func(ctx context.Context, timeout time.Duration) (string, error)
+	signalFunc  func(ctx context.Context, params app.SignalParams) (app.SignalResult, error)
+	setNameFunc func(ctx context.Context, params app.SetNameParams) (app.SetNameResult, error)
+	statsFunc   func(ctx context.Context, params app.StatsParams) ([]app.ProcStats, error)
+	watchFunc   func(ctx context.Context, params app.WatchParams, fn func(app.Event) error) error
 }
 
 func (s *stubController) Ping(ctx context.Context, timeout time.Duration) (string, error) {
@@ -25,8 +30,68 @@ func (s *stubController) Add(ctx context.Context, params app.AddParams) (app.Add
 	panic("Add not implemented")
 }
 
-func (s *stubController) List(ctx context.Context, params app.ListParams) ([]app.Process, error) {
-	panic("List not implemented")
+func (s *stubController) Spawn(ctx context.Context, params app.SpawnParams) (app.SpawnResult, error) {
+	panic("Spawn not implemented")
+}
+
+func (s *stubController) ListPage(ctx context.Context, params app.ListParams) (app.ListPageResult, error) {
+	panic("ListPage not implemented")
+}
+
+func (s *stubController) Logs(ctx context.Context, params app.LogsParams, w io.Writer) error {
+	panic("Logs not implemented")
+}
+
+func (s *stubController) Watch(ctx context.Context, params app.WatchParams, fn func(app.Event) error) error {
+	if s.watchFunc != nil {
+		return s.watchFunc(ctx, params, fn)
+	}
+	panic("Watch not implemented")
+}
+
+func (s *stubController) Stats(ctx context.Context, params app.StatsParams) ([]app.ProcStats, error) {
+	if s.statsFunc != nil {
+		return s.statsFunc(ctx, params)
+	}
+	panic("Stats not implemented")
+}
+
+func (s *stubController) Signal(ctx context.Context, params app.SignalParams) (app.SignalResult, error) {
+	if s.signalFunc != nil {
+		return s.signalFunc(ctx, params)
+	}
+	panic("Signal not implemented")
+}
+
+func (s *stubController) UpdateLabels(ctx context.Context, params app.UpdateLabelsParams) (app.UpdateLabelsResult, error) {
+	panic("UpdateLabels not implemented")
+}
+
+func (s *stubController) SetName(ctx context.Context, params app.SetNameParams) (app.SetNameResult, error) {
+	if s.setNameFunc != nil {
+		return s.setNameFunc(ctx, params)
+	}
+	panic("SetName not implemented")
+}
+
+func (s *stubController) Apply(ctx context.Context, params app.ApplyParams) (app.ApplyResult, error) {
+	panic("Apply not implemented")
+}
+
+func (s *stubController) History(ctx context.Context, params app.HistoryParams) ([]app.HistoryEntry, error) {
+	panic("History not implemented")
+}
+
+func (s *stubController) Export(ctx context.Context, params app.ExportParams) (app.ExportResult, error) {
+	panic("Export not implemented")
+}
+
+func (s *stubController) Import(ctx context.Context, params app.ImportParams) (app.ImportResult, error) {
+	panic("Import not implemented")
+}
+
+func (s *stubController) InspectSnapshot(params app.InspectSnapshotParams) (app.SnapshotInfo, error) {
+	panic("InspectSnapshot not implemented")
 }
 
 func (s *stubController) Remove(ctx context.Context, params app.RemoveParams) (app.RemoveResult, error) {
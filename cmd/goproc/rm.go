@@ -2,7 +2,7 @@ package main
 
 import (
 	"fmt"
-	"os"
+	"io"
 	"strings"
 	"time"
 
@@ -18,6 +18,10 @@ var (
 	rmIDs         []int
 	rmNames       []string
 	rmSearch      string
+	rmQuery       string
+	rmNameGlobs   []string
+	rmTagGlobs    []string
+	rmSearchRegex string
 	rmRemoveAll   bool
 	rmTimeoutSecs int
 )
@@ -31,6 +35,10 @@ func init() {
 	cmdRm.Flags().IntSliceVar(&rmIDs, "id", nil, "Filter by registry ID (repeatable)")
 	cmdRm.Flags().StringSliceVar(&rmNames, "name", nil, "Match processes with these exact names")
 	cmdRm.Flags().StringVar(&rmSearch, "search", "", "Substring to match against process command")
+	cmdRm.Flags().StringArrayVar(&rmNameGlobs, "name-glob", nil, "Match names against a glob such as 'worker-*' (repeatable, any may match)")
+	cmdRm.Flags().StringArrayVar(&rmTagGlobs, "tag-glob", nil, "Match processes with a tag matching a glob such as 'env-*' (repeatable, any may match)")
+	cmdRm.Flags().StringVar(&rmSearchRegex, "search-regex", "", "Regular expression to match against the command, e.g. 'python .*manage.py'")
+	cmdRm.Flags().StringVarP(&rmQuery, "query", "q", "", "Query expression, e.g. 'tag:db and (group:prod or name~^api-) and rss>500MB'")
 	cmdRm.Flags().BoolVar(&rmRemoveAll, "all", false, "Remove every process that matches the selector")
 	cmdRm.Flags().IntVar(&rmTimeoutSecs, "timeout", 3, "Timeout in seconds for list/remove operations")
 }
@@ -48,6 +56,10 @@ var cmdRm = &cobra.Command{
 				TextSearch: rmSearch,
 				PIDs:       rmPIDs,
 				IDs:        rmIDs,
+				Query:      rmQuery,
+				NameGlobs:  rmNameGlobs,
+				TagGlobs:   rmTagGlobs,
+				CmdRegex:   rmSearchRegex,
 			},
 			AllowAll:        rmRemoveAll,
 			Timeout:         time.Duration(rmTimeoutSecs) * time.Second,
@@ -56,26 +68,31 @@ var cmdRm = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		if res.Message != "" {
-			fmt.Fprintln(os.Stdout, res.Message)
-			return nil
-		}
-		for _, proc := range res.Removed {
-			name := proc.Name
-			if name == "" {
-				name = "-"
-			}
-			fmt.Fprintf(
-				os.Stdout,
-				"Removed [id=%d] pid=%d name=%s cmd=%s tags=[%s] groups=[%s]\n",
-				proc.ID,
-				proc.PID,
-				name,
-				proc.Cmd,
-				strings.Join(proc.Tags, ","),
-				strings.Join(proc.Groups, ","),
-			)
-		}
-		return nil
+		res.Removed = nonNil(res.Removed)
+		return render(cmd, view{
+			data:  res,
+			items: res.Removed,
+			text: func(w io.Writer) {
+				if res.Message != "" {
+					fmt.Fprintln(w, res.Message)
+					return
+				}
+				for _, proc := range res.Removed {
+					fmt.Fprintf(
+						w,
+						"Removed [id=%d] pid=%d name=%s cmd=%s tags=[%s] groups=[%s]\n",
+						proc.ID,
+						proc.PID,
+						dashIfEmpty(proc.Name),
+						proc.Cmd,
+						strings.Join(proc.Tags, ","),
+						strings.Join(proc.Groups, ","),
+					)
+				}
+			},
+			table: func(wide bool) ([]string, [][]string) {
+				return processTable(res.Removed, wide)
+			},
+		})
 	},
 }
@@ -2,22 +2,35 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"strings"
-	"syscall"
 	"time"
 
 	"goproc/internal/app"
+	"goproc/internal/units"
 
 	"github.com/spf13/cobra"
 )
 
 var (
-	runTags    []string
-	runGroups  []string
-	runName    string
-	runTimeout int
+	runTags          []string
+	runGroups        []string
+	runName          string
+	runTimeout       int
+	runEnv           []string
+	runCwd           string
+	runRestart       string
+	runMaxRestarts   int
+	runRestartWindow time.Duration
+	runBackoff       time.Duration
+	runMaxBackoff    time.Duration
+	runAnnotations   []string
+	runTrack         bool
+	runCgroup        bool
+	runMemoryMax     string
+	runCPUMax        float64
+	runPIDsMax       int
 )
 
 func init() {
@@ -26,45 +39,98 @@ func init() {
 	cmdRun.Flags().StringSliceVar(&runTags, "tag", nil, "Tag to assign to the tracked process (repeatable)")
 	cmdRun.Flags().StringSliceVar(&runGroups, "group", nil, "Group to assign to the tracked process (repeatable)")
 	cmdRun.Flags().StringVar(&runName, "name", "", "Optional unique name for the tracked process")
+	cmdRun.Flags().StringArrayVar(&runAnnotations, "annotate", nil, "Annotation KEY=VALUE to store on the entry (repeatable)")
+	cmdRun.Flags().BoolVar(&runTrack, "track-children", false, "Register processes it forks as child entries linked to this one")
 	cmdRun.Flags().IntVar(&runTimeout, "timeout", 3, "Timeout in seconds for contacting the daemon")
+	cmdRun.Flags().StringArrayVar(&runEnv, "env", nil, "Extra environment variable KEY=VALUE (repeatable)")
+	cmdRun.Flags().StringVar(&runCwd, "cwd", "", "Working directory for the process (defaults to the current directory)")
+	cmdRun.Flags().StringVar(&runRestart, "restart", "never", "Restart policy: never, on-failure, or always")
+	cmdRun.Flags().IntVar(&runMaxRestarts, "max-restarts", 5, "Give up after this many restarts within --restart-window (0 = unlimited)")
+	cmdRun.Flags().DurationVar(&runRestartWindow, "restart-window", time.Minute, "Sliding window used to count restarts")
+	cmdRun.Flags().DurationVar(&runBackoff, "backoff", time.Second, "Initial delay before a restart; doubles on every restart")
+	cmdRun.Flags().DurationVar(&runMaxBackoff, "max-backoff", time.Minute, "Upper bound for the restart delay")
+	cmdRun.Flags().BoolVar(&runCgroup, "cgroup", false, "Run the process in its own cgroup v2 leaf under the daemon's cgroup root")
+	cmdRun.Flags().StringVar(&runMemoryMax, "memory-max", "", "Memory limit for the cgroup, e.g. 512MB (implies --cgroup)")
+	cmdRun.Flags().Float64Var(&runCPUMax, "cpu-max", 0, "CPU limit for the cgroup in cores, e.g. 0.5 (implies --cgroup)")
+	cmdRun.Flags().IntVar(&runPIDsMax, "pids-max", 0, "Maximum number of tasks in the cgroup (implies --cgroup)")
 }
 
 var cmdRun = &cobra.Command{
 	Use:   "run -- <command> [args...]",
-	Short: "Launch a new command and register its PID with the daemon",
-	Long:  "Starts the provided command, immediately registers the spawned PID with the daemon, and exits while the process keeps running.",
+	Short: "Launch a new command under daemon supervision",
+	Long:  "Asks the daemon to start the provided command, registers it right away, and applies the restart policy whenever it exits.",
 	Args:  cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		child := exec.Command(args[0], args[1:]...)
-		child.Stdout = os.Stdout
-		child.Stderr = os.Stderr
-		child.Stdin = os.Stdin
-		child.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
-
-		if err := child.Start(); err != nil {
-			return fmt.Errorf("start command: %w", err)
+		for _, kv := range runEnv {
+			if !strings.Contains(kv, "=") {
+				return usageErrorf("invalid --env %q (expected KEY=VALUE)", kv)
+			}
+		}
+		annotations, err := app.ParseAnnotations(runAnnotations)
+		if err != nil {
+			return err
+		}
+		limits, err := runCgroupLimits()
+		if err != nil {
+			return err
+		}
+		dir := runCwd
+		if dir == "" {
+			wd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("resolve working directory: %w", err)
+			}
+			dir = wd
 		}
-		defer child.Process.Release()
 
-		name := strings.TrimSpace(runName)
-		res, err := controller().Add(cmd.Context(), app.AddParams{
-			PID:     child.Process.Pid,
-			Tags:    runTags,
-			Groups:  runGroups,
-			Name:    name,
-			Timeout: time.Duration(runTimeout) * time.Second,
+		res, err := controller().Spawn(cmd.Context(), app.SpawnParams{
+			Argv:   args,
+			Env:    append(os.Environ(), runEnv...),
+			Dir:    dir,
+			Tags:   runTags,
+			Groups: runGroups,
+			Name:   strings.TrimSpace(runName),
+			Restart: app.RestartPolicy{
+				Mode:        runRestart,
+				MaxRestarts: runMaxRestarts,
+				Window:      runRestartWindow,
+				Backoff:     runBackoff,
+				MaxBackoff:  runMaxBackoff,
+			},
+			Timeout:       time.Duration(runTimeout) * time.Second,
+			Annotations:   annotations,
+			TrackChildren: runTrack,
+			Cgroup:        limits,
 		})
 		if err != nil {
-			_ = child.Process.Kill()
 			return err
 		}
-		if res.AlreadyExists {
-			_ = child.Process.Kill()
-			fmt.Fprintln(os.Stdout, res.ExistingReason)
-			return nil
-		}
 
-		fmt.Fprintf(os.Stdout, "Started pid=%d registry id=%d\n", child.Process.Pid, res.ID)
-		return nil
+		return render(cmd, view{
+			data: res,
+			text: func(w io.Writer) {
+				fmt.Fprintf(w, "Started pid=%d registry id=%d\n", res.PID, res.ID)
+			},
+			table: func(bool) ([]string, [][]string) {
+				return []string{"ID", "PID"}, [][]string{{fmt.Sprint(res.ID), fmt.Sprint(res.PID)}}
+			},
+		})
 	},
 }
+
+// runCgroupLimits returns the cgroup requested by the run flags, or nil if
+// the process should stay in the daemon's cgroup.
+func runCgroupLimits() (*app.CgroupLimits, error) {
+	limits := app.CgroupLimits{CPUMax: runCPUMax, PIDsMax: runPIDsMax}
+	if runMemoryMax != "" {
+		n, err := units.ParseBytes(runMemoryMax)
+		if err != nil {
+			return nil, usageErrorf("invalid --memory-max: %v", err)
+		}
+		limits.MemoryMax = n
+	}
+	if !runCgroup && limits == (app.CgroupLimits{}) {
+		return nil, nil
+	}
+	return &limits, nil
+}
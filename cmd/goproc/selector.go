@@ -0,0 +1,17 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"goproc/internal/app"
+)
+
+// selectorFilters interprets a positional selector: a numeric registry ID or an exact process name.
+func selectorFilters(arg string) app.ListFilters {
+	arg = strings.TrimSpace(arg)
+	if id, err := strconv.Atoi(arg); err == nil {
+		return app.ListFilters{IDs: []int{id}}
+	}
+	return app.ListFilters{Names: []string{arg}}
+}
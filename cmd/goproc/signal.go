@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"goproc/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	signalTags         []string
+	signalGroups       []string
+	signalNames        []string
+	signalPIDs         []int
+	signalIDs          []int
+	signalQuery        string
+	signalNameGlobs    []string
+	signalTagGlobs     []string
+	signalSearchRegex  string
+	signalAll          bool
+	signalProcessGroup bool
+	signalTimeout      int
+)
+
+func init() {
+	rootCmd.AddCommand(cmdSignal)
+	cmdSignal.Flags().StringSliceVar(&signalTags, "tag", nil, "Match processes that have any of these tags")
+	cmdSignal.Flags().StringSliceVar(&signalGroups, "group", nil, "Match processes that belong to any of these groups")
+	cmdSignal.Flags().StringSliceVar(&signalNames, "name", nil, "Match processes with these exact names")
+	cmdSignal.Flags().IntSliceVar(&signalPIDs, "pid", nil, "Filter by PID (repeatable)")
+	cmdSignal.Flags().IntSliceVar(&signalIDs, "id", nil, "Filter by registry ID (repeatable)")
+	cmdSignal.Flags().StringArrayVar(&signalNameGlobs, "name-glob", nil, "Match names against a glob such as 'worker-*' (repeatable, any may match)")
+	cmdSignal.Flags().StringArrayVar(&signalTagGlobs, "tag-glob", nil, "Match processes with a tag matching a glob such as 'env-*' (repeatable, any may match)")
+	cmdSignal.Flags().StringVar(&signalSearchRegex, "search-regex", "", "Regular expression to match against the command, e.g. 'python .*manage.py'")
+	cmdSignal.Flags().StringVarP(&signalQuery, "query", "q", "", "Query expression, e.g. 'tag:db and (group:prod or name~^api-) and rss>500MB'")
+	cmdSignal.Flags().BoolVar(&signalAll, "all", false, "Signal every process that matches the selector")
+	cmdSignal.Flags().BoolVarP(&signalProcessGroup, "process-group", "g", false, "Signal the whole process group instead of the PID")
+	cmdSignal.Flags().IntVar(&signalTimeout, "timeout", 5, "Timeout in seconds for contacting the daemon")
+}
+
+var cmdSignal = &cobra.Command{
+	Use:   "signal <SIG>",
+	Short: "Send a signal to tracked processes",
+	Long:  "Sends SIG (a name such as HUP, SIGUSR1 or STOP, or a number) to the alive processes selected with the same filters as `list`. Unlike `kill`, registry entries are left untouched.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		res, err := controller().Signal(cmd.Context(), app.SignalParams{
+			Signal: args[0],
+			Filters: app.ListFilters{
+				TagsAny:   signalTags,
+				GroupsAny: signalGroups,
+				Names:     signalNames,
+				PIDs:      signalPIDs,
+				IDs:       signalIDs,
+				Query:     signalQuery,
+				NameGlobs: signalNameGlobs,
+				TagGlobs:  signalTagGlobs,
+				CmdRegex:  signalSearchRegex,
+			},
+			AllowAll:     signalAll,
+			ProcessGroup: signalProcessGroup,
+			Timeout:      time.Duration(signalTimeout) * time.Second,
+		})
+		// Failed signals are reported per process before the error.
+		if res.Message != "" || len(res.Events) > 0 {
+			res.Events = nonNil(res.Events)
+			if rerr := render(cmd, view{
+				data:  res,
+				items: res.Events,
+				text:  func(w io.Writer) { writeSignalText(w, res) },
+				table: func(bool) ([]string, [][]string) { return signalTable(res) },
+			}); rerr != nil && err == nil {
+				err = rerr
+			}
+		}
+		return err
+	},
+}
+
+func writeSignalText(w io.Writer, res app.SignalResult) {
+	if res.Message != "" {
+		fmt.Fprintln(w, res.Message)
+	}
+	for _, event := range res.Events {
+		name := dashIfEmpty(event.Proc.Name)
+		if event.Err != nil {
+			fmt.Fprintf(w, "Failed to signal [id=%d] pid=%d name=%s: %v\n", event.Proc.ID, event.Proc.PID, name, event.Err)
+			continue
+		}
+		fmt.Fprintf(w, "Sent %s to [id=%d] %s name=%s\n", res.Signal, event.Proc.ID, signalTarget(event.Proc), name)
+	}
+}
+
+func signalTable(res app.SignalResult) ([]string, [][]string) {
+	rows := make([][]string, 0, len(res.Events))
+	for _, event := range res.Events {
+		result, errText := "sent", "-"
+		if event.Err != nil {
+			result, errText = "failed", event.Err.Error()
+		}
+		rows = append(rows, []string{
+			fmt.Sprint(event.Proc.ID),
+			signalTarget(event.Proc),
+			dashIfEmpty(event.Proc.Name),
+			res.Signal,
+			result,
+			errText,
+		})
+	}
+	return []string{"ID", "TARGET", "NAME", "SIGNAL", "RESULT", "ERROR"}, rows
+}
+
+// signalTarget names what was signaled: the PID or, with -g, its group.
+func signalTarget(p app.Process) string {
+	if signalProcessGroup {
+		return fmt.Sprintf("pgid=%d", p.PGID)
+	}
+	return fmt.Sprintf("pid=%d", p.PID)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"goproc/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	snapshotStore       string
+	snapshotFile        string
+	snapshotJournalFile string
+)
+
+func init() {
+	rootCmd.AddCommand(cmdSnapshot)
+	cmdSnapshot.AddCommand(cmdSnapshotInspect)
+	cmdSnapshotInspect.Flags().StringVar(&snapshotStore, "store", "", "Store backend that wrote the file: json or bolt (default: the configured store)")
+	cmdSnapshotInspect.Flags().StringVar(&snapshotFile, "file", "", "Snapshot or bolt database to inspect (default: the daemon's file in the runtime directory)")
+	cmdSnapshotInspect.Flags().StringVar(&snapshotJournalFile, "journal", "", "Journal to count pending changes in (default: the daemon's journal, unless --file is set)")
+}
+
+var cmdSnapshot = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Work with the registry snapshot",
+}
+
+var cmdSnapshotInspect = &cobra.Command{
+	Use:   "inspect",
+	Short: "Print the version and contents summary of the registry snapshot",
+	Long:  "Reads the snapshot file directly, so the daemon does not need to be running. Snapshots older than this build are migrated by the daemon on load, after a backup; newer ones are refused. Changes made since the snapshot was written live in the journal and are only counted. The bolt store's database is locked while the daemon runs; inspect a copy or stop the daemon first.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info, err := controller().InspectSnapshot(app.InspectSnapshotParams{Store: snapshotStore, Path: snapshotFile, JournalPath: snapshotJournalFile})
+		if err != nil {
+			return err
+		}
+		return render(cmd, view{
+			data: info,
+			text: func(w io.Writer) { writeSnapshotText(w, info) },
+			table: func(bool) ([]string, [][]string) {
+				return []string{"STORE", "PATH", "VERSION", "CREATED", "NEXT_ID", "PROCS", "ALIVE", "SPAWNED", "NAMED", "CHILDREN", "HISTORY", "JOURNAL"},
+					[][]string{{
+						info.Store,
+						info.Path,
+						strconv.Itoa(info.Version),
+						formatSnapshotCreated(info.CreatedUnix),
+						strconv.FormatUint(info.NextID, 10),
+						strconv.Itoa(info.Procs),
+						strconv.Itoa(info.Alive),
+						strconv.Itoa(info.Spawned),
+						strconv.Itoa(info.Named),
+						strconv.Itoa(info.Children),
+						strconv.Itoa(info.History),
+						strconv.Itoa(info.JournalRecords),
+					}}
+			},
+		})
+	},
+}
+
+func writeSnapshotText(w io.Writer, info app.SnapshotInfo) {
+	fmt.Fprintf(w, "store:    %s\n", info.Store)
+	fmt.Fprintf(w, "path:     %s\n", info.Path)
+	version := strconv.Itoa(info.Version)
+	if info.Version < info.Supported {
+		version += fmt.Sprintf(" (migrated to %d when the daemon loads it)", info.Supported)
+	}
+	fmt.Fprintf(w, "version:  %s\n", version)
+	fmt.Fprintf(w, "created:  %s (created_unix=%d)\n", formatSnapshotCreated(info.CreatedUnix), info.CreatedUnix)
+	fmt.Fprintf(w, "next id:  %d\n", info.NextID)
+	fmt.Fprintf(w, "procs:    %d (alive %d, spawned %d, named %d, children %d)\n",
+		info.Procs, info.Alive, info.Spawned, info.Named, info.Children)
+	fmt.Fprintf(w, "history:  %d removed entries\n", info.History)
+	if info.JournalPath != "" {
+		fmt.Fprintf(w, "journal:  %s (%d changes since the snapshot)\n", info.JournalPath, info.JournalRecords)
+	}
+}
+
+func formatSnapshotCreated(unix int64) string {
+	if unix == 0 {
+		return "-"
+	}
+	return time.Unix(unix, 0).Format(time.RFC3339)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"goproc/internal/app"
+	"goproc/internal/units"
+
+	"github.com/spf13/cobra"
+)
+
+const sparklineWidth = 24
+
+var (
+	statsTags    []string
+	statsGroups  []string
+	statsWindow  time.Duration
+	statsTimeout int
+)
+
+func init() {
+	rootCmd.AddCommand(cmdStats)
+	cmdStats.Flags().StringSliceVar(&statsTags, "tag", nil, "Match processes that have any of these tags")
+	cmdStats.Flags().StringSliceVar(&statsGroups, "group", nil, "Match processes that belong to any of these groups")
+	cmdStats.Flags().DurationVar(&statsWindow, "window", 10*time.Minute, "How much history to summarize (0 = everything retained)")
+	cmdStats.Flags().IntVar(&statsTimeout, "timeout", 3, "Timeout in seconds for contacting the daemon")
+}
+
+var cmdStats = &cobra.Command{
+	Use:   "stats [id|name]",
+	Short: "Summarize recent CPU and memory usage",
+	Long:  "Shows min/avg/max/p95 CPU and RSS over the window with sparklines, from the samples the daemon keeps on every liveness tick. Without a selector every process is shown.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var filters app.ListFilters
+		if len(args) == 1 {
+			filters = selectorFilters(args[0])
+		}
+		filters.TagsAny = statsTags
+		filters.GroupsAny = statsGroups
+
+		stats, err := controller().Stats(cmd.Context(), app.StatsParams{
+			Filters: filters,
+			Window:  statsWindow,
+			Timeout: time.Duration(statsTimeout) * time.Second,
+		})
+		if err != nil {
+			return err
+		}
+		stats = nonNil(stats)
+		return render(cmd, view{
+			data:  stats,
+			items: stats,
+			text: func(w io.Writer) {
+				if len(stats) == 0 {
+					fmt.Fprintln(w, "No processes matched")
+					return
+				}
+				header, rows := statsTable(stats)
+				_ = writeTable(w, header, rows)
+			},
+			table: func(bool) ([]string, [][]string) { return statsTable(stats) },
+		})
+	},
+}
+
+// statsTable summarizes each process on one row, with sparklines of the
+// samples in the window.
+func statsTable(stats []app.ProcStats) ([]string, [][]string) {
+	rows := make([][]string, 0, len(stats))
+	for _, st := range stats {
+		cpu := make([]float64, len(st.Samples))
+		rss := make([]float64, len(st.Samples))
+		for i, s := range st.Samples {
+			cpu[i] = s.CPUPercent
+			rss[i] = float64(s.RSS)
+		}
+		cpuSummary, rssSummary := "-", "-"
+		if len(st.Samples) > 0 {
+			cpuSummary = fmt.Sprintf("%.1f/%.1f/%.1f/%.1f", st.CPU.Min, st.CPU.Avg, st.CPU.Max, st.CPU.P95)
+			rssSummary = fmt.Sprintf("%s/%s/%s/%s",
+				units.FormatBytes(uint64(st.RSS.Min)),
+				units.FormatBytes(uint64(st.RSS.Avg)),
+				units.FormatBytes(uint64(st.RSS.Max)),
+				units.FormatBytes(uint64(st.RSS.P95)),
+			)
+		}
+		rows = append(rows, []string{
+			fmt.Sprint(st.Process.ID),
+			dashIfEmpty(st.Process.Name),
+			fmt.Sprint(len(st.Samples)),
+			cpuSummary,
+			sparkline(cpu, sparklineWidth),
+			rssSummary,
+			sparkline(rss, sparklineWidth),
+		})
+	}
+	return []string{"ID", "NAME", "SAMPLES", "CPU% MIN/AVG/MAX/P95", "CPU", "RSS MIN/AVG/MAX/P95", "RSS"}, rows
+}
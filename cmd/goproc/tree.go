@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"goproc/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	treeTags    []string
+	treeGroups  []string
+	treeQuery   string
+	treeTimeout int
+)
+
+func init() {
+	rootCmd.AddCommand(cmdTree)
+	cmdTree.Flags().StringSliceVar(&treeTags, "tag", nil, "Match processes that have any of these tags")
+	cmdTree.Flags().StringSliceVar(&treeGroups, "group", nil, "Match processes that belong to any of these groups")
+	cmdTree.Flags().StringVarP(&treeQuery, "query", "q", "", "Query expression, e.g. 'tag:web and children>0'")
+	cmdTree.Flags().IntVar(&treeTimeout, "timeout", 3, "Timeout in seconds for contacting the daemon")
+}
+
+var cmdTree = &cobra.Command{
+	Use:   "tree [id|name]",
+	Short: "Show tracked processes with their child processes",
+	Long: "Prints each matching entry followed by the processes it forked, as discovered by the daemon through /proc on every liveness tick. " +
+		"Descendants registered with --track-children are marked with their registry ID and nested under their parent instead of being listed separately.",
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var filters app.ListFilters
+		if len(args) == 1 {
+			filters = selectorFilters(args[0])
+		}
+		filters.TagsAny = treeTags
+		filters.GroupsAny = treeGroups
+		filters.Query = treeQuery
+
+		page, err := controller().ListPage(cmd.Context(), app.ListParams{
+			Filters: filters,
+			Timeout: time.Duration(treeTimeout) * time.Second,
+		})
+		if err != nil {
+			return err
+		}
+		roots := nonNil(app.TreeRoots(page.Processes))
+		return render(cmd, view{
+			data:  roots,
+			items: roots,
+			text: func(w io.Writer) {
+				if len(roots) == 0 {
+					fmt.Fprintln(w, "No processes registered")
+					return
+				}
+				for _, proc := range roots {
+					writeTree(w, proc)
+				}
+			},
+		})
+	},
+}
+
+func writeTree(w io.Writer, proc app.Process) {
+	fmt.Fprintf(w, "[id=%d] pid=%d name=%s alive=%t cmd=%s\n", proc.ID, proc.PID, dashIfEmpty(proc.Name), proc.Alive, proc.Cmd)
+	for _, line := range app.TreeLines(proc.Children) {
+		fmt.Fprintf(w, "%s%d %s", line.Prefix, line.Node.PID, line.Node.Cmd)
+		if line.Node.ID != 0 {
+			fmt.Fprintf(w, " [id=%d]", line.Node.ID)
+		}
+		fmt.Fprintln(w)
+	}
+}
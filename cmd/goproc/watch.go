@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"goproc/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchTagsAny     []string
+	watchTagsAll     []string
+	watchGroupsAny   []string
+	watchGroupsAll   []string
+	watchNames       []string
+	watchAliveOnly   bool
+	watchPIDs        []int
+	watchIDs         []int
+	watchTextSearch  string
+	watchAnnots      []string
+	watchQuery       string
+	watchNameGlobs   []string
+	watchTagGlobs    []string
+	watchSearchRegex string
+	watchJSON        bool
+	watchTimeout     int
+)
+
+func init() {
+	rootCmd.AddCommand(cmdWatch)
+	cmdWatch.Flags().StringSliceVar(&watchTagsAny, "tag", nil, "Match processes that have any of these tags")
+	cmdWatch.Flags().StringSliceVar(&watchTagsAll, "tag-all", nil, "Match processes that have all of these tags")
+	cmdWatch.Flags().StringSliceVar(&watchGroupsAny, "group", nil, "Match processes that are in any of these groups")
+	cmdWatch.Flags().StringSliceVar(&watchGroupsAll, "group-all", nil, "Match processes that are in all of these groups")
+	cmdWatch.Flags().StringSliceVar(&watchNames, "name", nil, "Match processes with these exact names")
+	cmdWatch.Flags().BoolVar(&watchAliveOnly, "alive", false, "Only report processes currently considered alive (deaths are still reported)")
+	cmdWatch.Flags().IntSliceVar(&watchPIDs, "pid", nil, "Filter by PID (repeatable)")
+	cmdWatch.Flags().IntSliceVar(&watchIDs, "id", nil, "Filter by registry ID (repeatable)")
+	cmdWatch.Flags().StringVar(&watchTextSearch, "search", "", "Substring to match against command")
+	cmdWatch.Flags().StringArrayVar(&watchAnnots, "annotation", nil, "Match annotations: KEY (present), KEY=VALUE, or KEY=PREFIX* (repeatable, all must match)")
+	cmdWatch.Flags().StringArrayVar(&watchNameGlobs, "name-glob", nil, "Match names against a glob such as 'worker-*' (repeatable, any may match)")
+	cmdWatch.Flags().StringArrayVar(&watchTagGlobs, "tag-glob", nil, "Match processes with a tag matching a glob such as 'env-*' (repeatable, any may match)")
+	cmdWatch.Flags().StringVar(&watchSearchRegex, "search-regex", "", "Regular expression to match against the command, e.g. 'python .*manage.py'")
+	cmdWatch.Flags().StringVarP(&watchQuery, "query", "q", "", "Query expression, e.g. 'tag:db and (group:prod or name~^api-) and rss>500MB'")
+	cmdWatch.Flags().BoolVar(&watchJSON, "json", false, "Same as --output jsonl")
+	_ = cmdWatch.Flags().MarkDeprecated("json", "use --output jsonl")
+	cmdWatch.Flags().IntVar(&watchTimeout, "timeout", 3, "Timeout in seconds for contacting the daemon")
+}
+
+var cmdWatch = &cobra.Command{
+	Use:   "watch",
+	Short: "Stream registry changes as they happen",
+	Long: "Prints an event whenever a matching process is added, removed, dies, comes back, changes labels or is renamed, and when the registry is reset. Runs until interrupted. " +
+		"Each event is rendered on its own in the --output format; jsonl gives one JSON object per line.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if watchJSON {
+			if f := cmd.Flag("output"); f != nil && f.Changed && outputFormat != outputJSONL {
+				return usageErrorf("--json is --output jsonl and cannot be combined with --output %s", outputFormat)
+			}
+			outputFormat = outputJSONL
+		}
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		return controller().Watch(ctx, app.WatchParams{
+			Timeout: time.Duration(watchTimeout) * time.Second,
+			Filters: app.ListFilters{
+				TagsAny:    watchTagsAny,
+				TagsAll:    watchTagsAll,
+				GroupsAny:  watchGroupsAny,
+				GroupsAll:  watchGroupsAll,
+				Names:      watchNames,
+				AliveOnly:  watchAliveOnly,
+				TextSearch: watchTextSearch,
+				PIDs:       watchPIDs,
+				IDs:        watchIDs,
+
+				Annotations: watchAnnots,
+				Query:       watchQuery,
+				NameGlobs:   watchNameGlobs,
+				TagGlobs:    watchTagGlobs,
+				CmdRegex:    watchSearchRegex,
+			},
+		}, func(ev app.Event) error {
+			if outputFormat == outputYAML && outputTemplate == "" {
+				// One document per event.
+				if _, err := io.WriteString(cmd.OutOrStdout(), "---\n"); err != nil {
+					return err
+				}
+			}
+			return render(cmd, view{
+				data: ev,
+				text: func(w io.Writer) { fmt.Fprintln(w, formatEvent(ev)) },
+			})
+		})
+	},
+}
+
+func formatEvent(ev app.Event) string {
+	ts := ev.At.Format(time.RFC3339)
+	if ev.Process == nil {
+		return fmt.Sprintf("%s %s", ts, ev.Type)
+	}
+	proc := ev.Process
+	name := proc.Name
+	if name == "" {
+		name = "-"
+	}
+	line := fmt.Sprintf(
+		"%s %s [id=%d] pid=%d name=%s alive=%t tags=[%s] groups=[%s]",
+		ts,
+		ev.Type,
+		proc.ID,
+		proc.PID,
+		name,
+		proc.Alive,
+		strings.Join(proc.Tags, ","),
+		strings.Join(proc.Groups, ","),
+	)
+	if ev.PreviousName != "" {
+		line += " previous_name=" + ev.PreviousName
+	}
+	return line
+}
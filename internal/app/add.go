@@ -13,18 +13,22 @@ import (
 
 // AddParams configures PID registration.
 type AddParams struct {
-	PID     int
-	Tags    []string
-	Groups  []string
-	Name    string
-	Timeout time.Duration
+	PID    int
+	Tags   []string
+	Groups []string
+	Name   string
+	// Annotations are key/value pairs stored on the entry.
+	Annotations map[string]string
+	// TrackChildren registers descendants of the process as child entries.
+	TrackChildren bool
+	Timeout       time.Duration
 }
 
 // AddResult reports the daemon response.
 type AddResult struct {
-	ID             uint64
-	AlreadyExists  bool
-	ExistingReason string
+	ID             uint64 `json:"id"`
+	AlreadyExists  bool   `json:"already_exists"`
+	ExistingReason string `json:"existing_reason,omitempty"`
 }
 
 // Add registers a running PID with the daemon.
@@ -32,7 +36,7 @@ func (a *App) Add(ctx context.Context, params AddParams) (AddResult, error) {
 	var result AddResult
 
 	if params.PID <= 0 {
-		return result, fmt.Errorf("invalid pid %d", params.PID)
+		return result, invalidParamsf("invalid pid %d", params.PID)
 	}
 
 	name := strings.TrimSpace(params.Name)
@@ -45,6 +49,9 @@ func (a *App) Add(ctx context.Context, params AddParams) (AddResult, error) {
 			Tags:   tags,
 			Groups: groups,
 			Name:   name,
+
+			Annotations:   params.Annotations,
+			TrackChildren: params.TrackChildren,
 		})
 		if err != nil {
 			if st, ok := status.FromError(err); ok && st.Code() == codes.AlreadyExists {
@@ -0,0 +1,128 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	goprocv1 "goproc/api/proto/goproc/v1"
+)
+
+// Apply actions reported by the daemon.
+const (
+	ApplyCreate    = "create"
+	ApplyRestart   = "restart"
+	ApplyUpdate    = "update"
+	ApplyUnchanged = "unchanged"
+	ApplyPrune     = "prune"
+	ApplyConflict  = "conflict"
+)
+
+// ApplyParams configures a manifest apply.
+type ApplyParams struct {
+	// Manifest names the set of entries the processes belong to; prune
+	// only touches entries applied under the same name.
+	Manifest string
+	// Processes are the declared processes. Name is required and Env is
+	// added to the daemon's environment; Timeout is ignored.
+	Processes []SpawnParams
+	// Prune stops and removes entries of the manifest that are no longer
+	// declared.
+	Prune bool
+	// DryRun only reports the plan.
+	DryRun bool
+	// Grace is how long changed or pruned processes get to exit after
+	// SIGTERM before they are killed.
+	Grace   time.Duration
+	Timeout time.Duration
+}
+
+// ApplyAction is one step of an apply plan and, unless it was a dry run, its
+// outcome.
+type ApplyAction struct {
+	Name   string `json:"name"`
+	Action string `json:"action"`
+	Reason string `json:"reason,omitempty"`
+	ID     uint64 `json:"id,omitempty"`
+	PID    int    `json:"pid,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ApplyResult lists the actions in manifest order, followed by prunes.
+type ApplyResult struct {
+	Actions []ApplyAction `json:"actions"`
+	DryRun  bool          `json:"dry_run,omitempty"`
+}
+
+// Apply asks the daemon to reconcile the manifest's entries with the declared
+// processes. Failed actions (including name conflicts) are reported in the
+// result and make the returned error match ErrPartialFailure.
+func (a *App) Apply(ctx context.Context, params ApplyParams) (ApplyResult, error) {
+	result := ApplyResult{DryRun: params.DryRun}
+
+	manifest := strings.TrimSpace(params.Manifest)
+	if manifest == "" {
+		return result, invalidParamsf("manifest name must not be empty")
+	}
+	if params.Grace < 0 {
+		return result, invalidParamsf("grace must not be negative")
+	}
+	req := &goprocv1.ApplyRequest{
+		Manifest: manifest,
+		Prune:    params.Prune,
+		DryRun:   params.DryRun,
+		GraceMs:  params.Grace.Milliseconds(),
+	}
+	seen := make(map[string]struct{}, len(params.Processes))
+	for _, p := range params.Processes {
+		name := strings.TrimSpace(p.Name)
+		if name == "" {
+			return result, invalidParamsf("every process needs a name")
+		}
+		if _, dup := seen[name]; dup {
+			return result, invalidParamsf("process %q is declared twice", name)
+		}
+		seen[name] = struct{}{}
+		spawn, err := spawnRequest(p)
+		if err != nil {
+			return result, invalidParamsf("process %q: %w", name, err)
+		}
+		req.Processes = append(req.Processes, spawn)
+	}
+
+	timeout := params.Timeout
+	if !params.DryRun {
+		timeout += params.Grace + killEscalationWait
+	}
+	err := a.withClient(ctx, timeout, func(ctx context.Context, client goprocv1.GoProcClient) error {
+		resp, err := client.Apply(ctx, req)
+		if err != nil {
+			return fmt.Errorf("daemon apply RPC failed: %w", err)
+		}
+		for _, act := range resp.GetActions() {
+			result.Actions = append(result.Actions, ApplyAction{
+				Name:   act.GetName(),
+				Action: act.GetAction(),
+				Reason: act.GetReason(),
+				ID:     act.GetId(),
+				PID:    int(act.GetPid()),
+				Error:  act.GetError(),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+	failed := 0
+	for _, act := range result.Actions {
+		if act.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return result, bulkErrorf("%d of %d apply actions failed", failed, len(result.Actions))
+	}
+	return result, nil
+}
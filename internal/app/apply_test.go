@@ -0,0 +1,69 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	goprocv1 "goproc/api/proto/goproc/v1"
+)
+
+func TestAppApplyRejectsInvalidParams(t *testing.T) {
+	app := New(Options{})
+	cases := []ApplyParams{
+		{},
+		{Manifest: "dev", Grace: -time.Second},
+		{Manifest: "dev", Processes: []SpawnParams{{Argv: []string{"sleep"}}}},
+		{Manifest: "dev", Processes: []SpawnParams{{Name: "a", Argv: []string{"sleep"}}, {Name: " a ", Argv: []string{"true"}}}},
+		{Manifest: "dev", Processes: []SpawnParams{{Name: "a"}}},
+	}
+	for _, params := range cases {
+		if _, err := app.Apply(context.Background(), params); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("Apply(%+v) = %v, want ErrInvalidParams", params, err)
+		}
+	}
+}
+
+func TestAppApplySendsManifest(t *testing.T) {
+	stubDaemon(t, true, func(context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		conn := &fakeConn{
+			invoke: func(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+				req := args.(*goprocv1.ApplyRequest)
+				if req.GetManifest() != "dev" || !req.GetPrune() || req.GetGraceMs() != 2000 || len(req.GetProcesses()) != 2 {
+					t.Fatalf("unexpected request: %+v", req)
+				}
+				if p := req.GetProcesses()[0]; p.GetName() != "api" || p.GetArgv()[0] != "./api" || p.GetEnv()[0] != "PORT=8080" {
+					t.Fatalf("unexpected process: %+v", p)
+				}
+				resp := reply.(*goprocv1.ApplyResponse)
+				resp.Actions = []*goprocv1.ApplyAction{
+					{Name: "api", Action: ApplyCreate, Id: 3, Pid: 300},
+					{Name: "worker", Action: ApplyConflict, Id: 1, Pid: 100, Error: "name taken"},
+				}
+				return nil
+			},
+		}
+		return goprocv1.NewGoProcClient(conn), conn, nil
+	})
+
+	app := New(Options{})
+	res, err := app.Apply(context.Background(), ApplyParams{
+		Manifest: "dev",
+		Processes: []SpawnParams{
+			{Name: "api", Argv: []string{"./api"}, Env: []string{"PORT=8080"}},
+			{Name: "worker", Argv: []string{"./worker"}},
+		},
+		Prune:   true,
+		Grace:   2 * time.Second,
+		Timeout: time.Second,
+	})
+	if !errors.Is(err, ErrPartialFailure) {
+		t.Fatalf("expected partial failure, got %v", err)
+	}
+	if len(res.Actions) != 2 || res.Actions[0].ID != 3 || res.Actions[0].PID != 300 || res.Actions[1].Error != "name taken" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
@@ -2,7 +2,6 @@ package app
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"io"
 	"time"
@@ -35,10 +34,10 @@ func resetDaemonDeps() {
 
 func (a *App) withClient(ctx context.Context, timeout time.Duration, fn func(context.Context, goprocv1.GoProcClient) error) error {
 	if timeout <= 0 {
-		return errors.New("timeout must be greater than 0")
+		return invalidParamsf("timeout must be greater than 0")
 	}
 	if !daemonIsRunning() {
-		return errors.New("daemon is not running")
+		return ErrDaemonNotRunning
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, timeout)
@@ -54,3 +53,44 @@ func (a *App) withClient(ctx context.Context, timeout time.Duration, fn func(con
 
 	return fn(ctx, client)
 }
+
+// withStream is like withClient, but the timeout only bounds connection setup:
+// fn runs under ctx so long-lived server streams are not cut off.
+func (a *App) withStream(ctx context.Context, timeout time.Duration, fn func(context.Context, goprocv1.GoProcClient) error) error {
+	if timeout <= 0 {
+		return invalidParamsf("timeout must be greater than 0")
+	}
+	if !daemonIsRunning() {
+		return ErrDaemonNotRunning
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, timeout)
+	client, conn, err := dialDaemonClient(dialCtx)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	if conn != nil {
+		defer conn.Close()
+	}
+
+	return fn(ctx, client)
+}
+
+// resolveOne lists entries matching req and requires exactly one match.
+func resolveOne(ctx context.Context, client goprocv1.GoProcClient, req *goprocv1.ListRequest, timeout time.Duration) (Process, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	resp, err := client.List(ctx, req)
+	if err != nil {
+		return Process{}, fmt.Errorf("daemon list RPC failed: %w", err)
+	}
+	switch procs := resp.GetProcs(); len(procs) {
+	case 0:
+		return Process{}, ErrNoMatch
+	case 1:
+		return procFromProto(procs[0]), nil
+	default:
+		return Process{}, ambiguousf("multiple processes match the selector (ids: %s); narrow the selection", joinSampleIDs(procs))
+	}
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDaemonNotRunning is returned when no daemon is listening on the socket.
+var ErrDaemonNotRunning = errors.New("daemon is not running")
+
+// ErrInvalidParams is matched (via errors.Is) by errors reporting parameters
+// that were rejected before contacting the daemon: a missing selector, a
+// malformed filter, a negative duration and so on.
+var ErrInvalidParams = errors.New("invalid parameters")
+
+// ErrNoMatch is returned when a selector that must resolve to one process
+// matches none.
+var ErrNoMatch = errors.New("no process matches the selector")
+
+// ErrAmbiguous is matched by errors from commands that act on a single
+// process (or need --all) when the selector matches several.
+var ErrAmbiguous = errors.New("selector matches multiple processes")
+
+// ErrPartialFailure is matched by errors from bulk operations that failed for
+// some or all of the selected processes; the per-process outcome is in the
+// accompanying result.
+var ErrPartialFailure = errors.New("operation failed for some processes")
+
+// classError keeps its own message while matching one of the sentinels above.
+type classError struct {
+	err   error
+	class error
+}
+
+func (e *classError) Error() string { return e.err.Error() }
+
+func (e *classError) Unwrap() error { return e.err }
+
+func (e *classError) Is(target error) bool { return target == e.class }
+
+func invalidParamsf(format string, args ...any) error {
+	return &classError{err: fmt.Errorf(format, args...), class: ErrInvalidParams}
+}
+
+func ambiguousf(format string, args ...any) error {
+	return &classError{err: fmt.Errorf(format, args...), class: ErrAmbiguous}
+}
+
+func bulkErrorf(format string, args ...any) error {
+	return &classError{err: fmt.Errorf(format, args...), class: ErrPartialFailure}
+}
@@ -0,0 +1,150 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	goprocv1 "goproc/api/proto/goproc/v1"
+)
+
+// ExportParams selects the entries to export.
+type ExportParams struct {
+	Filters ListFilters
+	Timeout time.Duration
+}
+
+// ExportResult is an exported registry document, ready to be written to a
+// file and passed to Import.
+type ExportResult struct {
+	Document []byte
+	Count    int
+}
+
+// Export asks the daemon for a document holding the matching entries.
+func (a *App) Export(ctx context.Context, params ExportParams) (ExportResult, error) {
+	var result ExportResult
+	req, err := params.Filters.buildRequest()
+	if err != nil {
+		return result, err
+	}
+	err = a.withClient(ctx, params.Timeout, func(ctx context.Context, client goprocv1.GoProcClient) error {
+		resp, err := client.Export(ctx, &goprocv1.ExportRequest{Filter: req})
+		if err != nil {
+			return fmt.Errorf("daemon export RPC failed: %w", err)
+		}
+		result.Document = resp.GetDocument()
+		result.Count = int(resp.GetCount())
+		return nil
+	})
+	return result, err
+}
+
+// Import actions reported by the daemon.
+const (
+	ImportAdded    = "added"
+	ImportRenamed  = "renamed"
+	ImportReplaced = "replaced"
+	ImportSkipped  = "skipped"
+)
+
+var importConflictPolicies = []string{"rename", "skip", "replace", "fail"}
+
+// ImportParams configures an import.
+type ImportParams struct {
+	// Document is the output of Export.
+	Document []byte
+	// Replace removes every existing entry first; otherwise the imported
+	// entries are merged into the registry.
+	Replace bool
+	// OnConflict decides what happens to an imported entry whose name is
+	// taken: rename (default), skip, replace or fail.
+	OnConflict string
+	// Readopt tracks entries whose process is still running again. Other
+	// entries are imported dead, detached from their PID.
+	Readopt bool
+	// Respawn starts the entries the daemon had started that are not
+	// running.
+	Respawn bool
+	Timeout time.Duration
+}
+
+// ImportEntry is the outcome for one entry of the document.
+type ImportEntry struct {
+	FromID     uint64 `json:"from_id"`
+	ID         uint64 `json:"id,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Action     string `json:"action"`
+	Alive      bool   `json:"alive"`
+	PID        int    `json:"pid,omitempty"`
+	Respawned  bool   `json:"respawned,omitempty"`
+	ReplacedID uint64 `json:"replaced_id,omitempty"`
+	Note       string `json:"note,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ImportResult lists the entries in the order of their original IDs.
+type ImportResult struct {
+	Entries []ImportEntry `json:"entries"`
+	// Removed counts the existing entries removed by Replace or the replace
+	// conflict policy.
+	Removed int `json:"removed"`
+}
+
+// Import asks the daemon to add the entries of an exported document under
+// new IDs. Entries that could not be respawned are reported in the result
+// and make the returned error match ErrPartialFailure.
+func (a *App) Import(ctx context.Context, params ImportParams) (ImportResult, error) {
+	var result ImportResult
+	if len(params.Document) == 0 {
+		return result, invalidParamsf("import document is empty")
+	}
+	policy := strings.ToLower(strings.TrimSpace(params.OnConflict))
+	if policy != "" && !slices.Contains(importConflictPolicies, policy) {
+		return result, invalidParamsf("invalid conflict policy %q (expected %s)", params.OnConflict, strings.Join(importConflictPolicies, ", "))
+	}
+	req := &goprocv1.ImportRequest{
+		Document:   params.Document,
+		Replace:    params.Replace,
+		OnConflict: policy,
+		Readopt:    params.Readopt,
+		Respawn:    params.Respawn,
+	}
+	err := a.withClient(ctx, params.Timeout, func(ctx context.Context, client goprocv1.GoProcClient) error {
+		resp, err := client.Import(ctx, req)
+		if err != nil {
+			return fmt.Errorf("daemon import RPC failed: %w", err)
+		}
+		result.Removed = int(resp.GetRemoved())
+		for _, r := range resp.GetResults() {
+			result.Entries = append(result.Entries, ImportEntry{
+				FromID:     r.GetFromId(),
+				ID:         r.GetId(),
+				Name:       r.GetName(),
+				Action:     r.GetAction(),
+				Alive:      r.GetAlive(),
+				PID:        int(r.GetPid()),
+				Respawned:  r.GetRespawned(),
+				ReplacedID: r.GetReplacedId(),
+				Note:       r.GetNote(),
+				Error:      r.GetError(),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+	failed := 0
+	for _, e := range result.Entries {
+		if e.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return result, bulkErrorf("%d of %d imported processes could not be respawned", failed, len(result.Entries))
+	}
+	return result, nil
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	goprocv1 "goproc/api/proto/goproc/v1"
+)
+
+func TestAppExportSendsFilter(t *testing.T) {
+	stubDaemon(t, true, func(context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		conn := &fakeConn{
+			invoke: func(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+				if method != goprocv1.GoProc_Export_FullMethodName {
+					t.Fatalf("unexpected method %s", method)
+				}
+				req := args.(*goprocv1.ExportRequest)
+				if got := req.GetFilter().GetTagsAny(); len(got) != 1 || got[0] != "web" {
+					t.Fatalf("filter not forwarded: %+v", req.GetFilter())
+				}
+				resp := reply.(*goprocv1.ExportResponse)
+				resp.Document, resp.Count = []byte(`{"version": 3}`), 2
+				return nil
+			},
+		}
+		return goprocv1.NewGoProcClient(conn), conn, nil
+	})
+
+	res, err := New(Options{}).Export(context.Background(), ExportParams{Filters: ListFilters{TagsAny: []string{"web"}}, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if string(res.Document) != `{"version": 3}` || res.Count != 2 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestAppImportRejectsInvalidParams(t *testing.T) {
+	app := New(Options{})
+	cases := []ImportParams{
+		{},
+		{Document: []byte("{}"), OnConflict: "merge"},
+	}
+	for _, params := range cases {
+		if _, err := app.Import(context.Background(), params); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("Import(%+v) = %v, want ErrInvalidParams", params, err)
+		}
+	}
+}
+
+func TestAppImportReportsFailedRespawns(t *testing.T) {
+	stubDaemon(t, true, func(context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		conn := &fakeConn{
+			invoke: func(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+				if method != goprocv1.GoProc_Import_FullMethodName {
+					t.Fatalf("unexpected method %s", method)
+				}
+				req := args.(*goprocv1.ImportRequest)
+				if string(req.GetDocument()) != "{}" || !req.GetReplace() || req.GetOnConflict() != "skip" || !req.GetReadopt() || !req.GetRespawn() {
+					t.Fatalf("unexpected request: %+v", req)
+				}
+				resp := reply.(*goprocv1.ImportResponse)
+				resp.Removed = 1
+				resp.Results = []*goprocv1.ImportResult{
+					{FromId: 1, Id: 5, Name: "api", Action: "added", Alive: true, Pid: 10, Respawned: true},
+					{FromId: 2, Id: 6, Name: "web", Action: "added", Pid: 20, Error: "exec: not found"},
+				}
+				return nil
+			},
+		}
+		return goprocv1.NewGoProcClient(conn), conn, nil
+	})
+
+	res, err := New(Options{}).Import(context.Background(), ImportParams{
+		Document:   []byte("{}"),
+		Replace:    true,
+		OnConflict: " Skip",
+		Readopt:    true,
+		Respawn:    true,
+		Timeout:    time.Second,
+	})
+	if !errors.Is(err, ErrPartialFailure) {
+		t.Fatalf("Import error = %v, want ErrPartialFailure", err)
+	}
+	if res.Removed != 1 || len(res.Entries) != 2 || !res.Entries[0].Respawned || res.Entries[0].PID != 10 || res.Entries[1].Error == "" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
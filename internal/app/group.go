@@ -2,7 +2,6 @@ package app
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -19,9 +18,9 @@ type GroupParams struct {
 
 // GroupResult aggregates rename/list for groups.
 type GroupResult struct {
-	RenameInfo *RenameInfo
-	Processes  []Process
-	Message    string
+	RenameInfo *RenameInfo `json:"rename,omitempty"`
+	Processes  []Process   `json:"processes"`
+	Message    string      `json:"message,omitempty"`
 }
 
 // Group lists and optionally renames groups.
@@ -30,7 +29,7 @@ func (a *App) Group(ctx context.Context, params GroupParams) (GroupResult, error
 
 	name := strings.TrimSpace(params.Name)
 	if name == "" {
-		return result, errors.New("group must not be empty")
+		return result, invalidParamsf("group must not be empty")
 	}
 
 	req := &goprocv1.ListRequest{
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	goprocv1 "goproc/api/proto/goproc/v1"
+)
+
+// HistoryParams selects removed entries from the daemon's history.
+type HistoryParams struct {
+	// Filters are matched against the final state of each entry.
+	Filters ListFilters
+	// Since keeps entries removed at or after it; zero keeps all retained.
+	Since time.Time
+	// Reasons keeps entries removed for any of these reasons (rm, kill,
+	// reset, gc, import) or processes that died while their entry was kept
+	// (died); empty keeps all.
+	Reasons []string
+	// Limit caps the result to the newest matches; 0 returns all.
+	Limit   int
+	Timeout time.Duration
+}
+
+// HistoryEntry is the final state of a removed entry or a dead process.
+type HistoryEntry struct {
+	Seq       uint64    `json:"seq"`
+	RemovedAt time.Time `json:"removed_at"`
+	Reason    string    `json:"reason"`
+	Process   Process   `json:"process"`
+}
+
+var historyReasons = []string{"rm", "kill", "reset", "gc", "import", "died"}
+
+// History fetches removed entries matching params, newest first.
+func (a *App) History(ctx context.Context, params HistoryParams) ([]HistoryEntry, error) {
+	if params.Limit < 0 {
+		return nil, invalidParamsf("limit must not be negative")
+	}
+	reasons := make([]string, 0, len(params.Reasons))
+	for _, raw := range params.Reasons {
+		reason := strings.ToLower(strings.TrimSpace(raw))
+		if !slices.Contains(historyReasons, reason) {
+			return nil, invalidParamsf("invalid reason %q (expected %s)", raw, strings.Join(historyReasons, ", "))
+		}
+		reasons = append(reasons, reason)
+	}
+	req, err := params.Filters.buildRequest()
+	if err != nil {
+		return nil, err
+	}
+	hreq := &goprocv1.HistoryRequest{Filter: req, Reasons: reasons, Limit: uint32(params.Limit)}
+	if !params.Since.IsZero() {
+		hreq.SinceUnixMs = params.Since.UnixMilli()
+	}
+
+	var out []HistoryEntry
+	err = a.withClient(ctx, params.Timeout, func(ctx context.Context, client goprocv1.GoProcClient) error {
+		resp, err := client.History(ctx, hreq)
+		if err != nil {
+			return fmt.Errorf("daemon history RPC failed: %w", err)
+		}
+		out = make([]HistoryEntry, 0, len(resp.GetEntries()))
+		for _, t := range resp.GetEntries() {
+			out = append(out, HistoryEntry{
+				Seq:       t.GetSeq(),
+				RemovedAt: time.UnixMilli(t.GetRemovedAtUnixMs()),
+				Reason:    t.GetReason(),
+				Process:   procFromProto(t.GetProc()),
+			})
+		}
+		return nil
+	})
+	return out, err
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	goprocv1 "goproc/api/proto/goproc/v1"
+)
+
+func TestAppHistoryRejectsInvalidParams(t *testing.T) {
+	app := New(Options{})
+	if _, err := app.History(context.Background(), HistoryParams{Limit: -1}); err == nil || err.Error() != "limit must not be negative" {
+		t.Fatalf("expected limit error, got %v", err)
+	}
+	_, err := app.History(context.Background(), HistoryParams{Reasons: []string{"exit"}})
+	if err == nil || err.Error() != `invalid reason "exit" (expected rm, kill, reset, gc, import, died)` {
+		t.Fatalf("expected reason error, got %v", err)
+	}
+}
+
+func TestAppHistorySuccess(t *testing.T) {
+	var captured *goprocv1.HistoryRequest
+	stubDaemon(t, true, func(ctx context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		conn := &fakeConn{
+			invoke: func(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+				if method != goprocv1.GoProc_History_FullMethodName {
+					t.Fatalf("unexpected method %s", method)
+				}
+				captured = args.(*goprocv1.HistoryRequest)
+				reply.(*goprocv1.HistoryResponse).Entries = []*goprocv1.Tombstone{{
+					Seq:             7,
+					RemovedAtUnixMs: 5000,
+					Reason:          "kill",
+					Proc:            &goprocv1.Proc{Id: 3, Name: "api", Groups: []string{"prod"}},
+				}}
+				return nil
+			},
+		}
+		return goprocv1.NewGoProcClient(conn), conn, nil
+	})
+
+	app := New(Options{})
+	since := time.UnixMilli(1000)
+	entries, err := app.History(context.Background(), HistoryParams{
+		Filters: ListFilters{GroupsAny: []string{"prod"}},
+		Since:   since,
+		Reasons: []string{" Kill"},
+		Limit:   5,
+		Timeout: time.Second,
+	})
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if captured.GetSinceUnixMs() != 1000 || captured.GetLimit() != 5 || len(captured.GetReasons()) != 1 || captured.GetReasons()[0] != "kill" {
+		t.Fatalf("unexpected request: %+v", captured)
+	}
+	if got := captured.GetFilter().GetGroupsAny(); len(got) != 1 || got[0] != "prod" {
+		t.Fatalf("filter not forwarded: %+v", captured.GetFilter())
+	}
+	if len(entries) != 1 || entries[0].Seq != 7 || entries[0].Reason != "kill" || entries[0].Process.Name != "api" || !entries[0].RemovedAt.Equal(time.UnixMilli(5000)) {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+}
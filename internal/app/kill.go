@@ -2,12 +2,14 @@ package app
 
 import (
 	"context"
-	"errors"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	goprocv1 "goproc/api/proto/goproc/v1"
+	"goproc/internal/signals"
 )
 
 // KillParams configures kill command semantics.
@@ -16,29 +18,69 @@ type KillParams struct {
 	AllowAll        bool
 	Timeout         time.Duration
 	RequireSelector bool
+	// Signal is sent first; empty means SIGTERM.
+	Signal string
+	// Grace is how long the daemon waits for each process to exit.
+	Grace time.Duration
+	// Escalate sends SIGKILL to processes that outlive Grace.
+	Escalate bool
+	// NoGroup signals only the process instead of its process group.
+	NoGroup bool
 }
 
+// Kill outcomes reported by the daemon.
+const (
+	KillOutcomeExited    = "exited"
+	KillOutcomeEscalated = "escalated"
+	KillOutcomeRunning   = "running"
+)
+
 // KillEvent describes one action taken during kill/remove.
 type KillEvent struct {
-	Kind string
-	Proc Process
-	Err  error
+	Kind    string  `json:"kind"`
+	Proc    Process `json:"process"`
+	Outcome string  `json:"outcome,omitempty"`
+	Err     error   `json:"-"`
+}
+
+// MarshalJSON renders Err as its message.
+func (e KillEvent) MarshalJSON() ([]byte, error) {
+	type plain KillEvent
+	out := struct {
+		plain
+		Error string `json:"error,omitempty"`
+	}{plain: plain(e)}
+	if e.Err != nil {
+		out.Error = e.Err.Error()
+	}
+	return json.Marshal(out)
 }
 
 // KillResult aggregates the command outcome.
 type KillResult struct {
-	Events       []KillEvent
-	Message      string
-	TotalMatches int
-	TotalAlive   int
-	Successes    int
+	Events       []KillEvent `json:"events"`
+	Message      string      `json:"message,omitempty"`
+	TotalMatches int         `json:"total_matches"`
+	TotalAlive   int         `json:"total_alive"`
+	Successes    int         `json:"successes"`
 }
 
 // Kill terminates and removes processes that match the filters.
 func (a *App) Kill(ctx context.Context, params KillParams) (KillResult, error) {
 	var result KillResult
 	if params.RequireSelector && !params.AllowAll && emptySelectors(params.Filters) {
-		return result, errors.New("provide at least one selector (--id/--pid/--tag/--group/--name) or pass --all")
+		return result, invalidParamsf("provide at least one selector (--id/--pid/--tag/--group/--name/--query) or pass --all")
+	}
+
+	if params.Grace < 0 {
+		return result, invalidParamsf("grace must not be negative")
+	}
+	sig := ""
+	if params.Signal != "" {
+		var err error
+		if sig, err = ParseSignal(params.Signal); err != nil {
+			return result, err
+		}
 	}
 
 	req, err := params.Filters.buildRequest()
@@ -46,7 +88,15 @@ func (a *App) Kill(ctx context.Context, params KillParams) (KillResult, error) {
 		return result, err
 	}
 
-	err = a.withClient(ctx, params.Timeout, func(ctx context.Context, client goprocv1.GoProcClient) error {
+	// The daemon may block for the grace period (plus the SIGKILL wait) on
+	// every process, so the overall deadline must cover that on top of the
+	// RPC timeout. Processes are killed concurrently.
+	timeout := params.Timeout
+	if params.Grace > 0 || params.Escalate {
+		timeout += params.Grace + killEscalationWait
+	}
+
+	err = a.withClient(ctx, timeout, func(ctx context.Context, client goprocv1.GoProcClient) error {
 		resp, err := client.List(ctx, req)
 		if err != nil {
 			return fmt.Errorf("daemon list RPC failed: %w", err)
@@ -71,11 +121,33 @@ func (a *App) Kill(ctx context.Context, params KillParams) (KillResult, error) {
 		}
 
 		if len(alive) > 1 && !params.AllowAll {
-			return fmt.Errorf("multiple alive processes match filters (ids: %s). Use --all to terminate all or narrow the selection", joinProcessesSample(alive))
+			return ambiguousf("multiple alive processes match filters (ids: %s). Use --all to terminate all or narrow the selection", joinProcessesSample(alive))
 		}
 
-		for _, proc := range alive {
-			if _, err := client.Kill(ctx, &goprocv1.KillRequest{Target: &goprocv1.KillRequest_Id{Id: proc.ID}}); err != nil {
+		type killReply struct {
+			resp *goprocv1.KillResponse
+			err  error
+		}
+		replies := make([]killReply, len(alive))
+		var wg sync.WaitGroup
+		for i, proc := range alive {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				resp, err := client.Kill(ctx, &goprocv1.KillRequest{
+					Target:   &goprocv1.KillRequest_Id{Id: proc.ID},
+					Signal:   sig,
+					GraceMs:  params.Grace.Milliseconds(),
+					Escalate: params.Escalate,
+					NoGroup:  params.NoGroup,
+				})
+				replies[i] = killReply{resp: resp, err: err}
+			}()
+		}
+		wg.Wait()
+
+		for i, proc := range alive {
+			if err := replies[i].err; err != nil {
 				result.Events = append(result.Events, KillEvent{
 					Kind: "kill_failure",
 					Proc: proc,
@@ -83,17 +155,29 @@ func (a *App) Kill(ctx context.Context, params KillParams) (KillResult, error) {
 				})
 				continue
 			}
-			if _, err := client.Rm(ctx, &goprocv1.RmRequest{Id: proc.ID}); err != nil {
+			outcome := replies[i].resp.GetOutcome()
+			if outcome == KillOutcomeRunning {
+				// Keep the entry: the process is still there to be managed.
 				result.Events = append(result.Events, KillEvent{
-					Kind: "remove_failure",
-					Proc: proc,
-					Err:  fmt.Errorf("remove id %d failed: %w", proc.ID, err),
+					Kind:    "still_running",
+					Proc:    proc,
+					Outcome: outcome,
+				})
+				continue
+			}
+			if _, err := client.Rm(ctx, &goprocv1.RmRequest{Id: proc.ID, Reason: "kill"}); err != nil {
+				result.Events = append(result.Events, KillEvent{
+					Kind:    "remove_failure",
+					Proc:    proc,
+					Outcome: outcome,
+					Err:     fmt.Errorf("remove id %d failed: %w", proc.ID, err),
 				})
 				continue
 			}
 			result.Events = append(result.Events, KillEvent{
-				Kind: "success",
-				Proc: proc,
+				Kind:    "success",
+				Proc:    proc,
+				Outcome: outcome,
 			})
 			result.Successes++
 		}
@@ -107,10 +191,23 @@ func (a *App) Kill(ctx context.Context, params KillParams) (KillResult, error) {
 	case result.Successes == result.TotalAlive:
 		return result, nil
 	case result.Successes == 0 && result.TotalAlive > 0:
-		return result, errors.New("no processes were killed (see output above)")
+		return result, bulkErrorf("no processes were killed (see output above)")
 	default:
-		return result, fmt.Errorf("partially successful: killed %d/%d processes", result.Successes, result.TotalAlive)
+		return result, bulkErrorf("partially successful: killed %d/%d processes", result.Successes, result.TotalAlive)
+	}
+}
+
+// killEscalationWait covers the daemon's wait after SIGKILL.
+const killEscalationWait = 3 * time.Second
+
+// ParseSignal validates a signal given as "TERM", "SIGTERM" or a number and
+// returns its canonical name. Unknown signals match ErrInvalidParams.
+func ParseSignal(raw string) (string, error) {
+	sig, err := signals.Parse(raw)
+	if err != nil {
+		return "", invalidParamsf("%w", err)
 	}
+	return signals.Name(sig), nil
 }
 
 func joinProcessesSample(procs []Process) string {
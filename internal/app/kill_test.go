@@ -18,7 +18,7 @@ func TestAppKillRequiresSelector(t *testing.T) {
 		Timeout:         time.Second,
 		RequireSelector: true,
 	})
-	if err == nil || err.Error() != "provide at least one selector (--id/--pid/--tag/--group/--name) or pass --all" {
+	if err == nil || err.Error() != "provide at least one selector (--id/--pid/--tag/--group/--name/--query) or pass --all" {
 		t.Fatalf("expected selector error, got %v", err)
 	}
 }
@@ -241,3 +241,86 @@ func TestAppKillSuccess(t *testing.T) {
 		t.Fatalf("unexpected result: %+v", res)
 	}
 }
+
+func TestAppKillPassesSignalOptions(t *testing.T) {
+	stubDaemon(t, true, func(context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		conn := &fakeConn{
+			invoke: func(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+				switch req := args.(type) {
+				case *goprocv1.ListRequest:
+					resp := reply.(*goprocv1.ListResponse)
+					resp.Procs = []*goprocv1.Proc{{Id: 3, Alive: true}}
+				case *goprocv1.KillRequest:
+					if req.GetSignal() != "SIGINT" || req.GetGraceMs() != 1500 || !req.GetEscalate() || !req.GetNoGroup() {
+						t.Fatalf("unexpected kill request: %+v", req)
+					}
+					reply.(*goprocv1.KillResponse).Outcome = KillOutcomeEscalated
+				case *goprocv1.RmRequest:
+				default:
+					t.Fatalf("unexpected args %T", args)
+				}
+				return nil
+			},
+		}
+		return goprocv1.NewGoProcClient(conn), conn, nil
+	})
+
+	app := New(Options{})
+	res, err := app.Kill(context.Background(), KillParams{
+		Filters:  ListFilters{IDs: []int{3}},
+		Timeout:  time.Second,
+		Signal:   "int",
+		Grace:    1500 * time.Millisecond,
+		Escalate: true,
+		NoGroup:  true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Events) != 1 || res.Events[0].Kind != "success" || res.Events[0].Outcome != KillOutcomeEscalated {
+		t.Fatalf("unexpected events: %+v", res.Events)
+	}
+}
+
+func TestAppKillKeepsRunningProcess(t *testing.T) {
+	stubDaemon(t, true, func(context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		conn := &fakeConn{
+			invoke: func(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+				switch args.(type) {
+				case *goprocv1.ListRequest:
+					resp := reply.(*goprocv1.ListResponse)
+					resp.Procs = []*goprocv1.Proc{{Id: 4, Alive: true}}
+				case *goprocv1.KillRequest:
+					reply.(*goprocv1.KillResponse).Outcome = KillOutcomeRunning
+				default:
+					t.Fatalf("unexpected args %T", args)
+				}
+				return nil
+			},
+		}
+		return goprocv1.NewGoProcClient(conn), conn, nil
+	})
+
+	app := New(Options{})
+	res, err := app.Kill(context.Background(), KillParams{
+		Filters: ListFilters{IDs: []int{4}},
+		Timeout: time.Second,
+	})
+	if err == nil || err.Error() != "no processes were killed (see output above)" {
+		t.Fatalf("expected failure summary, got %v", err)
+	}
+	if len(res.Events) != 1 || res.Events[0].Kind != "still_running" {
+		t.Fatalf("unexpected events: %+v", res.Events)
+	}
+}
+
+func TestAppKillRejectsUnknownSignal(t *testing.T) {
+	app := New(Options{})
+	_, err := app.Kill(context.Background(), KillParams{
+		Filters: ListFilters{IDs: []int{1}},
+		Signal:  "BOGUS",
+	})
+	if !errors.Is(err, ErrInvalidParams) || err.Error() != `unknown signal "BOGUS"` {
+		t.Fatalf("expected invalid signal error, got %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	goprocv1 "goproc/api/proto/goproc/v1"
+)
+
+// LabelChanges lists tags and groups to add to or remove from processes.
+type LabelChanges struct {
+	AddTags      []string
+	RemoveTags   []string
+	AddGroups    []string
+	RemoveGroups []string
+}
+
+// Empty reports whether no change is requested.
+func (c LabelChanges) Empty() bool {
+	return len(c.AddTags)+len(c.RemoveTags)+len(c.AddGroups)+len(c.RemoveGroups) == 0
+}
+
+// UpdateLabelsParams configures a label update.
+type UpdateLabelsParams struct {
+	Filters  ListFilters
+	AllowAll bool
+	Changes  LabelChanges
+	Timeout  time.Duration
+}
+
+// UpdateLabelsResult reports the matching processes after the update.
+type UpdateLabelsResult struct {
+	Processes []Process `json:"processes"`
+	Updated   int       `json:"updated"`
+	Message   string    `json:"message,omitempty"`
+}
+
+// UpdateLabels adds and removes tags and groups on every process that matches
+// the filters. Without selectors AllowAll must be set.
+func (a *App) UpdateLabels(ctx context.Context, params UpdateLabelsParams) (UpdateLabelsResult, error) {
+	var result UpdateLabelsResult
+	if params.Changes.Empty() {
+		return result, invalidParamsf("no label changes requested")
+	}
+	if !params.AllowAll && emptySelectors(params.Filters) {
+		return result, invalidParamsf("provide at least one selector (--id/--pid/--tag/--group/--name/--query) or pass --all")
+	}
+
+	filter, err := params.Filters.buildRequest()
+	if err != nil {
+		return result, err
+	}
+
+	err = a.withClient(ctx, params.Timeout, func(ctx context.Context, client goprocv1.GoProcClient) error {
+		resp, err := client.UpdateLabels(ctx, &goprocv1.UpdateLabelsRequest{
+			Filter:       filter,
+			AddTags:      params.Changes.AddTags,
+			RemoveTags:   params.Changes.RemoveTags,
+			AddGroups:    params.Changes.AddGroups,
+			RemoveGroups: params.Changes.RemoveGroups,
+		})
+		if err != nil {
+			return fmt.Errorf("daemon update labels RPC failed: %w", err)
+		}
+		for _, p := range resp.GetProcs() {
+			result.Processes = append(result.Processes, procFromProto(p))
+		}
+		result.Updated = int(resp.GetUpdated())
+		if len(result.Processes) == 0 {
+			result.Message = "No processes match the provided selectors"
+		}
+		return nil
+	})
+	return result, err
+}
+
+// ParseLabelEdits reads a compact edit spec such as "+web -old +@backend -@batch":
+// "+x"/"-x" add or remove tag x, "+@x"/"-@x" add or remove group x.
+func ParseLabelEdits(spec string) (LabelChanges, error) {
+	var c LabelChanges
+	for _, tok := range strings.Fields(spec) {
+		op, label := tok[0], tok[1:]
+		if op != '+' && op != '-' {
+			return LabelChanges{}, invalidParamsf("invalid edit %q: start with + to add or - to remove", tok)
+		}
+		group := strings.HasPrefix(label, "@")
+		if group {
+			label = label[1:]
+		}
+		if label == "" {
+			return LabelChanges{}, invalidParamsf("invalid edit %q: missing label", tok)
+		}
+		switch {
+		case op == '+' && group:
+			c.AddGroups = append(c.AddGroups, label)
+		case op == '+':
+			c.AddTags = append(c.AddTags, label)
+		case group:
+			c.RemoveGroups = append(c.RemoveGroups, label)
+		default:
+			c.RemoveTags = append(c.RemoveTags, label)
+		}
+	}
+	if c.Empty() {
+		return LabelChanges{}, invalidParamsf("no label changes requested")
+	}
+	return c, nil
+}
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	goprocv1 "goproc/api/proto/goproc/v1"
+)
+
+func TestAppUpdateLabelsRequiresSelector(t *testing.T) {
+	app := New(Options{})
+	_, err := app.UpdateLabels(context.Background(), UpdateLabelsParams{
+		Changes: LabelChanges{AddTags: []string{"web"}},
+	})
+	if err == nil || err.Error() != "provide at least one selector (--id/--pid/--tag/--group/--name/--query) or pass --all" {
+		t.Fatalf("expected selector error, got %v", err)
+	}
+	_, err = app.UpdateLabels(context.Background(), UpdateLabelsParams{AllowAll: true})
+	if err == nil || err.Error() != "no label changes requested" {
+		t.Fatalf("expected empty change error, got %v", err)
+	}
+}
+
+func TestAppUpdateLabelsSendsChanges(t *testing.T) {
+	stubDaemon(t, true, func(context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		conn := &fakeConn{
+			invoke: func(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+				req := args.(*goprocv1.UpdateLabelsRequest)
+				if req.GetFilter().GetGroupsAny()[0] != "web" || req.GetAddTags()[0] != "canary" || req.GetRemoveGroups()[0] != "old" {
+					t.Fatalf("unexpected request: %+v", req)
+				}
+				resp := reply.(*goprocv1.UpdateLabelsResponse)
+				resp.Procs = []*goprocv1.Proc{{Id: 1, Tags: []string{"canary"}}, {Id: 2, Tags: []string{"canary"}}}
+				resp.Updated = 1
+				return nil
+			},
+		}
+		return goprocv1.NewGoProcClient(conn), conn, nil
+	})
+
+	app := New(Options{})
+	res, err := app.UpdateLabels(context.Background(), UpdateLabelsParams{
+		Filters: ListFilters{GroupsAny: []string{"web"}},
+		Changes: LabelChanges{AddTags: []string{"canary"}, RemoveGroups: []string{"old"}},
+		Timeout: time.Second,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Processes) != 2 || res.Updated != 1 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestParseLabelEdits(t *testing.T) {
+	got, err := ParseLabelEdits(" +web -old +@backend  -@batch ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := LabelChanges{
+		AddTags:      []string{"web"},
+		RemoveTags:   []string{"old"},
+		AddGroups:    []string{"backend"},
+		RemoveGroups: []string{"batch"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for _, spec := range []string{"", "web", "+", "-@"} {
+		if _, err := ParseLabelEdits(spec); err == nil {
+			t.Fatalf("expected error for %q", spec)
+		}
+	}
+}
@@ -6,32 +6,70 @@ import (
 	"time"
 
 	goprocv1 "goproc/api/proto/goproc/v1"
+	"goproc/internal/registry"
 )
 
-// ListParams defines filters and timeout.
+// ListParams defines filters, ordering, paging and timeout.
 type ListParams struct {
 	Filters ListFilters
+	// Sort is id (default), pid, name, added_at, last_seen, cpu or rss.
+	Sort string
+	Desc bool
+	// Limit caps the page size; 0 returns every match.
+	Limit int
+	// PageToken continues after a previous page (ListPageResult.NextPageToken).
+	PageToken string
+	// Fields restricts which process fields the daemon fills in (proto
+	// field names such as "pid" or "metrics"); empty means all.
+	Fields  []string
 	Timeout time.Duration
 }
 
+// ListPageResult is one page of a listing.
+type ListPageResult struct {
+	Processes     []Process
+	Total         int
+	NextPageToken string
+}
+
 // List fetches registry entries matching the provided filters.
 func (a *App) List(ctx context.Context, params ListParams) ([]Process, error) {
+	res, err := a.ListPage(ctx, params)
+	return res.Processes, err
+}
+
+// ListPage fetches one page of matching entries together with the total
+// number of matches and the token for the next page.
+func (a *App) ListPage(ctx context.Context, params ListParams) (ListPageResult, error) {
+	var result ListPageResult
 	req, err := params.Filters.buildRequest()
 	if err != nil {
-		return nil, err
+		return result, err
+	}
+	if _, err := registry.ParseSortKey(params.Sort); err != nil {
+		return result, invalidParamsf("%w", err)
+	}
+	if params.Limit < 0 {
+		return result, invalidParamsf("limit must not be negative")
 	}
+	req.Sort = params.Sort
+	req.Desc = params.Desc
+	req.Limit = uint32(params.Limit)
+	req.PageToken = params.PageToken
+	req.Fields = params.Fields
 
-	var procs []Process
 	err = a.withClient(ctx, params.Timeout, func(ctx context.Context, client goprocv1.GoProcClient) error {
 		resp, err := client.List(ctx, req)
 		if err != nil {
 			return fmt.Errorf("daemon list RPC failed: %w", err)
 		}
-		procs = make([]Process, 0, len(resp.GetProcs()))
+		result.Processes = make([]Process, 0, len(resp.GetProcs()))
 		for _, p := range resp.GetProcs() {
-			procs = append(procs, procFromProto(p))
+			result.Processes = append(result.Processes, procFromProto(p))
 		}
+		result.Total = int(resp.GetTotal())
+		result.NextPageToken = resp.GetNextPageToken()
 		return nil
 	})
-	return procs, err
+	return result, err
 }
@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"io"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"google.golang.org/grpc"
 	goprocv1 "goproc/api/proto/goproc/v1"
+	"goproc/internal/query"
 )
 
 func TestAppListRejectsInvalidNameFilter(t *testing.T) {
@@ -102,6 +105,7 @@ func TestAppListSuccess(t *testing.T) {
 			TextSearch: "search",
 			PIDs:       []int{99},
 			IDs:        []int{1},
+			Query:      "tag:db and rss>500MB",
 		},
 	}
 	app := New(Options{})
@@ -115,7 +119,269 @@ func TestAppListSuccess(t *testing.T) {
 	if captured == nil {
 		t.Fatal("expected captured request")
 	}
-	if captured.GetTextSearch() != params.Filters.TextSearch || !captured.GetAliveOnly() {
+	if captured.GetTextSearch() != params.Filters.TextSearch || !captured.GetAliveOnly() || captured.GetQuery() != params.Filters.Query {
 		t.Fatalf("filters not passed correctly: %+v", captured)
 	}
 }
+
+func TestAppListMapsMetrics(t *testing.T) {
+	stubDaemon(t, true, func(ctx context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		conn := &fakeConn{
+			invoke: func(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+				reply.(*goprocv1.ListResponse).Procs = []*goprocv1.Proc{
+					{Id: 1, Alive: true, Metrics: &goprocv1.ProcMetrics{CpuPercent: 12.5, RssBytes: 2048, Threads: 4, OpenFds: 7, SampledAtUnix: 300}},
+					{Id: 2},
+				}
+				return nil
+			},
+		}
+		return goprocv1.NewGoProcClient(conn), conn, nil
+	})
+	app := New(Options{})
+	procs, err := app.List(context.Background(), ListParams{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m := procs[0].Metrics
+	if m == nil || m.CPUPercent != 12.5 || m.RSS != 2048 || m.Threads != 4 || m.FDs != 7 || !m.SampledAt.Equal(time.Unix(300, 0)) {
+		t.Fatalf("unexpected metrics: %+v", m)
+	}
+	if procs[1].Metrics != nil {
+		t.Fatalf("expected no metrics for unsampled process, got %+v", procs[1].Metrics)
+	}
+}
+
+func TestAppListMapsProcessTree(t *testing.T) {
+	stubDaemon(t, true, func(ctx context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		conn := &fakeConn{
+			invoke: func(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+				reply.(*goprocv1.ListResponse).Procs = []*goprocv1.Proc{
+					{Id: 1, Pid: 30, Alive: true, TrackChildren: true, Children: []*goprocv1.ProcNode{
+						{Pid: 31, Cmd: "worker", Id: 2, Children: []*goprocv1.ProcNode{{Pid: 40, Cmd: "helper", Id: 3}}},
+					}},
+					{Id: 2, Pid: 31, Alive: true, ParentId: 1},
+				}
+				return nil
+			},
+		}
+		return goprocv1.NewGoProcClient(conn), conn, nil
+	})
+	app := New(Options{})
+	procs, err := app.List(context.Background(), ListParams{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []ProcNode{{PID: 31, Cmd: "worker", ID: 2, Children: []ProcNode{{PID: 40, Cmd: "helper", ID: 3}}}}
+	if !procs[0].TrackChildren || !reflect.DeepEqual(procs[0].Children, want) {
+		t.Fatalf("unexpected tree: %+v", procs[0])
+	}
+	if procs[1].ParentID != 1 || procs[1].Children != nil {
+		t.Fatalf("unexpected child entry: %+v", procs[1])
+	}
+}
+
+func TestAppListMapsExitStatus(t *testing.T) {
+	stubDaemon(t, true, func(ctx context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		conn := &fakeConn{
+			invoke: func(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+				reply.(*goprocv1.ListResponse).Procs = []*goprocv1.Proc{
+					{Id: 1, Exit: &goprocv1.ExitStatus{AtUnix: 50, Reason: "signaled", Code: -1, Signal: "SIGSEGV", CoreDumped: true}},
+					{Id: 2, Exit: &goprocv1.ExitStatus{AtUnix: 60, Reason: "exited", Code: 3}},
+					{Id: 3},
+				}
+				return nil
+			},
+		}
+		return goprocv1.NewGoProcClient(conn), conn, nil
+	})
+	app := New(Options{})
+	procs, err := app.List(context.Background(), ListParams{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e := procs[0].Exit; e == nil || e.String() != "SIGSEGV (core dumped)" || !e.At.Equal(time.Unix(50, 0)) {
+		t.Fatalf("unexpected signaled exit: %+v", e)
+	}
+	if e := procs[1].Exit; e == nil || e.String() != "code 3" {
+		t.Fatalf("unexpected exit: %+v", e)
+	}
+	if procs[2].Exit != nil {
+		t.Fatalf("expected no exit status, got %+v", procs[2].Exit)
+	}
+}
+
+func TestAppListPassesAnnotationSelectors(t *testing.T) {
+	var captured *goprocv1.ListRequest
+	stubDaemon(t, true, func(ctx context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		conn := &fakeConn{
+			invoke: func(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+				captured = args.(*goprocv1.ListRequest)
+				reply.(*goprocv1.ListResponse).Procs = []*goprocv1.Proc{
+					{Id: 1, Annotations: map[string]string{"owner": "alice"}},
+				}
+				return nil
+			},
+		}
+		return goprocv1.NewGoProcClient(conn), conn, nil
+	})
+
+	app := New(Options{})
+	procs, err := app.List(context.Background(), ListParams{
+		Timeout: time.Second,
+		Filters: ListFilters{Annotations: []string{"owner", "team=infra", "commit=ab12*"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(procs) != 1 || procs[0].Annotations["owner"] != "alice" {
+		t.Fatalf("annotations not mapped: %+v", procs)
+	}
+	want := []struct{ key, op, value string }{
+		{"owner", "exists", ""},
+		{"team", "equals", "infra"},
+		{"commit", "prefix", "ab12"},
+	}
+	if len(captured.GetAnnotations()) != len(want) {
+		t.Fatalf("unexpected selectors: %+v", captured.GetAnnotations())
+	}
+	for i, w := range want {
+		got := captured.GetAnnotations()[i]
+		if got.GetKey() != w.key || got.GetOp() != w.op || got.GetValue() != w.value {
+			t.Fatalf("selector %d = %+v, want %+v", i, got, w)
+		}
+	}
+}
+
+func TestAppListRejectsAnnotationSelectorWithoutKey(t *testing.T) {
+	app := New(Options{})
+	_, err := app.List(context.Background(), ListParams{
+		Timeout: time.Second,
+		Filters: ListFilters{Annotations: []string{"=value"}},
+	})
+	if err == nil || err.Error() != `invalid annotation filter "=value": missing key` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParseAnnotations(t *testing.T) {
+	got, err := ParseAnnotations([]string{"owner=alice", " port = 8080 ", "owner=bob", "empty="})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 3 || got["owner"] != "bob" || got["port"] != "8080" || got["empty"] != "" {
+		t.Fatalf("unexpected annotations: %v", got)
+	}
+	for _, bad := range []string{"novalue", "=x"} {
+		if _, err := ParseAnnotations([]string{bad}); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestAppListRejectsInvalidQueryBeforeDialing(t *testing.T) {
+	stubDaemon(t, true, func(ctx context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		t.Fatal("daemon should not be contacted")
+		return nil, nil, nil
+	})
+	app := New(Options{})
+	_, err := app.List(context.Background(), ListParams{
+		Timeout: time.Second,
+		Filters: ListFilters{Query: "tag:db and rsss>1"},
+	})
+	var qerr *query.Error
+	if !errors.As(err, &qerr) || qerr.Column() != 12 {
+		t.Fatalf("expected query error at column 12, got %v", err)
+	}
+}
+
+func TestAppListPassesPatterns(t *testing.T) {
+	var captured *goprocv1.ListRequest
+	stubDaemon(t, true, func(ctx context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		conn := &fakeConn{
+			invoke: func(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+				captured = args.(*goprocv1.ListRequest)
+				return nil
+			},
+		}
+		return goprocv1.NewGoProcClient(conn), conn, nil
+	})
+
+	app := New(Options{})
+	_, err := app.List(context.Background(), ListParams{
+		Timeout: time.Second,
+		Filters: ListFilters{
+			NameGlobs: []string{"worker-*", "job-?"},
+			TagGlobs:  []string{"env-[a-z]*"},
+			CmdRegex:  `python .*manage\.py`,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(captured.GetNameGlobs()) != 2 || captured.GetTagGlobs()[0] != "env-[a-z]*" || captured.GetCmdRegex() != `python .*manage\.py` {
+		t.Fatalf("patterns not passed correctly: %+v", captured)
+	}
+}
+
+func TestAppListRejectsInvalidPatterns(t *testing.T) {
+	app := New(Options{})
+	for _, tc := range []struct {
+		filters ListFilters
+		prefix  string
+	}{
+		{ListFilters{CmdRegex: "python ("}, "invalid cmd regex: "},
+		{ListFilters{NameGlobs: []string{"worker-[0-9"}}, "invalid name glob: "},
+		{ListFilters{TagGlobs: []string{""}}, "invalid tag glob: "},
+	} {
+		_, err := app.List(context.Background(), ListParams{Timeout: time.Second, Filters: tc.filters})
+		if err == nil || !strings.HasPrefix(err.Error(), tc.prefix) {
+			t.Fatalf("filters %+v: unexpected error %v", tc.filters, err)
+		}
+	}
+}
+
+func TestAppListPage(t *testing.T) {
+	var captured *goprocv1.ListRequest
+	stubDaemon(t, true, func(ctx context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		conn := &fakeConn{
+			invoke: func(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+				captured = args.(*goprocv1.ListRequest)
+				resp := reply.(*goprocv1.ListResponse)
+				resp.Procs = []*goprocv1.Proc{{Id: 4}, {Id: 2}}
+				resp.Total = 9
+				resp.NextPageToken = "tok2"
+				return nil
+			},
+		}
+		return goprocv1.NewGoProcClient(conn), conn, nil
+	})
+
+	app := New(Options{})
+	page, err := app.ListPage(context.Background(), ListParams{
+		Timeout:   time.Second,
+		Sort:      "rss",
+		Desc:      true,
+		Limit:     2,
+		PageToken: "tok1",
+		Fields:    []string{"pid", "name"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if captured.GetSort() != "rss" || !captured.GetDesc() || captured.GetLimit() != 2 || captured.GetPageToken() != "tok1" || len(captured.GetFields()) != 2 {
+		t.Fatalf("paging not passed correctly: %+v", captured)
+	}
+	if len(page.Processes) != 2 || page.Processes[0].ID != 4 || page.Total != 9 || page.NextPageToken != "tok2" {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+}
+
+func TestAppListPageRejectsInvalidParams(t *testing.T) {
+	app := New(Options{})
+	if _, err := app.ListPage(context.Background(), ListParams{Sort: "memory"}); err == nil || !strings.HasPrefix(err.Error(), `invalid sort key "memory"`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := app.ListPage(context.Background(), ListParams{Limit: -1}); err == nil || err.Error() != "limit must not be negative" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
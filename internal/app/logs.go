@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	goprocv1 "goproc/api/proto/goproc/v1"
+)
+
+// LogsParams configures log retrieval for a single process.
+type LogsParams struct {
+	Filters ListFilters
+	Stderr  bool
+	Follow  bool
+	Tail    int
+	Timeout time.Duration
+}
+
+// Logs writes captured output of the single process matching the filters to w.
+// With Follow it keeps streaming until ctx is cancelled.
+func (a *App) Logs(ctx context.Context, params LogsParams, w io.Writer) error {
+	if emptySelectors(params.Filters) {
+		return invalidParamsf("provide a selector for the process")
+	}
+	if params.Tail < 0 {
+		return invalidParamsf("invalid tail %d", params.Tail)
+	}
+	req, err := params.Filters.buildRequest()
+	if err != nil {
+		return err
+	}
+
+	return a.withStream(ctx, params.Timeout, func(ctx context.Context, client goprocv1.GoProcClient) error {
+		proc, err := resolveOne(ctx, client, req, params.Timeout)
+		if err != nil {
+			return err
+		}
+		stream, err := client.Logs(ctx, &goprocv1.LogsRequest{
+			Id:     proc.ID,
+			Stderr: params.Stderr,
+			Follow: params.Follow,
+			Tail:   int32(params.Tail),
+		})
+		if err != nil {
+			return fmt.Errorf("daemon logs RPC failed: %w", err)
+		}
+		for {
+			chunk, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("daemon logs RPC failed: %w", err)
+			}
+			if _, err := w.Write(chunk.GetData()); err != nil {
+				return err
+			}
+		}
+	})
+}
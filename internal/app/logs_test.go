@@ -0,0 +1,115 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	goprocv1 "goproc/api/proto/goproc/v1"
+)
+
+func TestAppLogsRequiresSelector(t *testing.T) {
+	app := New(Options{})
+	err := app.Logs(context.Background(), LogsParams{Timeout: time.Second}, io.Discard)
+	if err == nil || err.Error() != "provide a selector for the process" {
+		t.Fatalf("expected selector error, got %v", err)
+	}
+}
+
+func TestAppLogsDaemonNotRunning(t *testing.T) {
+	stubDaemon(t, false, nil)
+	app := New(Options{})
+	err := app.Logs(context.Background(), LogsParams{Filters: ListFilters{IDs: []int{1}}, Timeout: time.Second}, io.Discard)
+	if err == nil || err.Error() != "daemon is not running" {
+		t.Fatalf("expected daemon error, got %v", err)
+	}
+}
+
+func TestAppLogsMultipleMatches(t *testing.T) {
+	stubDaemon(t, true, func(ctx context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		conn := &fakeConn{
+			invoke: func(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+				reply.(*goprocv1.ListResponse).Procs = []*goprocv1.Proc{{Id: 1}, {Id: 2}}
+				return nil
+			},
+		}
+		return goprocv1.NewGoProcClient(conn), conn, nil
+	})
+	app := New(Options{})
+	err := app.Logs(context.Background(), LogsParams{Filters: ListFilters{Names: []string{"svc"}}, Timeout: time.Second}, io.Discard)
+	if err == nil || err.Error() != "multiple processes match the selector (ids: 1, 2); narrow the selection" {
+		t.Fatalf("expected multi-match error, got %v", err)
+	}
+}
+
+func TestAppLogsStreamsChunks(t *testing.T) {
+	var sent *goprocv1.LogsRequest
+	stubDaemon(t, true, func(ctx context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		chunks := []string{"hello ", "world\n"}
+		conn := &fakeConn{
+			invoke: func(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+				reply.(*goprocv1.ListResponse).Procs = []*goprocv1.Proc{{Id: 4}}
+				return nil
+			},
+		}
+		conn.stream = func(ctx context.Context, method string) (grpc.ClientStream, error) {
+			if method != goprocv1.GoProc_Logs_FullMethodName {
+				t.Fatalf("unexpected method %s", method)
+			}
+			fs := &fakeStream{ctx: ctx}
+			fs.recv = func(m interface{}) error {
+				sent, _ = fs.sent.(*goprocv1.LogsRequest)
+				if len(chunks) == 0 {
+					return io.EOF
+				}
+				m.(*goprocv1.LogChunk).Data = []byte(chunks[0])
+				chunks = chunks[1:]
+				return nil
+			}
+			return fs, nil
+		}
+		return goprocv1.NewGoProcClient(conn), conn, nil
+	})
+
+	var buf bytes.Buffer
+	app := New(Options{})
+	err := app.Logs(context.Background(), LogsParams{
+		Filters: ListFilters{IDs: []int{4}},
+		Stderr:  true,
+		Tail:    20,
+		Timeout: time.Second,
+	}, &buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != "hello world\n" {
+		t.Fatalf("unexpected output %q", buf.String())
+	}
+	if sent == nil || sent.GetId() != 4 || !sent.GetStderr() || sent.GetTail() != 20 {
+		t.Fatalf("unexpected request: %+v", sent)
+	}
+}
+
+func TestAppLogsStreamError(t *testing.T) {
+	stubDaemon(t, true, func(ctx context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		conn := &fakeConn{
+			invoke: func(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+				reply.(*goprocv1.ListResponse).Procs = []*goprocv1.Proc{{Id: 4}}
+				return nil
+			},
+			stream: func(ctx context.Context, method string) (grpc.ClientStream, error) {
+				return nil, errors.New("no stream")
+			},
+		}
+		return goprocv1.NewGoProcClient(conn), conn, nil
+	})
+	app := New(Options{})
+	err := app.Logs(context.Background(), LogsParams{Filters: ListFilters{IDs: []int{4}}, Timeout: time.Second}, io.Discard)
+	if err == nil || err.Error() != "daemon logs RPC failed: no stream" {
+		t.Fatalf("expected stream error, got %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	goprocv1 "goproc/api/proto/goproc/v1"
+)
+
+// SetNameParams configures the name command.
+type SetNameParams struct {
+	Filters ListFilters
+	Name    string
+	// Clear removes the name instead of setting one.
+	Clear   bool
+	Timeout time.Duration
+}
+
+// SetNameResult reports the renamed entry.
+type SetNameResult struct {
+	Process      Process `json:"process"`
+	PreviousName string  `json:"previous_name"`
+}
+
+// SetName names (or, with Clear, unnames) the single entry matching the filters.
+func (a *App) SetName(ctx context.Context, params SetNameParams) (SetNameResult, error) {
+	var result SetNameResult
+	name := strings.TrimSpace(params.Name)
+	switch {
+	case params.Clear && name != "":
+		return result, invalidParamsf("pass either a new name or --clear, not both")
+	case !params.Clear && name == "":
+		return result, invalidParamsf("new name must not be empty (use --clear to remove the name)")
+	}
+	if emptySelectors(params.Filters) {
+		return result, invalidParamsf("provide a selector for the process")
+	}
+
+	req, err := params.Filters.buildRequest()
+	if err != nil {
+		return result, err
+	}
+
+	err = a.withClient(ctx, params.Timeout, func(ctx context.Context, client goprocv1.GoProcClient) error {
+		resp, err := client.List(ctx, req)
+		if err != nil {
+			return fmt.Errorf("daemon list RPC failed: %w", err)
+		}
+		switch len(resp.GetProcs()) {
+		case 0:
+			return ErrNoMatch
+		case 1:
+		default:
+			return ambiguousf("multiple processes match filters (ids: %s); names must be unique", joinSampleIDs(resp.GetProcs()))
+		}
+
+		setResp, err := client.SetName(ctx, &goprocv1.SetNameRequest{Id: resp.GetProcs()[0].GetId(), Name: name})
+		if err != nil {
+			return fmt.Errorf("daemon set name RPC failed: %w", err)
+		}
+		result.Process = procFromProto(setResp.GetProc())
+		result.PreviousName = setResp.GetPreviousName()
+		return nil
+	})
+	return result, err
+}
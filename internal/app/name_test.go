@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	goprocv1 "goproc/api/proto/goproc/v1"
+)
+
+func TestAppSetNameValidatesInput(t *testing.T) {
+	app := New(Options{})
+	cases := []struct {
+		params SetNameParams
+		want   string
+	}{
+		{SetNameParams{Filters: ListFilters{IDs: []int{1}}}, "new name must not be empty (use --clear to remove the name)"},
+		{SetNameParams{Filters: ListFilters{IDs: []int{1}}, Name: "x", Clear: true}, "pass either a new name or --clear, not both"},
+		{SetNameParams{Name: "x"}, "provide a selector for the process"},
+	}
+	for _, tc := range cases {
+		if _, err := app.SetName(context.Background(), tc.params); err == nil || err.Error() != tc.want {
+			t.Fatalf("expected %q, got %v", tc.want, err)
+		}
+	}
+}
+
+func TestAppSetNameRenamesSingleMatch(t *testing.T) {
+	stubDaemon(t, true, func(context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		conn := &fakeConn{
+			invoke: func(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+				switch req := args.(type) {
+				case *goprocv1.ListRequest:
+					reply.(*goprocv1.ListResponse).Procs = []*goprocv1.Proc{{Id: 4, Name: "old"}}
+				case *goprocv1.SetNameRequest:
+					if req.GetId() != 4 || req.GetName() != "new" {
+						t.Fatalf("unexpected request: %+v", req)
+					}
+					resp := reply.(*goprocv1.SetNameResponse)
+					resp.Proc = &goprocv1.Proc{Id: 4, Name: "new"}
+					resp.PreviousName = "old"
+				default:
+					t.Fatalf("unexpected args %T", args)
+				}
+				return nil
+			},
+		}
+		return goprocv1.NewGoProcClient(conn), conn, nil
+	})
+
+	app := New(Options{})
+	res, err := app.SetName(context.Background(), SetNameParams{
+		Filters: ListFilters{Names: []string{"old"}},
+		Name:    " new ",
+		Timeout: time.Second,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Process.Name != "new" || res.PreviousName != "old" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestAppSetNameConflict(t *testing.T) {
+	stubDaemon(t, true, func(context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		conn := &fakeConn{
+			invoke: func(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+				if _, ok := args.(*goprocv1.ListRequest); ok {
+					reply.(*goprocv1.ListResponse).Procs = []*goprocv1.Proc{{Id: 1}}
+					return nil
+				}
+				return status.Error(codes.AlreadyExists, `name "api" is already in use`)
+			},
+		}
+		return goprocv1.NewGoProcClient(conn), conn, nil
+	})
+
+	app := New(Options{})
+	_, err := app.SetName(context.Background(), SetNameParams{
+		Filters: ListFilters{IDs: []int{1}},
+		Name:    "api",
+		Timeout: time.Second,
+	})
+	if err == nil || status.Code(errors.Unwrap(err)) != codes.AlreadyExists || !strings.Contains(err.Error(), "already in use") {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+}
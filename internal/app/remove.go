@@ -2,7 +2,6 @@ package app
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -20,15 +19,15 @@ type RemoveParams struct {
 
 // RemoveResult reports the registry entries removed.
 type RemoveResult struct {
-	Removed []Process
-	Message string
+	Removed []Process `json:"removed"`
+	Message string    `json:"message,omitempty"`
 }
 
 // Remove deletes registry entries matching the filters.
 func (a *App) Remove(ctx context.Context, params RemoveParams) (RemoveResult, error) {
 	var result RemoveResult
 	if params.RequireSelector && !params.AllowAll && emptySelectors(params.Filters) {
-		return result, errors.New("provide at least one selector (--id/--pid/--tag/--group/--name/--search)")
+		return result, invalidParamsf("provide at least one selector (--id/--pid/--tag/--group/--name/--search/--query)")
 	}
 
 	req, err := params.Filters.buildRequest()
@@ -46,7 +45,7 @@ func (a *App) Remove(ctx context.Context, params RemoveParams) (RemoveResult, er
 			return nil
 		}
 		if len(resp.GetProcs()) > 1 && !params.AllowAll {
-			return fmt.Errorf("multiple processes match filters (ids: %s). Use --all to delete all or narrow the selection", joinSampleIDs(resp.GetProcs()))
+			return ambiguousf("multiple processes match filters (ids: %s). Use --all to delete all or narrow the selection", joinSampleIDs(resp.GetProcs()))
 		}
 		for _, protoProc := range resp.GetProcs() {
 			if _, err := client.Rm(ctx, &goprocv1.RmRequest{Id: protoProc.GetId()}); err != nil {
@@ -68,7 +67,11 @@ func emptySelectors(filters ListFilters) bool {
 		len(filters.Names) == 0 &&
 		len(filters.PIDs) == 0 &&
 		len(filters.IDs) == 0 &&
-		strings.TrimSpace(filters.TextSearch) == ""
+		len(filters.Annotations) == 0 &&
+		strings.TrimSpace(filters.TextSearch) == "" &&
+		strings.TrimSpace(filters.Query) == "" &&
+		len(filters.NameGlobs)+len(filters.TagGlobs)+len(filters.GroupGlobs)+len(filters.CmdGlobs) == 0 &&
+		filters.NameRegex+filters.TagRegex+filters.GroupRegex+filters.CmdRegex == ""
 }
 
 func joinSampleIDs(procs []*goprocv1.Proc) string {
@@ -19,7 +19,7 @@ func TestAppRemoveRequiresSelector(t *testing.T) {
 		RequireSelector: true,
 		Timeout:         time.Second,
 	})
-	if err == nil || err.Error() != "provide at least one selector (--id/--pid/--tag/--group/--name/--search)" {
+	if err == nil || err.Error() != "provide at least one selector (--id/--pid/--tag/--group/--name/--search/--query)" {
 		t.Fatalf("expected selector error, got %v", err)
 	}
 }
@@ -2,7 +2,6 @@ package app
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"time"
 
@@ -18,7 +17,7 @@ type ResetParams struct {
 // Reset wipes registry state.
 func (a *App) Reset(ctx context.Context, params ResetParams) error {
 	if !params.Confirmed {
-		return errors.New(`destructive command: confirmation required`)
+		return invalidParamsf(`destructive command: confirmation required`)
 	}
 
 	return a.withClient(ctx, params.Timeout, func(ctx context.Context, client goprocv1.GoProcClient) error {
@@ -0,0 +1,124 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	goprocv1 "goproc/api/proto/goproc/v1"
+)
+
+// SignalParams configures the signal command.
+type SignalParams struct {
+	Signal   string
+	Filters  ListFilters
+	AllowAll bool
+	// ProcessGroup signals each entry's process group instead of its PID.
+	ProcessGroup bool
+	Timeout      time.Duration
+}
+
+// SignalEvent is the per-process result of a signal request.
+type SignalEvent struct {
+	Proc Process `json:"process"`
+	Err  error   `json:"-"`
+}
+
+// MarshalJSON renders Err as its message.
+func (e SignalEvent) MarshalJSON() ([]byte, error) {
+	type plain SignalEvent
+	out := struct {
+		plain
+		Error string `json:"error,omitempty"`
+	}{plain: plain(e)}
+	if e.Err != nil {
+		out.Error = e.Err.Error()
+	}
+	return json.Marshal(out)
+}
+
+// SignalResult aggregates the command outcome.
+type SignalResult struct {
+	Signal       string        `json:"signal"`
+	Events       []SignalEvent `json:"events"`
+	Message      string        `json:"message,omitempty"`
+	TotalMatches int           `json:"total_matches"`
+	TotalAlive   int           `json:"total_alive"`
+	Successes    int           `json:"successes"`
+}
+
+// Signal sends a signal to the alive processes that match the filters. The
+// registry entries are left untouched.
+func (a *App) Signal(ctx context.Context, params SignalParams) (SignalResult, error) {
+	var result SignalResult
+	sig, err := ParseSignal(params.Signal)
+	if err != nil {
+		return result, err
+	}
+	result.Signal = sig
+	if !params.AllowAll && emptySelectors(params.Filters) {
+		return result, invalidParamsf("provide at least one selector (--id/--pid/--tag/--group/--name/--query) or pass --all")
+	}
+
+	req, err := params.Filters.buildRequest()
+	if err != nil {
+		return result, err
+	}
+
+	err = a.withClient(ctx, params.Timeout, func(ctx context.Context, client goprocv1.GoProcClient) error {
+		resp, err := client.List(ctx, req)
+		if err != nil {
+			return fmt.Errorf("daemon list RPC failed: %w", err)
+		}
+
+		result.TotalMatches = len(resp.GetProcs())
+		if result.TotalMatches == 0 {
+			result.Message = "No processes match the provided selectors"
+			return nil
+		}
+
+		alive := make([]Process, 0, len(resp.GetProcs()))
+		for _, protoProc := range resp.GetProcs() {
+			if protoProc.GetAlive() {
+				alive = append(alive, procFromProto(protoProc))
+			}
+		}
+		result.TotalAlive = len(alive)
+		if len(alive) == 0 {
+			result.Message = "Matching processes exist but none are currently alive"
+			return nil
+		}
+
+		if len(alive) > 1 && !params.AllowAll {
+			return ambiguousf("multiple alive processes match filters (ids: %s). Use --all to signal all or narrow the selection", joinProcessesSample(alive))
+		}
+
+		for _, proc := range alive {
+			_, err := client.Signal(ctx, &goprocv1.SignalRequest{
+				Id:           proc.ID,
+				Signal:       sig,
+				ProcessGroup: params.ProcessGroup,
+			})
+			if err != nil {
+				err = fmt.Errorf("daemon signal RPC failed: %w", err)
+			} else {
+				result.Successes++
+			}
+			result.Events = append(result.Events, SignalEvent{Proc: proc, Err: err})
+		}
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	switch {
+	case result.Successes == result.TotalAlive:
+		return result, nil
+	case result.Successes == 0:
+		return result, bulkErrorf("no processes were signaled (see output above)")
+	default:
+		return result, bulkErrorf("partially successful: signaled %d/%d processes", result.Successes, result.TotalAlive)
+	}
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	goprocv1 "goproc/api/proto/goproc/v1"
+)
+
+func TestAppSignalValidatesInput(t *testing.T) {
+	app := New(Options{})
+	if _, err := app.Signal(context.Background(), SignalParams{Signal: "NOPE", Filters: ListFilters{IDs: []int{1}}}); !errors.Is(err, ErrInvalidParams) || err.Error() != `unknown signal "NOPE"` {
+		t.Fatalf("expected signal error, got %v", err)
+	}
+	if _, err := app.Signal(context.Background(), SignalParams{Signal: "HUP"}); err == nil || err.Error() != "provide at least one selector (--id/--pid/--tag/--group/--name/--query) or pass --all" {
+		t.Fatalf("expected selector error, got %v", err)
+	}
+}
+
+func TestAppSignalSendsToEachAliveMatch(t *testing.T) {
+	var signaled []uint64
+	stubDaemon(t, true, func(context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		conn := &fakeConn{
+			invoke: func(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+				switch req := args.(type) {
+				case *goprocv1.ListRequest:
+					resp := reply.(*goprocv1.ListResponse)
+					resp.Procs = []*goprocv1.Proc{{Id: 1, Alive: true}, {Id: 2, Alive: false}, {Id: 3, Alive: true}}
+				case *goprocv1.SignalRequest:
+					if req.GetSignal() != "SIGHUP" || !req.GetProcessGroup() {
+						t.Fatalf("unexpected signal request: %+v", req)
+					}
+					signaled = append(signaled, req.GetId())
+					if req.GetId() == 3 {
+						return errors.New("boom")
+					}
+				default:
+					t.Fatalf("unexpected args %T", args)
+				}
+				return nil
+			},
+		}
+		return goprocv1.NewGoProcClient(conn), conn, nil
+	})
+
+	app := New(Options{})
+	res, err := app.Signal(context.Background(), SignalParams{
+		Signal:       "hup",
+		Filters:      ListFilters{TagsAny: []string{"web"}},
+		AllowAll:     true,
+		ProcessGroup: true,
+		Timeout:      time.Second,
+	})
+	if err == nil || err.Error() != "partially successful: signaled 1/2 processes" {
+		t.Fatalf("expected partial failure, got %v", err)
+	}
+	if len(signaled) != 2 || signaled[0] != 1 || signaled[1] != 3 {
+		t.Fatalf("unexpected signaled ids: %v", signaled)
+	}
+	if res.Signal != "SIGHUP" || len(res.Events) != 2 || res.Events[0].Err != nil || res.Events[1].Err == nil {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"goproc/internal/config"
+	"goproc/internal/daemon"
+	"goproc/internal/registry"
+)
+
+// InspectSnapshotParams selects the snapshot to inspect.
+type InspectSnapshotParams struct {
+	// Store is the backend that wrote the file (config.StoreJSON or
+	// config.StoreBolt); empty means the store selected by the config.
+	Store string
+	// Path defaults to the daemon's snapshot, or its database for the bolt
+	// store, in the runtime directory.
+	Path string
+	// JournalPath defaults to the daemon's journal when Path is empty;
+	// otherwise the journal is only read if it is set.
+	JournalPath string
+}
+
+// SnapshotInfo summarises a registry snapshot file.
+type SnapshotInfo struct {
+	Store   string `json:"store"`
+	Path    string `json:"path"`
+	Version int    `json:"version"`
+	// Supported is the version this build writes; older snapshots are
+	// migrated (after a backup) when the daemon loads them.
+	Supported   int    `json:"supported_version"`
+	CreatedUnix int64  `json:"created_unix"`
+	NextID      uint64 `json:"next_id"`
+	Procs       int    `json:"procs"`
+	Alive       int    `json:"alive"`
+	Spawned     int    `json:"spawned"`
+	Named       int    `json:"named"`
+	Children    int    `json:"children"`
+	History     int    `json:"history"` // removed entries kept in the history
+	// JournalRecords counts the changes recorded since the snapshot was
+	// written; the daemon folds them in on its next compaction.
+	JournalPath    string `json:"journal_path,omitempty"`
+	JournalRecords int    `json:"journal_records"`
+}
+
+// InspectSnapshot reads a snapshot file directly, so it works whether or not
+// the daemon is running. The file is not migrated. A bolt database is locked
+// while the daemon uses it, so it can only be inspected when the daemon is
+// stopped or from a copy.
+func (a *App) InspectSnapshot(params InspectSnapshotParams) (SnapshotInfo, error) {
+	store := params.Store
+	if store == "" {
+		cfg, err := config.Load(a.cfgPath)
+		if err != nil {
+			return SnapshotInfo{}, err
+		}
+		store = cfg.Store
+	}
+	path, journalPath := params.Path, params.JournalPath
+	inspect := registry.InspectSnapshot
+	switch store {
+	case config.StoreJSON:
+		if path == "" {
+			path = daemon.SnapshotPath()
+			if journalPath == "" {
+				journalPath = daemon.JournalPath()
+			}
+		}
+	case config.StoreBolt:
+		if params.JournalPath != "" {
+			return SnapshotInfo{}, invalidParamsf("the bolt store has no journal")
+		}
+		if path == "" {
+			path = daemon.StorePath()
+		}
+		inspect = registry.InspectBoltStore
+	default:
+		return SnapshotInfo{}, invalidParamsf("unknown store %q (expected %s or %s)", store, config.StoreJSON, config.StoreBolt)
+	}
+	info, err := inspect(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return SnapshotInfo{Store: store, Path: path}, fmt.Errorf("no snapshot at %s", path)
+		}
+		return SnapshotInfo{Store: store, Path: path}, fmt.Errorf("inspect snapshot %s: %w", path, err)
+	}
+	out := SnapshotInfo{
+		Store:     store,
+		Path:      info.Path,
+		Version:   info.Version,
+		Supported: info.Supported,
+		NextID:    info.NextID,
+		Procs:     info.Procs,
+		Alive:     info.Alive,
+		Spawned:   info.Spawned,
+		Named:     info.Named,
+		Children:  info.Children,
+		History:   info.History,
+	}
+	if !info.Created.IsZero() {
+		out.CreatedUnix = info.Created.Unix()
+	}
+	if journalPath != "" {
+		journal, err := registry.InspectJournal(journalPath)
+		switch {
+		case err == nil:
+			out.JournalPath = journalPath
+			out.JournalRecords = journal.Records
+		case !errors.Is(err, os.ErrNotExist):
+			return out, fmt.Errorf("inspect journal %s: %w", journalPath, err)
+		}
+	}
+	return out, nil
+}
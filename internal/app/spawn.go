@@ -0,0 +1,129 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	goprocv1 "goproc/api/proto/goproc/v1"
+)
+
+// RestartPolicy configures daemon-side supervision of spawned processes.
+type RestartPolicy struct {
+	Mode        string        `json:"mode"`
+	MaxRestarts int           `json:"max_restarts,omitempty"`
+	Window      time.Duration `json:"window,omitempty"`
+	Backoff     time.Duration `json:"backoff,omitempty"`
+	MaxBackoff  time.Duration `json:"max_backoff,omitempty"`
+}
+
+// SpawnParams configures a daemon-owned process launch.
+type SpawnParams struct {
+	Argv    []string
+	Env     []string
+	Dir     string
+	Tags    []string
+	Groups  []string
+	Name    string
+	Restart RestartPolicy
+	Timeout time.Duration
+	// Annotations are key/value pairs stored on the entry.
+	Annotations map[string]string
+	// TrackChildren registers descendants of the process as child entries.
+	TrackChildren bool
+	// Cgroup runs the process in its own cgroup v2 leaf with these limits;
+	// nil leaves it in the daemon's cgroup.
+	Cgroup *CgroupLimits
+}
+
+// CgroupLimits are resource limits for a spawned process. Zero values leave a
+// resource unlimited.
+type CgroupLimits struct {
+	MemoryMax uint64  `json:"memory_max,omitempty"` // bytes
+	CPUMax    float64 `json:"cpu_max,omitempty"`    // CPUs, e.g. 0.5
+	PIDsMax   int     `json:"pids_max,omitempty"`
+}
+
+// Cgroup is the cgroup v2 leaf a spawned process runs in.
+type Cgroup struct {
+	Path string `json:"path"`
+	CgroupLimits
+}
+
+// SpawnResult reports the registered process.
+type SpawnResult struct {
+	ID  uint64 `json:"id"`
+	PID int    `json:"pid"`
+}
+
+// Spawn asks the daemon to start and supervise a new process.
+func (a *App) Spawn(ctx context.Context, params SpawnParams) (SpawnResult, error) {
+	var result SpawnResult
+
+	req, err := spawnRequest(params)
+	if err != nil {
+		return result, err
+	}
+	err = a.withClient(ctx, params.Timeout, func(ctx context.Context, client goprocv1.GoProcClient) error {
+		resp, err := client.Spawn(ctx, req)
+		if err != nil {
+			return fmt.Errorf("daemon spawn RPC failed: %w", err)
+		}
+		result.ID = resp.GetId()
+		result.PID = int(resp.GetPid())
+		return nil
+	})
+	return result, err
+}
+
+// spawnRequest validates params and builds the request for them.
+func spawnRequest(params SpawnParams) (*goprocv1.SpawnRequest, error) {
+	if len(params.Argv) == 0 || strings.TrimSpace(params.Argv[0]) == "" {
+		return nil, invalidParamsf("command must not be empty")
+	}
+	mode := strings.TrimSpace(params.Restart.Mode)
+	switch mode {
+	case "", "never", "on-failure", "always":
+	default:
+		return nil, invalidParamsf("invalid restart policy %q (expected never, on-failure, or always)", params.Restart.Mode)
+	}
+	if params.Restart.MaxRestarts < 0 {
+		return nil, invalidParamsf("invalid max restarts %d", params.Restart.MaxRestarts)
+	}
+	if cg := params.Cgroup; cg != nil {
+		if cg.CPUMax < 0 || math.IsNaN(cg.CPUMax) || math.IsInf(cg.CPUMax, 0) {
+			return nil, invalidParamsf("invalid cpu limit %v", cg.CPUMax)
+		}
+		if cg.PIDsMax < 0 {
+			return nil, invalidParamsf("invalid pids limit %d", cg.PIDsMax)
+		}
+	}
+
+	req := &goprocv1.SpawnRequest{
+		Argv:   append([]string(nil), params.Argv...),
+		Env:    append([]string(nil), params.Env...),
+		Cwd:    params.Dir,
+		Tags:   append([]string(nil), params.Tags...),
+		Groups: append([]string(nil), params.Groups...),
+		Name:   strings.TrimSpace(params.Name),
+		Restart: &goprocv1.RestartPolicy{
+			Mode:         mode,
+			MaxRestarts:  uint32(params.Restart.MaxRestarts),
+			WindowMs:     params.Restart.Window.Milliseconds(),
+			BackoffMs:    params.Restart.Backoff.Milliseconds(),
+			MaxBackoffMs: params.Restart.MaxBackoff.Milliseconds(),
+		},
+		Annotations:   params.Annotations,
+		TrackChildren: params.TrackChildren,
+	}
+	if cg := params.Cgroup; cg != nil {
+		req.Cgroup = &goprocv1.Cgroup{
+			MemoryMaxBytes: cg.MemoryMax,
+			CpuMax:         cg.CPUMax,
+			PidsMax:        uint32(cg.PIDsMax),
+		}
+	}
+	return req, nil
+}
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	goprocv1 "goproc/api/proto/goproc/v1"
+)
+
+func TestAppSpawnRejectsEmptyCommand(t *testing.T) {
+	app := New(Options{})
+	if _, err := app.Spawn(context.Background(), SpawnParams{Timeout: time.Second}); err == nil || err.Error() != "command must not be empty" {
+		t.Fatalf("expected empty command error, got %v", err)
+	}
+}
+
+func TestAppSpawnRejectsInvalidPolicy(t *testing.T) {
+	app := New(Options{})
+	_, err := app.Spawn(context.Background(), SpawnParams{
+		Argv:    []string{"sleep", "1"},
+		Restart: RestartPolicy{Mode: "sometimes"},
+		Timeout: time.Second,
+	})
+	if err == nil || err.Error() != `invalid restart policy "sometimes" (expected never, on-failure, or always)` {
+		t.Fatalf("expected policy error, got %v", err)
+	}
+}
+
+func TestAppSpawnDaemonNotRunning(t *testing.T) {
+	stubDaemon(t, false, nil)
+	app := New(Options{})
+	_, err := app.Spawn(context.Background(), SpawnParams{Argv: []string{"true"}, Timeout: time.Second})
+	if err == nil || err.Error() != "daemon is not running" {
+		t.Fatalf("expected daemon error, got %v", err)
+	}
+}
+
+func TestAppSpawnRPCError(t *testing.T) {
+	stubDaemon(t, true, func(ctx context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		conn := &fakeConn{
+			invoke: func(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+				return errors.New("boom")
+			},
+		}
+		return goprocv1.NewGoProcClient(conn), conn, nil
+	})
+	app := New(Options{})
+	_, err := app.Spawn(context.Background(), SpawnParams{Argv: []string{"true"}, Timeout: time.Second})
+	if err == nil || err.Error() != "daemon spawn RPC failed: boom" {
+		t.Fatalf("expected wrapped rpc error, got %v", err)
+	}
+}
+
+func TestAppSpawnSuccess(t *testing.T) {
+	var captured *goprocv1.SpawnRequest
+	stubDaemon(t, true, func(ctx context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		conn := &fakeConn{
+			invoke: func(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+				req, ok := args.(*goprocv1.SpawnRequest)
+				if !ok {
+					t.Fatalf("unexpected args type %T", args)
+				}
+				captured = req
+				resp := reply.(*goprocv1.SpawnResponse)
+				resp.Id = 7
+				resp.Pid = 4242
+				return nil
+			},
+		}
+		return goprocv1.NewGoProcClient(conn), conn, nil
+	})
+
+	params := SpawnParams{
+		Argv:   []string{"sleep", "60"},
+		Env:    []string{"A=1"},
+		Dir:    "/tmp",
+		Tags:   []string{"t"},
+		Groups: []string{"g"},
+		Name:   " svc ",
+		Restart: RestartPolicy{
+			Mode:        "on-failure",
+			MaxRestarts: 3,
+			Window:      time.Minute,
+			Backoff:     2 * time.Second,
+			MaxBackoff:  10 * time.Second,
+		},
+		Cgroup:  &CgroupLimits{MemoryMax: 64 << 20, CPUMax: 0.5, PIDsMax: 32},
+		Timeout: time.Second,
+	}
+	app := New(Options{})
+	res, err := app.Spawn(context.Background(), params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.ID != 7 || res.PID != 4242 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if captured == nil {
+		t.Fatal("request was not captured")
+	}
+	if !reflect.DeepEqual(captured.GetArgv(), params.Argv) || captured.GetCwd() != "/tmp" || captured.GetName() != "svc" {
+		t.Fatalf("unexpected request: %+v", captured)
+	}
+	pol := captured.GetRestart()
+	if pol.GetMode() != "on-failure" || pol.GetMaxRestarts() != 3 || pol.GetWindowMs() != 60000 || pol.GetBackoffMs() != 2000 || pol.GetMaxBackoffMs() != 10000 {
+		t.Fatalf("unexpected restart policy: %+v", pol)
+	}
+	cg := captured.GetCgroup()
+	if cg.GetMemoryMaxBytes() != 64<<20 || cg.GetCpuMax() != 0.5 || cg.GetPidsMax() != 32 {
+		t.Fatalf("unexpected cgroup limits: %+v", cg)
+	}
+}
+
+func TestAppSpawnRejectsInvalidCgroupLimits(t *testing.T) {
+	app := New(Options{})
+	_, err := app.Spawn(context.Background(), SpawnParams{
+		Argv:    []string{"sleep", "1"},
+		Cgroup:  &CgroupLimits{PIDsMax: -1},
+		Timeout: time.Second,
+	})
+	if !errors.Is(err, ErrInvalidParams) {
+		t.Fatalf("expected invalid params error, got %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	goprocv1 "goproc/api/proto/goproc/v1"
+)
+
+// StatsParams selects processes and the history window to summarize.
+type StatsParams struct {
+	Filters ListFilters
+	Window  time.Duration // 0 = everything the daemon retained
+	Timeout time.Duration
+}
+
+// MetricSummary aggregates one metric over the window.
+type MetricSummary struct {
+	Min float64 `json:"min"`
+	Avg float64 `json:"avg"`
+	Max float64 `json:"max"`
+	P95 float64 `json:"p95"`
+}
+
+// MetricSample is one historical reading.
+type MetricSample struct {
+	At         time.Time `json:"at"`
+	CPUPercent float64   `json:"cpu_percent"`
+	RSS        uint64    `json:"rss_bytes"`
+}
+
+// ProcStats is the metric history of one process.
+type ProcStats struct {
+	Process Process        `json:"process"`
+	CPU     MetricSummary  `json:"cpu_percent"`
+	RSS     MetricSummary  `json:"rss_bytes"`
+	Samples []MetricSample `json:"samples"`
+}
+
+// Stats fetches CPU/RSS history summaries for matching processes.
+func (a *App) Stats(ctx context.Context, params StatsParams) ([]ProcStats, error) {
+	if params.Window < 0 {
+		return nil, invalidParamsf("window must not be negative")
+	}
+	req, err := params.Filters.buildRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []ProcStats
+	err = a.withClient(ctx, params.Timeout, func(ctx context.Context, client goprocv1.GoProcClient) error {
+		resp, err := client.Stats(ctx, &goprocv1.StatsRequest{
+			Filter:   req,
+			WindowMs: params.Window.Milliseconds(),
+		})
+		if err != nil {
+			return fmt.Errorf("daemon stats RPC failed: %w", err)
+		}
+		out = make([]ProcStats, 0, len(resp.GetStats()))
+		for _, st := range resp.GetStats() {
+			ps := ProcStats{
+				Process: procFromProto(st.GetProc()),
+				CPU:     summaryFromProto(st.GetCpuPercent()),
+				RSS:     summaryFromProto(st.GetRssBytes()),
+				Samples: make([]MetricSample, 0, len(st.GetSamples())),
+			}
+			for _, s := range st.GetSamples() {
+				ps.Samples = append(ps.Samples, MetricSample{
+					At:         time.UnixMilli(s.GetAtUnixMs()),
+					CPUPercent: s.GetCpuPercent(),
+					RSS:        s.GetRssBytes(),
+				})
+			}
+			out = append(out, ps)
+		}
+		return nil
+	})
+	return out, err
+}
+
+func summaryFromProto(s *goprocv1.MetricSummary) MetricSummary {
+	return MetricSummary{Min: s.GetMin(), Avg: s.GetAvg(), Max: s.GetMax(), P95: s.GetP95()}
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	goprocv1 "goproc/api/proto/goproc/v1"
+)
+
+func TestAppStatsRejectsNegativeWindow(t *testing.T) {
+	app := New(Options{})
+	_, err := app.Stats(context.Background(), StatsParams{Window: -time.Second, Timeout: time.Second})
+	if err == nil || err.Error() != "window must not be negative" {
+		t.Fatalf("expected window error, got %v", err)
+	}
+}
+
+func TestAppStatsDaemonNotRunning(t *testing.T) {
+	stubDaemon(t, false, nil)
+	app := New(Options{})
+	_, err := app.Stats(context.Background(), StatsParams{Timeout: time.Second})
+	if err == nil || err.Error() != "daemon is not running" {
+		t.Fatalf("expected daemon error, got %v", err)
+	}
+}
+
+func TestAppStatsSuccess(t *testing.T) {
+	var captured *goprocv1.StatsRequest
+	stubDaemon(t, true, func(ctx context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		conn := &fakeConn{
+			invoke: func(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+				if method != goprocv1.GoProc_Stats_FullMethodName {
+					t.Fatalf("unexpected method %s", method)
+				}
+				captured = args.(*goprocv1.StatsRequest)
+				reply.(*goprocv1.StatsResponse).Stats = []*goprocv1.ProcStats{{
+					Proc:       &goprocv1.Proc{Id: 5, Name: "api"},
+					CpuPercent: &goprocv1.MetricSummary{Min: 1, Avg: 2, Max: 3, P95: 3},
+					RssBytes:   &goprocv1.MetricSummary{Min: 100, Avg: 150, Max: 200, P95: 200},
+					Samples: []*goprocv1.MetricSample{
+						{AtUnixMs: 1000, CpuPercent: 1, RssBytes: 100},
+						{AtUnixMs: 2000, CpuPercent: 3, RssBytes: 200},
+					},
+				}}
+				return nil
+			},
+		}
+		return goprocv1.NewGoProcClient(conn), conn, nil
+	})
+
+	app := New(Options{})
+	stats, err := app.Stats(context.Background(), StatsParams{
+		Filters: ListFilters{Names: []string{"api"}},
+		Window:  10 * time.Minute,
+		Timeout: time.Second,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if captured.GetWindowMs() != 600000 || len(captured.GetFilter().GetNames()) != 1 {
+		t.Fatalf("unexpected request: %+v", captured)
+	}
+	if len(stats) != 1 || stats[0].Process.ID != 5 || stats[0].CPU.Avg != 2 || stats[0].RSS.Max != 200 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if len(stats[0].Samples) != 2 || !stats[0].Samples[1].At.Equal(time.UnixMilli(2000)) || stats[0].Samples[1].RSS != 200 {
+		t.Fatalf("unexpected samples: %+v", stats[0].Samples)
+	}
+}
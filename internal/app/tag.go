@@ -2,7 +2,6 @@ package app
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -12,9 +11,9 @@ import (
 
 // RenameInfo reports rename operations.
 type RenameInfo struct {
-	From    string
-	To      string
-	Updated uint32
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Updated uint32 `json:"updated"`
 }
 
 // TagParams configures the tag command.
@@ -26,9 +25,9 @@ type TagParams struct {
 
 // TagResult aggregates the rename/list outcome.
 type TagResult struct {
-	RenameInfo *RenameInfo
-	Processes  []Process
-	Message    string
+	RenameInfo *RenameInfo `json:"rename,omitempty"`
+	Processes  []Process   `json:"processes"`
+	Message    string      `json:"message,omitempty"`
 }
 
 // Tag lists and optionally renames tags.
@@ -37,7 +36,7 @@ func (a *App) Tag(ctx context.Context, params TagParams) (TagResult, error) {
 
 	name := strings.TrimSpace(params.Name)
 	if name == "" {
-		return result, errors.New("tag must not be empty")
+		return result, invalidParamsf("tag must not be empty")
 	}
 
 	req := &goprocv1.ListRequest{
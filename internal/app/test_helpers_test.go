@@ -12,6 +12,7 @@ import (
 
 type fakeConn struct {
 	invoke func(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error
+	stream func(ctx context.Context, method string) (grpc.ClientStream, error)
 }
 
 func (f *fakeConn) Invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
@@ -22,9 +23,37 @@ func (f *fakeConn) Invoke(ctx context.Context, method string, args interface{},
 }
 
 func (f *fakeConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if f.stream != nil {
+		return f.stream(ctx, method)
+	}
 	return nil, errors.New("not implemented")
 }
 
+// fakeStream is a server stream stub: it records the request and replays
+// messages via recv until it returns an error (io.EOF ends the stream).
+type fakeStream struct {
+	grpc.ClientStream
+	ctx  context.Context
+	sent interface{}
+	recv func(m interface{}) error
+}
+
+func (f *fakeStream) Context() context.Context { return f.ctx }
+
+func (f *fakeStream) SendMsg(m interface{}) error {
+	f.sent = m
+	return nil
+}
+
+func (f *fakeStream) CloseSend() error { return nil }
+
+func (f *fakeStream) RecvMsg(m interface{}) error {
+	if f.recv == nil {
+		return io.EOF
+	}
+	return f.recv(m)
+}
+
 func (f *fakeConn) Close() error { return nil }
 
 func stubDaemon(t *testing.T, running bool, dial func(context.Context) (goprocv1.GoProcClient, io.Closer, error)) {
@@ -0,0 +1,45 @@
+package app
+
+// TreeLine is one row of a process tree drawn as text.
+type TreeLine struct {
+	// Prefix holds the box-drawing guides, e.g. "│  └─ ".
+	Prefix string
+	Node   ProcNode
+}
+
+// TreeLines flattens nodes depth-first into rows with box-drawing prefixes,
+// ready to print below the line of their root process.
+func TreeLines(nodes []ProcNode) []TreeLine {
+	var out []TreeLine
+	appendTreeLines(&out, nodes, "")
+	return out
+}
+
+func appendTreeLines(out *[]TreeLine, nodes []ProcNode, indent string) {
+	for i, n := range nodes {
+		branch, next := "├─ ", "│  "
+		if i == len(nodes)-1 {
+			branch, next = "└─ ", "   "
+		}
+		*out = append(*out, TreeLine{Prefix: indent + branch, Node: n})
+		appendTreeLines(out, n.Children, indent+next)
+	}
+}
+
+// TreeRoots returns the entries to draw as roots: every process except child
+// entries whose parent entry is also in procs, since they already appear in
+// the parent's tree.
+func TreeRoots(procs []Process) []Process {
+	present := make(map[uint64]struct{}, len(procs))
+	for _, p := range procs {
+		present[p.ID] = struct{}{}
+	}
+	roots := make([]Process, 0, len(procs))
+	for _, p := range procs {
+		if _, ok := present[p.ParentID]; p.ParentID != 0 && ok {
+			continue
+		}
+		roots = append(roots, p)
+	}
+	return roots
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestTreeLines(t *testing.T) {
+	nodes := []ProcNode{
+		{PID: 31, Children: []ProcNode{{PID: 40}, {PID: 41}}},
+		{PID: 32, Children: []ProcNode{{PID: 50}}},
+	}
+	var got []string
+	for _, line := range TreeLines(nodes) {
+		got = append(got, line.Prefix+strconv.Itoa(line.Node.PID))
+	}
+	want := []string{
+		"├─ 31",
+		"│  ├─ 40",
+		"│  └─ 41",
+		"└─ 32",
+		"   └─ 50",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected tree:\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestTreeRoots(t *testing.T) {
+	procs := []Process{{ID: 1}, {ID: 2, ParentID: 1}, {ID: 3, ParentID: 9}, {ID: 4}}
+	var ids []uint64
+	for _, p := range TreeRoots(procs) {
+		ids = append(ids, p.ID)
+	}
+	if !reflect.DeepEqual(ids, []uint64{1, 3, 4}) {
+		t.Fatalf("unexpected roots %v", ids)
+	}
+}
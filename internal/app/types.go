@@ -1,26 +1,89 @@
 package app
 
 import (
-	"errors"
 	"fmt"
+	"maps"
 	"strings"
 	"time"
 
 	goprocv1 "goproc/api/proto/goproc/v1"
+	"goproc/internal/pattern"
+	"goproc/internal/query"
 )
 
 // Process mirrors the daemon registry entry.
 type Process struct {
-	ID       uint64
-	PID      int
-	PGID     int
-	Cmd      string
-	Alive    bool
-	Tags     []string
-	Groups   []string
-	Name     string
-	AddedAt  time.Time
-	LastSeen time.Time
+	ID       uint64         `json:"id"`
+	PID      int            `json:"pid"`
+	PGID     int            `json:"pgid"`
+	Cmd      string         `json:"cmd"`
+	Alive    bool           `json:"alive"`
+	Tags     []string       `json:"tags,omitempty"`
+	Groups   []string       `json:"groups,omitempty"`
+	Name     string         `json:"name,omitempty"`
+	AddedAt  time.Time      `json:"added_at"`
+	LastSeen time.Time      `json:"last_seen"`
+	Restart  *RestartPolicy `json:"restart,omitempty"`
+	Restarts int            `json:"restarts,omitempty"`
+	Metrics  *Metrics       `json:"metrics,omitempty"`
+	Exit     *ExitStatus    `json:"exit,omitempty"`
+
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// ParentID is the entry this one was registered under by
+	// TrackChildren; 0 for entries added directly.
+	ParentID      uint64 `json:"parent_id,omitempty"`
+	TrackChildren bool   `json:"track_children,omitempty"`
+	// Children is the process tree below PID from the last liveness tick.
+	Children []ProcNode `json:"children,omitempty"`
+	// Cgroup is set for spawned processes that run in their own cgroup.
+	Cgroup *Cgroup `json:"cgroup,omitempty"`
+	// Manifest names the manifest that declared the entry (see Apply).
+	Manifest string `json:"manifest,omitempty"`
+}
+
+// ProcNode is a descendant process discovered by the daemon.
+type ProcNode struct {
+	PID      int        `json:"pid"`
+	Cmd      string     `json:"cmd"`
+	ID       uint64     `json:"id,omitempty"` // registry entry for PID; 0 if untracked
+	Children []ProcNode `json:"children,omitempty"`
+}
+
+// ExitStatus describes how a process last terminated.
+type ExitStatus struct {
+	At         time.Time `json:"at"`
+	Reason     string    `json:"reason"` // exited, signaled, unknown
+	Code       int       `json:"code"`
+	Signal     string    `json:"signal,omitempty"`
+	CoreDumped bool      `json:"core_dumped,omitempty"`
+}
+
+// String renders the status compactly, e.g. "code 1", "SIGSEGV (core dumped)".
+func (e ExitStatus) String() string {
+	switch e.Reason {
+	case "exited":
+		return fmt.Sprintf("code %d", e.Code)
+	case "signaled":
+		if e.CoreDumped {
+			return e.Signal + " (core dumped)"
+		}
+		return e.Signal
+	default:
+		return "unknown"
+	}
+}
+
+// Metrics is the latest resource sample the daemon took for a live process.
+type Metrics struct {
+	CPUPercent float64   `json:"cpu_percent"`
+	RSS        uint64    `json:"rss_bytes"`
+	VMS        uint64    `json:"vms_bytes"`
+	Threads    int       `json:"threads"`
+	FDs        int       `json:"open_fds"`
+	ReadBytes  uint64    `json:"read_bytes"`
+	WriteBytes uint64    `json:"write_bytes"`
+	SampledAt  time.Time `json:"sampled_at"`
 }
 
 func procFromProto(p *goprocv1.Proc) Process {
@@ -35,6 +98,90 @@ func procFromProto(p *goprocv1.Proc) Process {
 		Name:     p.GetName(),
 		AddedAt:  time.Unix(p.GetAddedAtUnix(), 0),
 		LastSeen: time.Unix(p.GetLastSeenUnix(), 0),
+		Restart:  restartPolicyFromProto(p.GetRestart()),
+		Restarts: int(p.GetRestarts()),
+		Metrics:  metricsFromProto(p.GetMetrics()),
+		Exit:     exitStatusFromProto(p.GetExit()),
+
+		Annotations: maps.Clone(p.GetAnnotations()),
+
+		ParentID:      p.GetParentId(),
+		TrackChildren: p.GetTrackChildren(),
+		Children:      procNodesFromProto(p.GetChildren()),
+		Cgroup:        cgroupFromProto(p.GetCgroup()),
+		Manifest:      p.GetManifest(),
+	}
+}
+
+func cgroupFromProto(cg *goprocv1.Cgroup) *Cgroup {
+	if cg == nil {
+		return nil
+	}
+	return &Cgroup{
+		Path: cg.GetPath(),
+		CgroupLimits: CgroupLimits{
+			MemoryMax: cg.GetMemoryMaxBytes(),
+			CPUMax:    cg.GetCpuMax(),
+			PIDsMax:   int(cg.GetPidsMax()),
+		},
+	}
+}
+
+func procNodesFromProto(nodes []*goprocv1.ProcNode) []ProcNode {
+	if len(nodes) == 0 {
+		return nil
+	}
+	out := make([]ProcNode, 0, len(nodes))
+	for _, n := range nodes {
+		out = append(out, ProcNode{
+			PID:      int(n.GetPid()),
+			Cmd:      n.GetCmd(),
+			ID:       n.GetId(),
+			Children: procNodesFromProto(n.GetChildren()),
+		})
+	}
+	return out
+}
+
+func exitStatusFromProto(e *goprocv1.ExitStatus) *ExitStatus {
+	if e == nil {
+		return nil
+	}
+	return &ExitStatus{
+		At:         time.Unix(e.GetAtUnix(), 0),
+		Reason:     e.GetReason(),
+		Code:       int(e.GetCode()),
+		Signal:     e.GetSignal(),
+		CoreDumped: e.GetCoreDumped(),
+	}
+}
+
+func metricsFromProto(m *goprocv1.ProcMetrics) *Metrics {
+	if m == nil {
+		return nil
+	}
+	return &Metrics{
+		CPUPercent: m.GetCpuPercent(),
+		RSS:        m.GetRssBytes(),
+		VMS:        m.GetVmsBytes(),
+		Threads:    int(m.GetThreads()),
+		FDs:        int(m.GetOpenFds()),
+		ReadBytes:  m.GetReadBytes(),
+		WriteBytes: m.GetWriteBytes(),
+		SampledAt:  time.Unix(m.GetSampledAtUnix(), 0),
+	}
+}
+
+func restartPolicyFromProto(p *goprocv1.RestartPolicy) *RestartPolicy {
+	if p == nil {
+		return nil
+	}
+	return &RestartPolicy{
+		Mode:        p.GetMode(),
+		MaxRestarts: int(p.GetMaxRestarts()),
+		Window:      time.Duration(p.GetWindowMs()) * time.Millisecond,
+		Backoff:     time.Duration(p.GetBackoffMs()) * time.Millisecond,
+		MaxBackoff:  time.Duration(p.GetMaxBackoffMs()) * time.Millisecond,
 	}
 }
 
@@ -49,6 +196,24 @@ type ListFilters struct {
 	TextSearch string
 	PIDs       []int
 	IDs        []int
+	// Annotations are selectors that must all match: "key" (present),
+	// "key=value" (equal) or "key=prefix*" (value starts with prefix).
+	Annotations []string
+	// Query is an expression such as "tag:db and (group:prod or name~^api-)";
+	// it is ANDed with the other filters.
+	Query string
+
+	// Globs (*, ?, [...]) match whole values and any glob in a list may
+	// match; regexes match anywhere unless anchored. Tag and group patterns
+	// need some tag or group to match.
+	NameGlobs  []string
+	NameRegex  string
+	TagGlobs   []string
+	TagRegex   string
+	GroupGlobs []string
+	GroupRegex string
+	CmdGlobs   []string
+	CmdRegex   string
 }
 
 func (f ListFilters) buildRequest() (*goprocv1.ListRequest, error) {
@@ -66,7 +231,7 @@ func (f ListFilters) buildRequest() (*goprocv1.ListRequest, error) {
 		for _, name := range names {
 			clean := strings.TrimSpace(name)
 			if clean == "" {
-				return nil, errors.New("name filters must not be empty")
+				return nil, invalidParamsf("name filters must not be empty")
 			}
 			req.Names = append(req.Names, clean)
 		}
@@ -75,7 +240,7 @@ func (f ListFilters) buildRequest() (*goprocv1.ListRequest, error) {
 		req.Pids = make([]int32, 0, len(pids))
 		for _, pid := range pids {
 			if pid <= 0 {
-				return nil, fmt.Errorf("invalid pid filter: %d", pid)
+				return nil, invalidParamsf("invalid pid filter: %d", pid)
 			}
 			req.Pids = append(req.Pids, int32(pid))
 		}
@@ -84,11 +249,77 @@ func (f ListFilters) buildRequest() (*goprocv1.ListRequest, error) {
 		req.Ids = make([]uint64, 0, len(ids))
 		for _, id := range ids {
 			if id <= 0 {
-				return nil, fmt.Errorf("invalid id filter: %d", id)
+				return nil, invalidParamsf("invalid id filter: %d", id)
 			}
 			req.Ids = append(req.Ids, uint64(id))
 		}
 	}
+	for _, raw := range f.Annotations {
+		sel, err := parseAnnotationSelector(raw)
+		if err != nil {
+			return nil, err
+		}
+		req.Annotations = append(req.Annotations, sel)
+	}
+	// Parse locally so syntax errors are reported with their position
+	// before contacting the daemon.
+	if _, err := query.Parse(f.Query); err != nil {
+		return nil, err
+	}
+	req.Query = f.Query
+
+	for _, pf := range []struct {
+		field string
+		globs []string
+		regex string
+	}{
+		{"name", f.NameGlobs, f.NameRegex},
+		{"tag", f.TagGlobs, f.TagRegex},
+		{"group", f.GroupGlobs, f.GroupRegex},
+		{"cmd", f.CmdGlobs, f.CmdRegex},
+	} {
+		if _, err := pattern.Compile(pf.globs, pf.regex); err != nil {
+			return nil, invalidParamsf("invalid %s %w", pf.field, err)
+		}
+	}
+	req.NameGlobs, req.NameRegex = f.NameGlobs, f.NameRegex
+	req.TagGlobs, req.TagRegex = f.TagGlobs, f.TagRegex
+	req.GroupGlobs, req.GroupRegex = f.GroupGlobs, f.GroupRegex
+	req.CmdGlobs, req.CmdRegex = f.CmdGlobs, f.CmdRegex
 
 	return req, nil
 }
+
+func parseAnnotationSelector(raw string) (*goprocv1.AnnotationSelector, error) {
+	key, value, hasValue := strings.Cut(raw, "=")
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, invalidParamsf("invalid annotation filter %q: missing key", raw)
+	}
+	switch {
+	case !hasValue:
+		return &goprocv1.AnnotationSelector{Key: key, Op: "exists"}, nil
+	case strings.HasSuffix(value, "*"):
+		return &goprocv1.AnnotationSelector{Key: key, Op: "prefix", Value: strings.TrimSuffix(value, "*")}, nil
+	default:
+		return &goprocv1.AnnotationSelector{Key: key, Op: "equals", Value: value}, nil
+	}
+}
+
+// ParseAnnotations turns repeated "key=value" arguments into a map. Later
+// occurrences of a key win.
+func ParseAnnotations(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, invalidParamsf("invalid annotation %q: expected key=value", pair)
+		}
+		out[key] = strings.TrimSpace(value)
+	}
+	return out, nil
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	goprocv1 "goproc/api/proto/goproc/v1"
+)
+
+// WatchParams selects which registry changes to stream.
+type WatchParams struct {
+	Filters ListFilters
+	Timeout time.Duration
+}
+
+// Event is a registry change reported by the daemon.
+type Event struct {
+	Type         string    `json:"type"`
+	Process      *Process  `json:"process,omitempty"`
+	PreviousName string    `json:"previous_name,omitempty"`
+	At           time.Time `json:"at"`
+}
+
+// Watch streams registry changes matching the filters to fn until ctx is
+// cancelled, the daemon closes the stream, or fn returns an error.
+func (a *App) Watch(ctx context.Context, params WatchParams, fn func(Event) error) error {
+	req, err := params.Filters.buildRequest()
+	if err != nil {
+		return err
+	}
+
+	return a.withStream(ctx, params.Timeout, func(ctx context.Context, client goprocv1.GoProcClient) error {
+		stream, err := client.Watch(ctx, &goprocv1.WatchRequest{Filter: req})
+		if err != nil {
+			return fmt.Errorf("daemon watch RPC failed: %w", err)
+		}
+		for {
+			ev, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("daemon watch RPC failed: %w", err)
+			}
+			if err := fn(eventFromProto(ev)); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+func eventFromProto(ev *goprocv1.WatchEvent) Event {
+	out := Event{
+		Type:         ev.GetType(),
+		PreviousName: ev.GetPreviousName(),
+		At:           time.UnixMilli(ev.GetAtUnixMs()),
+	}
+	if p := ev.GetProc(); p != nil {
+		proc := procFromProto(p)
+		out.Process = &proc
+	}
+	return out
+}
@@ -0,0 +1,107 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	goprocv1 "goproc/api/proto/goproc/v1"
+)
+
+func TestAppWatchDaemonNotRunning(t *testing.T) {
+	stubDaemon(t, false, nil)
+	app := New(Options{})
+	err := app.Watch(context.Background(), WatchParams{Timeout: time.Second}, func(Event) error { return nil })
+	if err == nil || err.Error() != "daemon is not running" {
+		t.Fatalf("expected daemon error, got %v", err)
+	}
+}
+
+func TestAppWatchInvalidFilter(t *testing.T) {
+	app := New(Options{})
+	err := app.Watch(context.Background(), WatchParams{Filters: ListFilters{PIDs: []int{0}}, Timeout: time.Second}, func(Event) error { return nil })
+	if err == nil || err.Error() != "invalid pid filter: 0" {
+		t.Fatalf("expected filter error, got %v", err)
+	}
+}
+
+func TestAppWatchDeliversEvents(t *testing.T) {
+	var sent *goprocv1.WatchRequest
+	stubDaemon(t, true, func(ctx context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		events := []*goprocv1.WatchEvent{
+			{Type: "added", Proc: &goprocv1.Proc{Id: 3, Pid: 42, Name: "api"}, AtUnixMs: 1500},
+			{Type: "reset", AtUnixMs: 2000},
+		}
+		conn := &fakeConn{}
+		conn.stream = func(ctx context.Context, method string) (grpc.ClientStream, error) {
+			if method != goprocv1.GoProc_Watch_FullMethodName {
+				t.Fatalf("unexpected method %s", method)
+			}
+			fs := &fakeStream{ctx: ctx}
+			fs.recv = func(m interface{}) error {
+				sent, _ = fs.sent.(*goprocv1.WatchRequest)
+				if len(events) == 0 {
+					return io.EOF
+				}
+				proto.Merge(m.(*goprocv1.WatchEvent), events[0])
+				events = events[1:]
+				return nil
+			}
+			return fs, nil
+		}
+		return goprocv1.NewGoProcClient(conn), conn, nil
+	})
+
+	var got []Event
+	app := New(Options{})
+	err := app.Watch(context.Background(), WatchParams{
+		Filters: ListFilters{TagsAny: []string{"web"}},
+		Timeout: time.Second,
+	}, func(ev Event) error {
+		got = append(got, ev)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(got))
+	}
+	if got[0].Type != "added" || got[0].Process == nil || got[0].Process.ID != 3 || got[0].Process.Name != "api" {
+		t.Fatalf("unexpected first event: %+v", got[0])
+	}
+	if !got[0].At.Equal(time.UnixMilli(1500)) {
+		t.Fatalf("unexpected timestamp %v", got[0].At)
+	}
+	if got[1].Type != "reset" || got[1].Process != nil {
+		t.Fatalf("unexpected second event: %+v", got[1])
+	}
+	if sent == nil || len(sent.GetFilter().GetTagsAny()) != 1 || sent.GetFilter().GetTagsAny()[0] != "web" {
+		t.Fatalf("unexpected request: %+v", sent)
+	}
+}
+
+func TestAppWatchCallbackErrorStops(t *testing.T) {
+	stubDaemon(t, true, func(ctx context.Context) (goprocv1.GoProcClient, io.Closer, error) {
+		conn := &fakeConn{}
+		conn.stream = func(ctx context.Context, method string) (grpc.ClientStream, error) {
+			fs := &fakeStream{ctx: ctx}
+			fs.recv = func(m interface{}) error {
+				m.(*goprocv1.WatchEvent).Type = "died"
+				return nil
+			}
+			return fs, nil
+		}
+		return goprocv1.NewGoProcClient(conn), conn, nil
+	})
+	stop := errors.New("stop")
+	app := New(Options{})
+	err := app.Watch(context.Background(), WatchParams{Timeout: time.Second}, func(Event) error { return stop })
+	if !errors.Is(err, stop) {
+		t.Fatalf("expected callback error, got %v", err)
+	}
+}
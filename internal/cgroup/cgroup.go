@@ -0,0 +1,281 @@
+// Package cgroup places daemon-spawned processes in cgroup v2 leaves below a
+// delegated root (goproc.slice by default) and applies resource limits.
+//
+// Each entry gets its own leaf with a unique "proc-" name. The leaf must exist
+// before the process starts, which is before the registry assigns an ID, and
+// cgroup v2 does not allow renaming; the daemon records the path on the entry
+// instead. Processes are cloned directly into the leaf, so no fork can escape
+// it before it is populated.
+package cgroup
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// DefaultRoot is the delegated subtree leaves are created in.
+const DefaultRoot = "/sys/fs/cgroup/goproc.slice"
+
+const (
+	leafPrefix = "proc-"
+	// cpuPeriod is the cpu.max period in microseconds; the quota is a
+	// fraction of it per CPU.
+	cpuPeriod = 100000
+	// minCPUQuota is the smallest quota the kernel accepts.
+	minCPUQuota = 1000
+)
+
+// Limits are resource limits for one leaf. Zero values leave the resource
+// unlimited.
+type Limits struct {
+	MemoryMax uint64  // bytes (memory.max)
+	CPUMax    float64 // CPUs, e.g. 0.5 for half a core (cpu.max)
+	PIDsMax   int     // tasks (pids.max)
+}
+
+// Validate rejects limits the kernel would not accept.
+func (l Limits) Validate() error {
+	if l.CPUMax < 0 || math.IsNaN(l.CPUMax) || math.IsInf(l.CPUMax, 0) {
+		return fmt.Errorf("invalid cpu limit %v", l.CPUMax)
+	}
+	if l.CPUMax > 0 && l.CPUMax*cpuPeriod < minCPUQuota {
+		return fmt.Errorf("cpu limit %v is below the minimum of %v", l.CPUMax, float64(minCPUQuota)/cpuPeriod)
+	}
+	if l.PIDsMax < 0 {
+		return fmt.Errorf("invalid pids limit %d", l.PIDsMax)
+	}
+	return nil
+}
+
+// controllers lists the controllers the limits need, in the order they are
+// enabled.
+func (l Limits) controllers() []string {
+	var out []string
+	if l.MemoryMax > 0 {
+		out = append(out, "memory")
+	}
+	if l.CPUMax > 0 {
+		out = append(out, "cpu")
+	}
+	if l.PIDsMax > 0 {
+		out = append(out, "pids")
+	}
+	return out
+}
+
+// Manager creates and removes leaves below a root directory.
+type Manager struct {
+	root string
+}
+
+// NewManager returns a Manager for root; empty means DefaultRoot.
+func NewManager(root string) *Manager {
+	if root == "" {
+		root = DefaultRoot
+	}
+	return &Manager{root: root}
+}
+
+// Leaf is an open leaf directory. Its descriptor is what a new process is
+// cloned into; close it once the process has started.
+type Leaf struct {
+	dir *os.File
+}
+
+// Path returns the leaf directory.
+func (l *Leaf) Path() string { return l.dir.Name() }
+
+// Close releases the directory descriptor.
+func (l *Leaf) Close() error { return l.dir.Close() }
+
+// Create creates a new leaf and applies limits to it. Call Discard if the
+// process never started.
+func (m *Manager) Create(limits Limits) (*Leaf, error) {
+	if err := m.prepare(limits); err != nil {
+		return nil, err
+	}
+	path, err := os.MkdirTemp(m.root, leafPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("create cgroup: %w", err)
+	}
+	leaf, err := openLeaf(path, limits)
+	if err != nil {
+		_ = os.Remove(path)
+		return nil, err
+	}
+	return leaf, nil
+}
+
+// Open opens the leaf at path for a relaunch, recreating it and reapplying
+// limits if it was removed in the meantime.
+func (m *Manager) Open(path string, limits Limits) (*Leaf, error) {
+	if err := m.prepare(limits); err != nil {
+		return nil, err
+	}
+	if err := os.Mkdir(path, 0o755); err != nil && !errors.Is(err, os.ErrExist) {
+		return nil, fmt.Errorf("create cgroup: %w", err)
+	}
+	return openLeaf(path, limits)
+}
+
+// Discard removes a leaf whose process did not start or was killed.
+func (m *Manager) Discard(leaf *Leaf) {
+	path := leaf.Path()
+	_ = leaf.Close()
+	_ = Remove(path)
+}
+
+// prepare creates the root and enables the controllers limits need for its
+// children. Controllers must be delegated to the root, e.g. with
+// Delegate=yes on a systemd slice.
+func (m *Manager) prepare(limits Limits) error {
+	if err := os.MkdirAll(m.root, 0o755); err != nil {
+		return fmt.Errorf("create cgroup root: %w", err)
+	}
+	need := limits.controllers()
+	if len(need) == 0 {
+		return nil
+	}
+	enabled, err := readFields(filepath.Join(m.root, "cgroup.subtree_control"))
+	if err != nil {
+		return fmt.Errorf("read cgroup controllers: %w", err)
+	}
+	available, err := readFields(filepath.Join(m.root, "cgroup.controllers"))
+	if err != nil {
+		return fmt.Errorf("read cgroup controllers: %w", err)
+	}
+	for _, c := range need {
+		if slices.Contains(enabled, c) {
+			continue
+		}
+		if !slices.Contains(available, c) {
+			return fmt.Errorf("cgroup controller %q is not delegated to %s", c, m.root)
+		}
+		if err := writeFile(filepath.Join(m.root, "cgroup.subtree_control"), "+"+c); err != nil {
+			return fmt.Errorf("enable cgroup controller %s: %w", c, err)
+		}
+	}
+	return nil
+}
+
+func openLeaf(path string, limits Limits) (*Leaf, error) {
+	if err := applyLimits(path, limits); err != nil {
+		return nil, err
+	}
+	dir, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open cgroup: %w", err)
+	}
+	return &Leaf{dir: dir}, nil
+}
+
+// applyLimits writes every limit, resetting unset ones to "max" so a changed
+// spec never keeps a stale limit from an earlier run.
+func applyLimits(path string, l Limits) error {
+	files := []struct {
+		name, value string
+		set         bool
+	}{
+		{"memory.max", strconv.FormatUint(l.MemoryMax, 10), l.MemoryMax > 0},
+		{"cpu.max", fmt.Sprintf("%d %d", int64(l.CPUMax*cpuPeriod), cpuPeriod), l.CPUMax > 0},
+		{"pids.max", strconv.Itoa(l.PIDsMax), l.PIDsMax > 0},
+	}
+	for _, f := range files {
+		file := filepath.Join(path, f.name)
+		if !f.set {
+			if _, err := os.Stat(file); err == nil {
+				_ = writeFile(file, "max")
+			}
+			continue
+		}
+		if err := writeFile(file, f.value); err != nil {
+			return fmt.Errorf("set %s: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
+// Procs returns the PIDs in the cgroup at path. A missing cgroup has none.
+func Procs(path string) ([]int, error) {
+	fields, err := readFields(filepath.Join(path, "cgroup.procs"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	pids := make([]int, 0, len(fields))
+	for _, f := range fields {
+		pid, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, fmt.Errorf("parse cgroup.procs: %w", err)
+		}
+		pids = append(pids, pid)
+	}
+	return pids, nil
+}
+
+// Signal sends sig to every process in the cgroup at path. Processes that
+// fork concurrently may be missed; use Kill for SIGKILL.
+func Signal(path string, sig syscall.Signal) error {
+	pids, err := Procs(path)
+	if err != nil {
+		return err
+	}
+	if len(pids) == 0 {
+		return syscall.ESRCH
+	}
+	var errs []error
+	for _, pid := range pids {
+		if err := syscall.Kill(pid, sig); err != nil && err != syscall.ESRCH {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Kill SIGKILLs every process in the cgroup at path, including those forked
+// while the kill is in progress (cgroup.kill, Linux 5.14+).
+func Kill(path string) error {
+	pids, err := Procs(path)
+	if err != nil {
+		return err
+	}
+	if len(pids) == 0 {
+		return syscall.ESRCH
+	}
+	return writeFile(filepath.Join(path, "cgroup.kill"), "1")
+}
+
+// Remove deletes the empty cgroup at path. A missing cgroup is not an error.
+func Remove(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func readFields(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(data)), nil
+}
+
+// writeFile writes value in a single write, as cgroupfs expects.
+func writeFile(path, value string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0)
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteString(value)
+	return errors.Join(err, f.Close())
+}
@@ -0,0 +1,13 @@
+//go:build linux
+
+package cgroup
+
+import "syscall"
+
+// Attach makes a process started with attr begin life in the leaf
+// (CLONE_INTO_CGROUP, Linux 5.7+).
+func (l *Leaf) Attach(attr *syscall.SysProcAttr) error {
+	attr.UseCgroupFD = true
+	attr.CgroupFD = int(l.dir.Fd())
+	return nil
+}
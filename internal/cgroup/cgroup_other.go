@@ -0,0 +1,13 @@
+//go:build !linux
+
+package cgroup
+
+import (
+	"errors"
+	"syscall"
+)
+
+// Attach fails outside Linux, which has no cgroups.
+func (l *Leaf) Attach(*syscall.SysProcAttr) error {
+	return errors.New("cgroups require Linux")
+}
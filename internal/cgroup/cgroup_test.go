@@ -0,0 +1,120 @@
+package cgroup
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestLimitsValidate(t *testing.T) {
+	for _, l := range []Limits{{}, {MemoryMax: 1 << 20, CPUMax: 0.5, PIDsMax: 10}, {CPUMax: 0.01}} {
+		if err := l.Validate(); err != nil {
+			t.Errorf("Validate(%+v) = %v", l, err)
+		}
+	}
+	for _, l := range []Limits{{CPUMax: -1}, {CPUMax: 0.001}, {PIDsMax: -1}} {
+		if err := l.Validate(); err == nil {
+			t.Errorf("Validate(%+v) succeeded, want error", l)
+		}
+	}
+}
+
+func TestPrepareEnablesDelegatedControllers(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "cgroup.controllers"), "cpu memory pids\n")
+	writeTestFile(t, filepath.Join(root, "cgroup.subtree_control"), "")
+	m := NewManager(root)
+
+	if err := m.prepare(Limits{PIDsMax: 5}); err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	if got := readTestFile(t, filepath.Join(root, "cgroup.subtree_control")); got != "+pids" {
+		t.Fatalf("subtree_control = %q, want +pids", got)
+	}
+
+	writeTestFile(t, filepath.Join(root, "cgroup.controllers"), "pids\n")
+	err := m.prepare(Limits{MemoryMax: 1 << 20})
+	if err == nil || !strings.Contains(err.Error(), `"memory" is not delegated`) {
+		t.Fatalf("expected undelegated controller error, got %v", err)
+	}
+}
+
+func TestApplyLimitsWritesAndResets(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"memory.max", "cpu.max", "pids.max"} {
+		writeTestFile(t, filepath.Join(dir, name), "")
+	}
+	if err := applyLimits(dir, Limits{MemoryMax: 64 << 20, CPUMax: 1.5}); err != nil {
+		t.Fatalf("applyLimits: %v", err)
+	}
+	want := map[string]string{"memory.max": "67108864", "cpu.max": "150000 100000", "pids.max": "max"}
+	for name, value := range want {
+		if got := readTestFile(t, filepath.Join(dir, name)); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+}
+
+func TestCreateAndRemoveLeaf(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "goproc.slice")
+	m := NewManager(root)
+	leaf, err := m.Create(Limits{})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	defer leaf.Close()
+	path := leaf.Path()
+	if filepath.Dir(path) != root || !strings.HasPrefix(filepath.Base(path), "proc-") {
+		t.Fatalf("unexpected leaf path %q", path)
+	}
+	other, err := m.Create(Limits{})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if other.Path() == path {
+		t.Fatalf("leaves share path %q", path)
+	}
+	m.Discard(other)
+	if _, err := os.Stat(other.Path()); !os.IsNotExist(err) {
+		t.Fatalf("discarded leaf still exists: %v", err)
+	}
+	if err := Remove(path); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := Remove(path); err != nil {
+		t.Fatalf("Remove of a missing leaf: %v", err)
+	}
+}
+
+func TestProcs(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "cgroup.procs"), "12\n34\n")
+	pids, err := Procs(dir)
+	if err != nil {
+		t.Fatalf("Procs: %v", err)
+	}
+	if !reflect.DeepEqual(pids, []int{12, 34}) {
+		t.Fatalf("Procs = %v", pids)
+	}
+	if pids, err := Procs(filepath.Join(dir, "missing")); err != nil || pids != nil {
+		t.Fatalf("Procs of a missing cgroup = %v, %v", pids, err)
+	}
+}
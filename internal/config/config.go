@@ -5,21 +5,101 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
+
+	"goproc/internal/units"
 )
 
 const (
 	defaultLivenessInterval   = 10 * time.Second
 	defaultLastSeenInterval   = 30 * time.Second
+	defaultLogMaxSize         = 10 << 20
+	defaultLogMaxFiles        = 3
+	defaultMetricsHistory     = 360
+	defaultLivenessBackend    = LivenessPidfd
+	defaultJournalSync        = JournalSyncBatch
+	defaultJournalInterval    = time.Second
+	defaultJournalCompact     = 1000
+	defaultStore              = StoreJSON
+	defaultHistoryMaxAge      = 7 * 24 * time.Hour
+	defaultHistoryMaxEntries  = 1000
 	envLivenessInterval       = "GOPROC_LIVENESS_INTERVAL"
 	envLastSeenUpdateInterval = "GOPROC_LAST_SEEN_INTERVAL"
+	envLogMaxSize             = "GOPROC_LOG_MAX_SIZE"
+	envLogMaxFiles            = "GOPROC_LOG_MAX_FILES"
+	envMetricsHistory         = "GOPROC_METRICS_HISTORY"
+	envLivenessBackend        = "GOPROC_LIVENESS_BACKEND"
+	envCgroupRoot             = "GOPROC_CGROUP_ROOT"
+	envJournalSync            = "GOPROC_JOURNAL_SYNC"
+	envJournalSyncInterval    = "GOPROC_JOURNAL_SYNC_INTERVAL"
+	envJournalCompactEvery    = "GOPROC_JOURNAL_COMPACT_EVERY"
+	envStore                  = "GOPROC_STORE"
+	envHistoryMaxAge          = "GOPROC_HISTORY_MAX_AGE"
+	envHistoryMaxEntries      = "GOPROC_HISTORY_MAX_ENTRIES"
+)
+
+// Liveness backends. LivenessPoll probes every PID on each tick;
+// LivenessPidfd additionally waits on pidfds with epoll so deaths are seen
+// immediately, and falls back to polling where pidfds are unsupported.
+const (
+	LivenessPoll  = "poll"
+	LivenessPidfd = "pidfd"
+)
+
+// Registry store backends. StoreJSON keeps the registry in a JSON snapshot
+// plus a journal; StoreBolt keeps one record per entry in a bbolt database,
+// so changes are written in place.
+const (
+	StoreJSON = "json"
+	StoreBolt = "bolt"
+)
+
+// Journal sync modes. JournalSyncAlways fsyncs every registry change before
+// the RPC returns, JournalSyncBatch fsyncs at most once per
+// JournalSyncInterval, and JournalSyncNone leaves flushing to the OS. They
+// apply to the commits of StoreBolt alike.
+const (
+	JournalSyncAlways = "always"
+	JournalSyncBatch  = "batch"
+	JournalSyncNone   = "none"
 )
 
 // Config aggregates tunable timeouts/intervals for the daemon.
 type Config struct {
 	LivenessInterval       time.Duration
 	LastSeenUpdateInterval time.Duration
+	// LogMaxSize is the size in bytes at which captured output is rotated.
+	LogMaxSize int64
+	// LogMaxFiles is the number of rotated log files retained per stream.
+	LogMaxFiles int
+	// MetricsHistory is the number of metric samples kept per process
+	// (one per liveness tick); 0 disables history.
+	MetricsHistory int
+	// LivenessBackend selects how process deaths are detected (LivenessPoll or LivenessPidfd).
+	LivenessBackend string
+	// CgroupRoot is the delegated cgroup v2 directory that holds the leaves
+	// of processes spawned with a cgroup; empty means cgroup.DefaultRoot.
+	CgroupRoot string
+	// Store selects the registry persistence backend (StoreJSON or StoreBolt).
+	Store string
+	// HistoryMaxAge is how long the final state of removed entries is
+	// kept; 0 keeps it regardless of age.
+	HistoryMaxAge time.Duration
+	// HistoryMaxEntries is the number of removed entries kept; 0 disables
+	// the history.
+	HistoryMaxEntries int
+	// JournalSync selects the durability of registry journal writes.
+	JournalSync string
+	// JournalSyncInterval is the flush period of JournalSyncBatch.
+	JournalSyncInterval time.Duration
+	// JournalCompactEvery is the number of journal records after which the
+	// journal is folded into the snapshot.
+	JournalCompactEvery int
 }
 
 // Load builds a Config from an optional JSON file path plus environment overrides.
@@ -27,6 +107,16 @@ func Load(path string) (Config, error) {
 	cfg := Config{
 		LivenessInterval:       defaultLivenessInterval,
 		LastSeenUpdateInterval: defaultLastSeenInterval,
+		LogMaxSize:             defaultLogMaxSize,
+		LogMaxFiles:            defaultLogMaxFiles,
+		MetricsHistory:         defaultMetricsHistory,
+		LivenessBackend:        defaultLivenessBackend,
+		JournalSync:            defaultJournalSync,
+		JournalSyncInterval:    defaultJournalInterval,
+		JournalCompactEvery:    defaultJournalCompact,
+		Store:                  defaultStore,
+		HistoryMaxAge:          defaultHistoryMaxAge,
+		HistoryMaxEntries:      defaultHistoryMaxEntries,
 	}
 
 	if path != "" {
@@ -40,6 +130,39 @@ func Load(path string) (Config, error) {
 		if fileCfg.LastSeenUpdateInterval != 0 {
 			cfg.LastSeenUpdateInterval = fileCfg.LastSeenUpdateInterval
 		}
+		if fileCfg.LogMaxSize != 0 {
+			cfg.LogMaxSize = fileCfg.LogMaxSize
+		}
+		if fileCfg.LogMaxFiles >= 0 {
+			cfg.LogMaxFiles = fileCfg.LogMaxFiles
+		}
+		if fileCfg.MetricsHistory >= 0 {
+			cfg.MetricsHistory = fileCfg.MetricsHistory
+		}
+		if fileCfg.LivenessBackend != "" {
+			cfg.LivenessBackend = fileCfg.LivenessBackend
+		}
+		if fileCfg.CgroupRoot != "" {
+			cfg.CgroupRoot = fileCfg.CgroupRoot
+		}
+		if fileCfg.JournalSync != "" {
+			cfg.JournalSync = fileCfg.JournalSync
+		}
+		if fileCfg.JournalSyncInterval != 0 {
+			cfg.JournalSyncInterval = fileCfg.JournalSyncInterval
+		}
+		if fileCfg.JournalCompactEvery != 0 {
+			cfg.JournalCompactEvery = fileCfg.JournalCompactEvery
+		}
+		if fileCfg.Store != "" {
+			cfg.Store = fileCfg.Store
+		}
+		if fileCfg.HistoryMaxAge >= 0 {
+			cfg.HistoryMaxAge = fileCfg.HistoryMaxAge
+		}
+		if fileCfg.HistoryMaxEntries >= 0 {
+			cfg.HistoryMaxEntries = fileCfg.HistoryMaxEntries
+		}
 	}
 
 	applyEnvOverrides(&cfg)
@@ -62,15 +185,114 @@ func applyEnvOverrides(cfg *Config) {
 			log.Printf("invalid %s value %q: %v", envLastSeenUpdateInterval, v, err)
 		}
 	}
+
+	if v := os.Getenv(envLogMaxSize); v != "" {
+		if size, err := parseSize(v); err == nil && size > 0 {
+			cfg.LogMaxSize = size
+		} else if err != nil {
+			log.Printf("invalid %s value %q: %v", envLogMaxSize, v, err)
+		}
+	}
+
+	if v := os.Getenv(envLogMaxFiles); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.LogMaxFiles = n
+		} else {
+			log.Printf("invalid %s value %q", envLogMaxFiles, v)
+		}
+	}
+
+	if v := os.Getenv(envMetricsHistory); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.MetricsHistory = n
+		} else {
+			log.Printf("invalid %s value %q", envMetricsHistory, v)
+		}
+	}
+
+	if v := os.Getenv(envLivenessBackend); v != "" {
+		if backend, err := parseLivenessBackend(v); err == nil {
+			cfg.LivenessBackend = backend
+		} else {
+			log.Printf("invalid %s value %q: %v", envLivenessBackend, v, err)
+		}
+	}
+
+	if v := os.Getenv(envCgroupRoot); v != "" {
+		if filepath.IsAbs(v) {
+			cfg.CgroupRoot = filepath.Clean(v)
+		} else {
+			log.Printf("invalid %s value %q: must be an absolute path", envCgroupRoot, v)
+		}
+	}
+
+	if v := os.Getenv(envJournalSync); v != "" {
+		if mode, err := parseJournalSync(v); err == nil {
+			cfg.JournalSync = mode
+		} else {
+			log.Printf("invalid %s value %q: %v", envJournalSync, v, err)
+		}
+	}
+
+	if v := os.Getenv(envJournalSyncInterval); v != "" {
+		if dur, err := time.ParseDuration(v); err == nil && dur > 0 {
+			cfg.JournalSyncInterval = dur
+		} else {
+			log.Printf("invalid %s value %q", envJournalSyncInterval, v)
+		}
+	}
+
+	if v := os.Getenv(envJournalCompactEvery); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.JournalCompactEvery = n
+		} else {
+			log.Printf("invalid %s value %q", envJournalCompactEvery, v)
+		}
+	}
+
+	if v := os.Getenv(envStore); v != "" {
+		if store, err := parseStore(v); err == nil {
+			cfg.Store = store
+		} else {
+			log.Printf("invalid %s value %q: %v", envStore, v, err)
+		}
+	}
+
+	if v := os.Getenv(envHistoryMaxAge); v != "" {
+		if dur, err := time.ParseDuration(v); err == nil && dur >= 0 {
+			cfg.HistoryMaxAge = dur
+		} else {
+			log.Printf("invalid %s value %q", envHistoryMaxAge, v)
+		}
+	}
+
+	if v := os.Getenv(envHistoryMaxEntries); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.HistoryMaxEntries = n
+		} else {
+			log.Printf("invalid %s value %q", envHistoryMaxEntries, v)
+		}
+	}
 }
 
 type fileConfig struct {
 	LivenessInterval       string `json:"liveness_interval"`
 	LastSeenUpdateInterval string `json:"last_seen_interval"`
+	LogMaxSize             string `json:"log_max_size"`
+	LogMaxFiles            *int   `json:"log_max_files"`
+	MetricsHistory         *int   `json:"metrics_history"`
+	LivenessBackend        string `json:"liveness_backend"`
+	CgroupRoot             string `json:"cgroup_root"`
+	JournalSync            string `json:"journal_sync"`
+	JournalSyncInterval    string `json:"journal_sync_interval"`
+	JournalCompactEvery    *int   `json:"journal_compact_every"`
+	Store                  string `json:"store"`
+	HistoryMaxAge          string `json:"history_max_age"`
+	HistoryMaxEntries      *int   `json:"history_max_entries"`
 }
 
 func loadFromFile(path string) (Config, error) {
-	var cfg Config
+	cfg := Config{LogMaxFiles: -1, MetricsHistory: -1, HistoryMaxAge: -1, HistoryMaxEntries: -1}
 
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -102,6 +324,127 @@ func loadFromFile(path string) (Config, error) {
 		}
 		cfg.LastSeenUpdateInterval = dur
 	}
+	if raw.LogMaxSize != "" {
+		size, err := parseSize(raw.LogMaxSize)
+		if err != nil {
+			return cfg, fmt.Errorf("parse log_max_size: %w", err)
+		}
+		if size <= 0 {
+			return cfg, errors.New("log_max_size must be > 0")
+		}
+		cfg.LogMaxSize = size
+	}
+	if raw.LogMaxFiles != nil {
+		if *raw.LogMaxFiles < 0 {
+			return cfg, errors.New("log_max_files must be >= 0")
+		}
+		cfg.LogMaxFiles = *raw.LogMaxFiles
+	}
+	if raw.MetricsHistory != nil {
+		if *raw.MetricsHistory < 0 {
+			return cfg, errors.New("metrics_history must be >= 0")
+		}
+		cfg.MetricsHistory = *raw.MetricsHistory
+	}
+	if raw.LivenessBackend != "" {
+		backend, err := parseLivenessBackend(raw.LivenessBackend)
+		if err != nil {
+			return cfg, fmt.Errorf("parse liveness_backend: %w", err)
+		}
+		cfg.LivenessBackend = backend
+	}
+	if raw.CgroupRoot != "" {
+		if !filepath.IsAbs(raw.CgroupRoot) {
+			return cfg, errors.New("cgroup_root must be an absolute path")
+		}
+		cfg.CgroupRoot = filepath.Clean(raw.CgroupRoot)
+	}
+	if raw.JournalSync != "" {
+		mode, err := parseJournalSync(raw.JournalSync)
+		if err != nil {
+			return cfg, fmt.Errorf("parse journal_sync: %w", err)
+		}
+		cfg.JournalSync = mode
+	}
+	if raw.JournalSyncInterval != "" {
+		dur, err := time.ParseDuration(raw.JournalSyncInterval)
+		if err != nil {
+			return cfg, fmt.Errorf("parse journal_sync_interval: %w", err)
+		}
+		if dur <= 0 {
+			return cfg, errors.New("journal_sync_interval must be > 0")
+		}
+		cfg.JournalSyncInterval = dur
+	}
+	if raw.JournalCompactEvery != nil {
+		if *raw.JournalCompactEvery <= 0 {
+			return cfg, errors.New("journal_compact_every must be > 0")
+		}
+		cfg.JournalCompactEvery = *raw.JournalCompactEvery
+	}
+	if raw.Store != "" {
+		store, err := parseStore(raw.Store)
+		if err != nil {
+			return cfg, fmt.Errorf("parse store: %w", err)
+		}
+		cfg.Store = store
+	}
+	if raw.HistoryMaxAge != "" {
+		dur, err := time.ParseDuration(raw.HistoryMaxAge)
+		if err != nil {
+			return cfg, fmt.Errorf("parse history_max_age: %w", err)
+		}
+		if dur < 0 {
+			return cfg, errors.New("history_max_age must be >= 0")
+		}
+		cfg.HistoryMaxAge = dur
+	}
+	if raw.HistoryMaxEntries != nil {
+		if *raw.HistoryMaxEntries < 0 {
+			return cfg, errors.New("history_max_entries must be >= 0")
+		}
+		cfg.HistoryMaxEntries = *raw.HistoryMaxEntries
+	}
 
 	return cfg, nil
 }
+
+func parseLivenessBackend(raw string) (string, error) {
+	switch v := strings.ToLower(strings.TrimSpace(raw)); v {
+	case LivenessPoll, LivenessPidfd:
+		return v, nil
+	default:
+		return "", fmt.Errorf("unknown backend %q (expected poll or pidfd)", raw)
+	}
+}
+
+func parseJournalSync(raw string) (string, error) {
+	switch v := strings.ToLower(strings.TrimSpace(raw)); v {
+	case JournalSyncAlways, JournalSyncBatch, JournalSyncNone:
+		return v, nil
+	default:
+		return "", fmt.Errorf("unknown sync mode %q (expected always, batch or none)", raw)
+	}
+}
+
+func parseStore(raw string) (string, error) {
+	switch v := strings.ToLower(strings.TrimSpace(raw)); v {
+	case StoreJSON, StoreBolt:
+		return v, nil
+	default:
+		return "", fmt.Errorf("unknown store %q (expected json or bolt)", raw)
+	}
+}
+
+// parseSize reads a size with units.ParseBytes, e.g. 512KB or 1.5GiB, and
+// checks that it fits the int64 fields sizes are kept in.
+func parseSize(raw string) (int64, error) {
+	n, err := units.ParseBytes(raw)
+	if err != nil {
+		return 0, err
+	}
+	if n > math.MaxInt64 {
+		return 0, fmt.Errorf("size %q is too large", raw)
+	}
+	return int64(n), nil
+}
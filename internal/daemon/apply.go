@@ -0,0 +1,295 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	goprocv1 "goproc/api/proto/goproc/v1"
+	"goproc/internal/registry"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Apply actions reported in ApplyAction.
+const (
+	applyCreate    = "create"
+	applyRestart   = "restart"
+	applyUpdate    = "update"
+	applyUnchanged = "unchanged"
+	applyPrune     = "prune"
+	applyConflict  = "conflict"
+)
+
+// applyItem is one step of an apply plan. want is nil for prunes; have is
+// the current entry, if any.
+type applyItem struct {
+	action string
+	reason []string
+	name   string
+	want   *registry.AddParams
+	have   *registry.Proc
+	out    *goprocv1.ApplyAction
+}
+
+// Apply reconciles the entries owned by a manifest with the processes it
+// declares. Entries are matched by name: missing ones are started, ones
+// whose command, environment, working directory, restart policy, cgroup or
+// child tracking differ are stopped and started again (under a new ID),
+// label changes are applied in place, and with prune, entries of the
+// manifest that are no longer declared are stopped and removed. Names held
+// by entries outside the manifest are reported as conflicts and left alone.
+func (s *service) Apply(ctx context.Context, req *goprocv1.ApplyRequest) (*goprocv1.ApplyResponse, error) {
+	manifest := strings.TrimSpace(req.GetManifest())
+	if manifest == "" {
+		return nil, status.Error(codes.InvalidArgument, "manifest name must be provided")
+	}
+	if req.GetGraceMs() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "grace_ms must not be negative")
+	}
+	plan, err := s.planApply(manifest, req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	resp := &goprocv1.ApplyResponse{}
+	for _, it := range plan {
+		it.out = &goprocv1.ApplyAction{Name: it.name, Action: it.action, Reason: strings.Join(it.reason, ", ")}
+		if it.have != nil {
+			it.out.Id = uint64(it.have.ID)
+			it.out.Pid = int32(it.have.PID)
+		}
+		if it.action == applyConflict {
+			it.out.Error = fmt.Sprintf("name %q is taken by id %d, which manifest %q does not manage", it.name, it.have.ID, manifest)
+		}
+		resp.Actions = append(resp.Actions, it.out)
+	}
+	if req.GetDryRun() {
+		return resp, nil
+	}
+
+	// Stop everything that goes away first, concurrently, so the total
+	// wait is one grace period rather than one per process.
+	grace := time.Duration(req.GetGraceMs()) * time.Millisecond
+	var wg sync.WaitGroup
+	for _, it := range plan {
+		if it.action != applyRestart && it.action != applyPrune {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.stopAndRemove(ctx, *it.have, grace); err != nil {
+				it.out.Error = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, it := range plan {
+		if it.out.Error != "" {
+			continue
+		}
+		switch it.action {
+		case applyCreate, applyRestart:
+			id, pid, err := s.sup.spawn(*it.want)
+			if err != nil {
+				it.out.Error = err.Error()
+				continue
+			}
+			it.out.Id, it.out.Pid = uint64(id), int32(pid)
+		case applyUpdate:
+			if err := s.relabel(it.have.ID, *it.want); err != nil {
+				it.out.Error = err.Error()
+			}
+		}
+	}
+	return resp, nil
+}
+
+// planApply validates the declared processes and decides what to do with
+// each of them, followed by the prunes.
+func (s *service) planApply(manifest string, req *goprocv1.ApplyRequest) ([]*applyItem, error) {
+	declared := make(map[string]struct{}, len(req.GetProcesses()))
+	plan := make([]*applyItem, 0, len(req.GetProcesses()))
+	for i, p := range req.GetProcesses() {
+		name, err := registry.NormalizeName(p.GetName())
+		if err != nil {
+			return nil, fmt.Errorf("process %d: %w", i+1, err)
+		}
+		if name == "" {
+			return nil, fmt.Errorf("process %d: name must be provided", i+1)
+		}
+		if _, dup := declared[name]; dup {
+			return nil, fmt.Errorf("process %q is declared twice", name)
+		}
+		declared[name] = struct{}{}
+		want, err := spawnParamsFromProto(p)
+		if err != nil {
+			return nil, fmt.Errorf("process %q: %w", name, err)
+		}
+		want.Name = name
+		want.Manifest = &registry.ManifestSource{Name: manifest, Env: want.Spawn.Env}
+		want.Spawn.Env = append(os.Environ(), want.Spawn.Env...)
+
+		it := &applyItem{name: name, want: &want}
+		if ps := s.reg.List(registry.ListFilter{Names: []string{name}}); len(ps) > 0 {
+			it.have = &ps[0]
+		}
+		switch {
+		case it.have == nil:
+			it.action = applyCreate
+		case it.have.Manifest == nil || it.have.Manifest.Name != manifest:
+			it.action = applyConflict
+		default:
+			if it.reason = restartReasons(*it.have, want); len(it.reason) > 0 {
+				it.action = applyRestart
+			} else if it.reason = labelReasons(*it.have, want); len(it.reason) > 0 {
+				it.action = applyUpdate
+			} else {
+				it.action = applyUnchanged
+			}
+		}
+		plan = append(plan, it)
+	}
+	if !req.GetPrune() {
+		return plan, nil
+	}
+	for _, p := range s.reg.List(registry.ListFilter{}) {
+		if p.Manifest == nil || p.Manifest.Name != manifest {
+			continue
+		}
+		if _, ok := declared[p.Name]; ok {
+			continue
+		}
+		plan = append(plan, &applyItem{action: applyPrune, name: p.Name, have: &p})
+	}
+	return plan, nil
+}
+
+// restartReasons lists the differences that need a new process.
+func restartReasons(have registry.Proc, want registry.AddParams) []string {
+	var reasons []string
+	if have.Spawn == nil || !slices.Equal(have.Spawn.Argv, want.Spawn.Argv) {
+		reasons = append(reasons, "command")
+	}
+	if !slices.Equal(have.Manifest.Env, want.Manifest.Env) {
+		reasons = append(reasons, "env")
+	}
+	if have.Spawn == nil || have.Spawn.Dir != want.Spawn.Dir {
+		reasons = append(reasons, "cwd")
+	}
+	if have.Restart == nil || *have.Restart != *want.Restart {
+		reasons = append(reasons, "restart policy")
+	}
+	if !sameCgroupLimits(have.Cgroup, want.Cgroup) {
+		reasons = append(reasons, "cgroup")
+	}
+	if have.TrackChildren != want.TrackChildren {
+		reasons = append(reasons, "track_children")
+	}
+	if len(reasons) == 0 && !have.Alive {
+		reasons = append(reasons, "not running")
+	}
+	return reasons
+}
+
+// labelReasons lists the label differences that can be applied in place.
+func labelReasons(have registry.Proc, want registry.AddParams) []string {
+	var reasons []string
+	if !sameLabels(have.Meta.Tags, want.Tags) {
+		reasons = append(reasons, "tags")
+	}
+	if !sameLabels(have.Meta.Groups, want.Groups) {
+		reasons = append(reasons, "groups")
+	}
+	if !maps.Equal(have.Meta.Annotations, want.Annotations) {
+		reasons = append(reasons, "annotations")
+	}
+	return reasons
+}
+
+func sameCgroupLimits(a, b *registry.CgroupSpec) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.MemoryMax == b.MemoryMax && a.CPUMax == b.CPUMax && a.PIDsMax == b.PIDsMax
+}
+
+// sameLabels compares a stored label list with a declared one, which may be
+// unsorted or contain blanks and duplicates.
+func sameLabels(have, want []string) bool {
+	return slices.Equal(labelSet(have), labelSet(want))
+}
+
+func labelSet(labels []string) []string {
+	out := make([]string, 0, len(labels))
+	for _, l := range labels {
+		if l = strings.TrimSpace(l); l != "" {
+			out = append(out, l)
+		}
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// relabel makes the tags, groups and annotations of an entry match want.
+func (s *service) relabel(id registry.ProcID, want registry.AddParams) error {
+	p, ok := s.reg.Get(id)
+	if !ok {
+		return fmt.Errorf("id %d not found", id)
+	}
+	have := labelSet(p.Meta.Tags)
+	tags := labelSet(want.Tags)
+	groupsHave := labelSet(p.Meta.Groups)
+	groups := labelSet(want.Groups)
+	_, err := s.reg.UpdateLabels(id, registry.LabelChange{
+		AddTags:      missingFrom(have, tags),
+		RemoveTags:   missingFrom(tags, have),
+		AddGroups:    missingFrom(groupsHave, groups),
+		RemoveGroups: missingFrom(groups, groupsHave),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = s.reg.SetAnnotations(id, want.Annotations)
+	return err
+}
+
+// missingFrom returns the labels of want that set lacks.
+func missingFrom(set, want []string) []string {
+	var out []string
+	for _, l := range want {
+		if !slices.Contains(set, l) {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// stopAndRemove terminates an entry's process (its whole cgroup or process
+// group), escalating to SIGKILL after grace, and removes the entry.
+func (s *service) stopAndRemove(ctx context.Context, p registry.Proc, grace time.Duration) error {
+	s.sup.stop(p.ID)
+	if p.Alive && !pidReused(p) {
+		target := killTarget{pid: p.PID, pgid: p.PGID}
+		if p.Cgroup != nil {
+			target.cgroup = p.Cgroup.Path
+		}
+		outcome, err := target.terminate(ctx, syscall.SIGTERM, grace, true)
+		if err != nil && err != syscall.ESRCH {
+			return fmt.Errorf("stop id %d: %w", p.ID, err)
+		}
+		if outcome == killRunning {
+			return fmt.Errorf("id %d is still running after SIGKILL", p.ID)
+		}
+	}
+	s.remove(p, registry.RemovalRm)
+	return nil
+}
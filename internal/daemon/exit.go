@@ -0,0 +1,38 @@
+package daemon
+
+import (
+	"syscall"
+	"time"
+
+	"goproc/internal/registry"
+	"goproc/internal/signals"
+)
+
+// exitStatusFromWait converts a wait(2) status into a registry exit record.
+func exitStatusFromWait(ws syscall.WaitStatus, at time.Time) registry.ExitStatus {
+	switch {
+	case ws.Exited():
+		return registry.ExitStatus{At: at, Reason: registry.ExitReasonExited, Code: ws.ExitStatus()}
+	case ws.Signaled():
+		return registry.ExitStatus{
+			At:         at,
+			Reason:     registry.ExitReasonSignaled,
+			Code:       -1,
+			Signal:     signalName(ws.Signal()),
+			CoreDumped: ws.CoreDump(),
+		}
+	default:
+		return unknownExit(at)
+	}
+}
+
+func unknownExit(at time.Time) registry.ExitStatus {
+	return registry.ExitStatus{At: at, Reason: registry.ExitReasonUnknown, Code: -1}
+}
+
+func signalName(sig syscall.Signal) string {
+	if name := signals.Name(sig); name != "" {
+		return name
+	}
+	return sig.String()
+}
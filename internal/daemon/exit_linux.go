@@ -0,0 +1,238 @@
+//go:build linux
+
+package daemon
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+
+	"goproc/internal/registry"
+)
+
+// pidfd_info from <linux/pidfd.h> (PIDFD_INFO_SIZE_VER0). PIDFD_GET_INFO is
+// available since Linux 6.13; PIDFD_INFO_EXIT reports the wait status of a
+// process that has exited and been reaped, even if we are not its parent.
+type pidfdInfo struct {
+	Mask     uint64
+	CgroupID uint64
+	PID      uint32
+	TGID     uint32
+	PPID     uint32
+	RUID     uint32
+	RGID     uint32
+	EUID     uint32
+	EGID     uint32
+	SUID     uint32
+	SGID     uint32
+	FSUID    uint32
+	FSGID    uint32
+	ExitCode int32
+}
+
+const (
+	pidfdInfoExit = 1 << 3
+	// _IOWR(PIDFS_IOCTL_MAGIC, 11, struct pidfd_info)
+	pidfdGetInfo = 0xC0000000 | uintptr(unsafe.Sizeof(pidfdInfo{}))<<16 | 0xFF<<8 | 11
+)
+
+// exitTracker holds a pidfd for every adopted process so that its exit
+// status can be recovered after it dies. Without kernel support lookups
+// simply report nothing and callers fall back to an unknown status.
+//
+// With notify enabled the pidfds are also registered with epoll: a pidfd
+// becomes readable when its process exits, so deaths are reported at once
+// instead of on the next liveness tick.
+type exitTracker struct {
+	mu    sync.Mutex
+	fds   map[registry.ProcID]trackedPidfd
+	byFD  map[int]registry.ProcID
+	noAPI bool // pidfd_open unsupported; stop trying
+
+	epfd   int // -1 unless notify is enabled
+	wakefd int // eventfd that stops the epoll loop
+	onExit func(registry.ProcID)
+}
+
+type trackedPidfd struct {
+	pid int
+	fd  int
+}
+
+func newExitTracker() *exitTracker {
+	return &exitTracker{
+		fds:    make(map[registry.ProcID]trackedPidfd),
+		byFD:   make(map[int]registry.ProcID),
+		epfd:   -1,
+		wakefd: -1,
+	}
+}
+
+// notify starts reporting exits of tracked processes to fn, which runs on
+// its own goroutine per event. It fails when pidfds or epoll are unavailable.
+func (t *exitTracker) notify(fn func(registry.ProcID)) error {
+	probe, err := unix.PidfdOpen(os.Getpid(), 0)
+	if err != nil {
+		return fmt.Errorf("pidfd_open: %w", err)
+	}
+	unix.Close(probe)
+
+	epfd, err := unix.EpollCreate1(unix.EPOLL_CLOEXEC)
+	if err != nil {
+		return fmt.Errorf("epoll_create1: %w", err)
+	}
+	wakefd, err := unix.Eventfd(0, unix.EFD_CLOEXEC|unix.EFD_NONBLOCK)
+	if err != nil {
+		unix.Close(epfd)
+		return fmt.Errorf("eventfd: %w", err)
+	}
+	if err := unix.EpollCtl(epfd, unix.EPOLL_CTL_ADD, wakefd, &unix.EpollEvent{Events: unix.EPOLLIN, Fd: int32(wakefd)}); err != nil {
+		unix.Close(wakefd)
+		unix.Close(epfd)
+		return fmt.Errorf("epoll_ctl: %w", err)
+	}
+
+	t.mu.Lock()
+	t.epfd, t.wakefd, t.onExit = epfd, wakefd, fn
+	for _, tp := range t.fds {
+		t.pollLocked(tp.fd)
+	}
+	t.mu.Unlock()
+
+	go t.loop(epfd, wakefd)
+	return nil
+}
+
+// pollLocked arms a one-shot readiness notification for a pidfd.
+func (t *exitTracker) pollLocked(fd int) {
+	if t.epfd < 0 {
+		return
+	}
+	ev := unix.EpollEvent{Events: unix.EPOLLIN | unix.EPOLLONESHOT, Fd: int32(fd)}
+	if err := unix.EpollCtl(t.epfd, unix.EPOLL_CTL_ADD, fd, &ev); err != nil {
+		log.Printf("liveness: epoll_ctl pidfd %d: %v", fd, err)
+	}
+}
+
+func (t *exitTracker) loop(epfd, wakefd int) {
+	events := make([]unix.EpollEvent, 64)
+	for {
+		n, err := unix.EpollWait(epfd, events, -1)
+		if err != nil {
+			if err == unix.EINTR {
+				continue
+			}
+			log.Printf("liveness: epoll_wait: %v", err)
+			return
+		}
+		for _, ev := range events[:n] {
+			fd := int(ev.Fd)
+			if fd == wakefd {
+				unix.Close(wakefd)
+				unix.Close(epfd)
+				return
+			}
+			t.mu.Lock()
+			id, ok := t.byFD[fd]
+			fn := t.onExit
+			t.mu.Unlock()
+			if ok && fn != nil {
+				go fn(id)
+			}
+		}
+	}
+}
+
+// track opens a pidfd for pid unless one is already held. It must be called
+// while the process is still alive.
+func (t *exitTracker) track(id registry.ProcID, pid int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.noAPI {
+		return
+	}
+	if cur, ok := t.fds[id]; ok {
+		if cur.pid == pid {
+			return
+		}
+		t.closeLocked(id, cur)
+	}
+	fd, err := unix.PidfdOpen(pid, unix.PIDFD_NONBLOCK)
+	if err != nil {
+		if err == unix.ENOSYS {
+			t.noAPI = true
+		}
+		return
+	}
+	t.fds[id] = trackedPidfd{pid: pid, fd: fd}
+	t.byFD[fd] = id
+	t.pollLocked(fd)
+}
+
+// closeLocked forgets a pidfd; closing it also drops it from the epoll set.
+func (t *exitTracker) closeLocked(id registry.ProcID, tp trackedPidfd) {
+	unix.Close(tp.fd)
+	delete(t.fds, id)
+	delete(t.byFD, tp.fd)
+}
+
+// exited returns the exit status of a dead tracked process and releases its
+// pidfd. ok is false when no status could be obtained.
+func (t *exitTracker) exited(id registry.ProcID, at time.Time) (registry.ExitStatus, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tp, found := t.fds[id]
+	if !found {
+		return registry.ExitStatus{}, false
+	}
+	defer t.closeLocked(id, tp)
+
+	info := pidfdInfo{Mask: pidfdInfoExit}
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(tp.fd), pidfdGetInfo, uintptr(unsafe.Pointer(&info))); errno != 0 {
+		return registry.ExitStatus{}, false
+	}
+	if info.Mask&pidfdInfoExit == 0 {
+		return registry.ExitStatus{}, false
+	}
+	return exitStatusFromWait(syscall.WaitStatus(info.ExitCode), at), true
+}
+
+// release drops the pidfd of an entry that is no longer watched.
+func (t *exitTracker) release(id registry.ProcID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if tp, ok := t.fds[id]; ok {
+		t.closeLocked(id, tp)
+	}
+}
+
+// prune releases pidfds of entries that left the registry.
+func (t *exitTracker) prune(keep map[registry.ProcID]struct{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id, tp := range t.fds {
+		if _, ok := keep[id]; !ok {
+			t.closeLocked(id, tp)
+		}
+	}
+}
+
+func (t *exitTracker) close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id, tp := range t.fds {
+		t.closeLocked(id, tp)
+	}
+	if t.wakefd >= 0 {
+		var one [8]byte
+		one[0] = 1
+		unix.Write(t.wakefd, one[:])
+		t.epfd, t.wakefd, t.onExit = -1, -1, nil
+	}
+}
@@ -0,0 +1,136 @@
+//go:build linux
+
+package daemon
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	goprocv1 "goproc/api/proto/goproc/v1"
+	"goproc/internal/config"
+	"goproc/internal/registry"
+)
+
+// startSleep starts a process the test kills itself; the cleanup reaps it
+// if the test did not.
+func startSleep(t *testing.T) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+	return cmd
+}
+
+// waitDead waits for the registry to mark an entry dead.
+func waitDead(t *testing.T, s *service, id registry.ProcID, within time.Duration) registry.Proc {
+	t.Helper()
+	deadline := time.Now().Add(within)
+	for {
+		p, ok := s.reg.Get(id)
+		if !ok {
+			t.Fatalf("entry %d vanished", id)
+		}
+		if !p.Alive {
+			return p
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("entry %d still alive after %s", id, within)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestExitTrackerNotifiesThroughPidfd(t *testing.T) {
+	tracker := newExitTracker()
+	defer tracker.close()
+	exits := make(chan registry.ProcID, 1)
+	if err := tracker.notify(func(id registry.ProcID) { exits <- id }); err != nil {
+		t.Skipf("pidfd backend unavailable: %v", err)
+	}
+	cmd := startSleep(t)
+	tracker.track(7, cmd.Process.Pid)
+	_ = cmd.Process.Kill()
+	_ = cmd.Wait()
+
+	select {
+	case id := <-exits:
+		if id != 7 {
+			t.Fatalf("notified id %d, want 7", id)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no exit notification")
+	}
+	st, ok := tracker.exited(7, time.Now())
+	if !ok {
+		t.Log("kernel does not report exit status through pidfds")
+	} else if st.Reason != registry.ExitReasonSignaled || st.Signal != "SIGKILL" {
+		t.Fatalf("exit status = %+v, want killed by SIGKILL", st)
+	}
+	if _, ok := tracker.exited(7, time.Now()); ok {
+		t.Fatal("pidfd not released after its exit was read")
+	}
+}
+
+func TestServiceSeesExitThroughPidfd(t *testing.T) {
+	if err := newExitTracker().notify(func(registry.ProcID) {}); err != nil {
+		t.Skipf("pidfd backend unavailable: %v", err)
+	}
+	// With a tick this long, only the pidfd can report the death in time.
+	s := newTestService(t, func(cfg *config.Config) {
+		cfg.LivenessBackend = config.LivenessPidfd
+		cfg.LivenessInterval = time.Hour
+	})
+	cmd := startSleep(t)
+	resp, err := s.Add(context.Background(), &goprocv1.AddRequest{Pid: int32(cmd.Process.Pid)})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	_ = cmd.Process.Signal(syscall.SIGTERM)
+	_ = cmd.Wait()
+
+	p := waitDead(t, s, registry.ProcID(resp.GetId()), 2*time.Second)
+	if p.Exit == nil {
+		t.Fatalf("dead entry without exit status: %+v", p)
+	}
+	if p.Exit.Reason != registry.ExitReasonUnknown && p.Exit.Signal != "SIGTERM" {
+		t.Fatalf("exit status = %+v, want terminated by SIGTERM", p.Exit)
+	}
+}
+
+func TestServiceFallsBackToPolling(t *testing.T) {
+	s := newTestService(t, func(cfg *config.Config) {
+		cfg.LivenessBackend = config.LivenessPoll
+		cfg.LivenessInterval = 50 * time.Millisecond
+	})
+	// Behave as on a kernel without pidfd_open.
+	s.exits.mu.Lock()
+	s.exits.noAPI = true
+	s.exits.mu.Unlock()
+
+	cmd := startSleep(t)
+	resp, err := s.Add(context.Background(), &goprocv1.AddRequest{Pid: int32(cmd.Process.Pid)})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	s.exits.mu.Lock()
+	_, tracked := s.exits.fds[registry.ProcID(resp.GetId())]
+	s.exits.mu.Unlock()
+	if tracked {
+		t.Fatal("pidfd opened without kernel support")
+	}
+	_ = cmd.Process.Kill()
+	_ = cmd.Wait()
+
+	p := waitDead(t, s, registry.ProcID(resp.GetId()), 2*time.Second)
+	if p.Exit == nil || p.Exit.Reason != registry.ExitReasonUnknown {
+		t.Fatalf("exit status = %+v, want unknown without pidfds", p.Exit)
+	}
+}
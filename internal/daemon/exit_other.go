@@ -0,0 +1,32 @@
+//go:build !linux
+
+package daemon
+
+import (
+	"errors"
+	"time"
+
+	"goproc/internal/registry"
+)
+
+// exitTracker is a no-op outside Linux: exit statuses of adopted processes
+// are reported as unknown.
+type exitTracker struct{}
+
+func newExitTracker() *exitTracker { return &exitTracker{} }
+
+func (t *exitTracker) notify(func(registry.ProcID)) error {
+	return errors.New("pidfd liveness requires Linux")
+}
+
+func (t *exitTracker) track(registry.ProcID, int) {}
+
+func (t *exitTracker) exited(registry.ProcID, time.Time) (registry.ExitStatus, bool) {
+	return registry.ExitStatus{}, false
+}
+
+func (t *exitTracker) release(registry.ProcID) {}
+
+func (t *exitTracker) prune(map[registry.ProcID]struct{}) {}
+
+func (t *exitTracker) close() {}
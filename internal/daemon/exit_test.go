@@ -0,0 +1,27 @@
+package daemon
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"goproc/internal/registry"
+)
+
+func TestExitStatusFromWait(t *testing.T) {
+	at := time.Unix(10, 0)
+	cases := []struct {
+		ws   syscall.WaitStatus
+		want registry.ExitStatus
+	}{
+		{0x0300, registry.ExitStatus{At: at, Reason: registry.ExitReasonExited, Code: 3}},
+		{0x0000, registry.ExitStatus{At: at, Reason: registry.ExitReasonExited, Code: 0}},
+		{0x000b | 0x80, registry.ExitStatus{At: at, Reason: registry.ExitReasonSignaled, Code: -1, Signal: "SIGSEGV", CoreDumped: true}},
+		{0x0009, registry.ExitStatus{At: at, Reason: registry.ExitReasonSignaled, Code: -1, Signal: "SIGKILL"}},
+	}
+	for _, tc := range cases {
+		if got := exitStatusFromWait(tc.ws, at); got != tc.want {
+			t.Fatalf("status %#x: expected %+v, got %+v", uint32(tc.ws), tc.want, got)
+		}
+	}
+}
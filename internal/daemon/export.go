@@ -0,0 +1,87 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+
+	goprocv1 "goproc/api/proto/goproc/v1"
+	"goproc/internal/registry"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Export returns the matching entries as a document for Import.
+func (s *service) Export(ctx context.Context, req *goprocv1.ExportRequest) (*goprocv1.ExportResponse, error) {
+	filter, err := filterFromRequest(req.GetFilter())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	doc, n, err := s.reg.Export(filter)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "export failed: %v", err)
+	}
+	return &goprocv1.ExportResponse{Document: doc, Count: uint32(n)}, nil
+}
+
+// Import adds the entries of an exported document under new IDs. With
+// readopt, entries whose process is still running here are tracked again;
+// the others are imported detached from their PID. With respawn, detached
+// entries the daemon started are started again. Entries removed to make
+// room are released like removed ones.
+func (s *service) Import(ctx context.Context, req *goprocv1.ImportRequest) (*goprocv1.ImportResponse, error) {
+	policy, err := registry.ParseNameConflict(req.GetOnConflict())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	procs, err := registry.ParseExport(req.GetDocument())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	opts := registry.ImportOptions{Replace: req.GetReplace(), OnConflict: policy}
+	if req.GetReadopt() {
+		opts.Adopt = isSameProcess
+	}
+	report, err := s.reg.Import(procs, opts)
+	if err != nil {
+		if errors.Is(err, registry.ErrNameInUse) {
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	for _, p := range report.Removed {
+		s.release(p)
+	}
+
+	resp := &goprocv1.ImportResponse{
+		Results: make([]*goprocv1.ImportResult, 0, len(report.Results)),
+		Removed: uint32(len(report.Removed)),
+	}
+	for _, res := range report.Results {
+		out := &goprocv1.ImportResult{
+			FromId:     uint64(res.From),
+			Id:         uint64(res.ID),
+			Name:       res.Name,
+			Action:     string(res.Action),
+			Alive:      res.Alive,
+			ReplacedId: uint64(res.Replaced),
+			Note:       res.Note,
+		}
+		resp.Results = append(resp.Results, out)
+		p, ok := s.reg.Get(res.ID)
+		if !ok {
+			continue
+		}
+		out.Pid = int32(p.PID)
+		if !req.GetRespawn() || p.Alive || p.Spawn == nil {
+			continue
+		}
+		pid, err := s.sup.relaunch(p.ID)
+		if err != nil {
+			out.Error = err.Error()
+			continue
+		}
+		out.Pid, out.Alive, out.Respawned = int32(pid), true, true
+	}
+	return resp, nil
+}
@@ -0,0 +1,113 @@
+package daemon
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"goproc/internal/registry"
+)
+
+// metricPoint is one stored sample of a process's usage.
+type metricPoint struct {
+	At  time.Time
+	CPU float64
+	RSS uint64
+}
+
+// metricsHistory keeps a bounded ring of recent samples per registry ID.
+type metricsHistory struct {
+	size int
+
+	mu     sync.Mutex
+	series map[registry.ProcID]*ring
+}
+
+type ring struct {
+	points []metricPoint
+	next   int // write position once points is full
+}
+
+func newMetricsHistory(size int) *metricsHistory {
+	return &metricsHistory{size: size, series: make(map[registry.ProcID]*ring)}
+}
+
+func (h *metricsHistory) add(id registry.ProcID, pt metricPoint) {
+	if h.size <= 0 {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	r := h.series[id]
+	if r == nil {
+		r = &ring{points: make([]metricPoint, 0, min(h.size, 64))}
+		h.series[id] = r
+	}
+	if len(r.points) < h.size {
+		r.points = append(r.points, pt)
+		return
+	}
+	r.points[r.next] = pt
+	r.next = (r.next + 1) % h.size
+}
+
+// since returns the samples of id taken at or after cutoff, oldest first.
+func (h *metricsHistory) since(id registry.ProcID, cutoff time.Time) []metricPoint {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	r := h.series[id]
+	if r == nil {
+		return nil
+	}
+	out := make([]metricPoint, 0, len(r.points))
+	for i := range r.points {
+		pt := r.points[(r.next+i)%len(r.points)]
+		if !pt.At.Before(cutoff) {
+			out = append(out, pt)
+		}
+	}
+	return out
+}
+
+func (h *metricsHistory) forget(id registry.ProcID) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.series, id)
+}
+
+// prune drops series for IDs that are no longer in the registry.
+func (h *metricsHistory) prune(keep map[registry.ProcID]struct{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for id := range h.series {
+		if _, ok := keep[id]; !ok {
+			delete(h.series, id)
+		}
+	}
+}
+
+// summary is min/avg/max/p95 over a series of values.
+type summary struct {
+	Min, Avg, Max, P95 float64
+}
+
+func summarize(values []float64) summary {
+	if len(values) == 0 {
+		return summary{}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	// Nearest-rank percentile.
+	rank := int(math.Ceil(0.95*float64(len(sorted)))) - 1
+	return summary{
+		Min: sorted[0],
+		Avg: sum / float64(len(sorted)),
+		Max: sorted[len(sorted)-1],
+		P95: sorted[max(rank, 0)],
+	}
+}
@@ -0,0 +1,60 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"goproc/internal/registry"
+)
+
+func TestMetricsHistoryKeepsNewestSamples(t *testing.T) {
+	h := newMetricsHistory(3)
+	base := time.Unix(1000, 0)
+	for i := 0; i < 5; i++ {
+		h.add(1, metricPoint{At: base.Add(time.Duration(i) * time.Second), CPU: float64(i)})
+	}
+	got := h.since(1, time.Time{})
+	if len(got) != 3 {
+		t.Fatalf("expected 3 samples, got %d", len(got))
+	}
+	for i, want := range []float64{2, 3, 4} {
+		if got[i].CPU != want {
+			t.Fatalf("sample %d: expected cpu %v, got %v", i, want, got[i].CPU)
+		}
+	}
+	if got := h.since(1, base.Add(4*time.Second)); len(got) != 1 || got[0].CPU != 4 {
+		t.Fatalf("expected only the newest sample inside the window, got %+v", got)
+	}
+}
+
+func TestMetricsHistoryDisabled(t *testing.T) {
+	h := newMetricsHistory(0)
+	h.add(1, metricPoint{At: time.Now()})
+	if got := h.since(1, time.Time{}); len(got) != 0 {
+		t.Fatalf("expected no samples, got %d", len(got))
+	}
+}
+
+func TestMetricsHistoryPrune(t *testing.T) {
+	h := newMetricsHistory(2)
+	h.add(1, metricPoint{})
+	h.add(2, metricPoint{})
+	h.prune(map[registry.ProcID]struct{}{2: {}})
+	if len(h.since(1, time.Time{})) != 0 || len(h.since(2, time.Time{})) != 1 {
+		t.Fatal("prune kept the wrong series")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	values := make([]float64, 0, 20)
+	for i := 1; i <= 20; i++ {
+		values = append(values, float64(i))
+	}
+	s := summarize(values)
+	if s.Min != 1 || s.Max != 20 || s.Avg != 10.5 || s.P95 != 19 {
+		t.Fatalf("unexpected summary %+v", s)
+	}
+	if (summarize(nil) != summary{}) {
+		t.Fatal("expected zero summary for empty input")
+	}
+}
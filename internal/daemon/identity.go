@@ -0,0 +1,40 @@
+package daemon
+
+import (
+	"sync"
+	"syscall"
+
+	"goproc/internal/procfs"
+	"goproc/internal/registry"
+)
+
+// currentBootID is read once; it is empty where /proc is unavailable.
+var currentBootID = sync.OnceValue(func() string {
+	id, _ := procfs.BootID()
+	return id
+})
+
+// startTicksOf returns the kernel start time of pid, or 0 when unavailable.
+func startTicksOf(pid int) uint64 {
+	ticks, err := procfs.StartTicks(pid)
+	if err != nil {
+		return 0
+	}
+	return ticks
+}
+
+// isSameProcess reports whether the entry's PID still refers to the process
+// that was registered, rather than to an unrelated one that reused the PID.
+// The PID of a detached entry never does.
+func isSameProcess(p registry.Proc) bool {
+	if p.Detached || syscall.Kill(p.PID, 0) != nil {
+		return false
+	}
+	return !p.Reused(startTicksOf(p.PID), currentBootID())
+}
+
+// pidReused reports whether the entry's PID is currently held by a different
+// process than the one that was registered.
+func pidReused(p registry.Proc) bool {
+	return syscall.Kill(p.PID, 0) == nil && (p.Detached || p.Reused(startTicksOf(p.PID), currentBootID()))
+}
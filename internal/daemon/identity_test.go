@@ -0,0 +1,50 @@
+package daemon
+
+import (
+	"os/exec"
+	"testing"
+
+	"goproc/internal/registry"
+)
+
+func TestProcessIdentity(t *testing.T) {
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	pid := cmd.Process.Pid
+	ticks := startTicksOf(pid)
+	if ticks == 0 {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		t.Skip("process start times are unavailable")
+	}
+	p := registry.Proc{PID: pid, StartTicks: ticks, BootID: currentBootID()}
+
+	if !isSameProcess(p) || pidReused(p) {
+		t.Fatal("the registered process is not recognised")
+	}
+	other := p
+	other.StartTicks++
+	if isSameProcess(other) || !pidReused(other) {
+		t.Fatal("a different start time is not detected as PID reuse")
+	}
+	if currentBootID() != "" {
+		other = p
+		other.BootID = "another-boot"
+		if isSameProcess(other) || !pidReused(other) {
+			t.Fatal("a different boot ID is not detected as PID reuse")
+		}
+	}
+	detached := p
+	detached.Detached = true
+	if isSameProcess(detached) || !pidReused(detached) {
+		t.Fatal("a detached entry claims its PID")
+	}
+
+	_ = cmd.Process.Kill()
+	_ = cmd.Wait()
+	if isSameProcess(p) || pidReused(p) {
+		t.Fatal("a PID without a process is reported as running")
+	}
+}
@@ -0,0 +1,129 @@
+package daemon
+
+import (
+	"context"
+	"syscall"
+	"time"
+
+	"goproc/internal/cgroup"
+	"goproc/internal/procfs"
+)
+
+// Kill outcomes reported in KillResponse.
+const (
+	killExited    = "exited"
+	killEscalated = "escalated"
+	killRunning   = "running"
+)
+
+const (
+	// killPollInterval is how often a killed target is checked for exit.
+	killPollInterval = 50 * time.Millisecond
+	// killWait bounds the wait after SIGKILL, which cannot be caught but may
+	// be delayed by uninterruptible sleep.
+	killWait = 2 * time.Second
+	// killSettle gives a signal a moment to take effect when the caller
+	// does not want to wait, since delivery and exit are asynchronous.
+	killSettle = 100 * time.Millisecond
+)
+
+// killTarget is what a kill request signals: one process, its whole group or
+// every process in its cgroup. Descendants can leave the process group with
+// setsid or setpgid, but never the cgroup.
+type killTarget struct {
+	pid    int
+	pgid   int    // 0 signals only pid
+	cgroup string // cgroup v2 leaf; takes precedence over pgid
+}
+
+func (t killTarget) signal(sig syscall.Signal) error {
+	if t.cgroup != "" {
+		if sig == syscall.SIGKILL {
+			return cgroup.Kill(t.cgroup)
+		}
+		return cgroup.Signal(t.cgroup, sig)
+	}
+	if t.pgid > 0 {
+		return syscall.Kill(-t.pgid, sig)
+	}
+	return syscall.Kill(t.pid, sig)
+}
+
+// gone reports whether the target has exited. Zombies count as gone: they
+// hold no resources and only wait for their parent to reap them.
+func (t killTarget) gone() bool {
+	if t.cgroup != "" {
+		// Exited processes leave cgroup.procs even before they are reaped.
+		pids, err := cgroup.Procs(t.cgroup)
+		return err == nil && len(pids) == 0
+	}
+	if t.pgid <= 0 {
+		if syscall.Kill(t.pid, 0) != nil {
+			return true
+		}
+		st, err := procfs.Stat(t.pid)
+		return err == nil && st.Zombie()
+	}
+	if syscall.Kill(-t.pgid, 0) != nil {
+		return true
+	}
+	pids, err := procfs.PIDs()
+	if err != nil {
+		return false
+	}
+	for _, pid := range pids {
+		if st, err := procfs.Stat(pid); err == nil && st.PGID == t.pgid && !st.Zombie() {
+			return false
+		}
+	}
+	return true
+}
+
+// waitGone polls until the target exits, d elapses or ctx is cancelled.
+func (t killTarget) waitGone(ctx context.Context, d time.Duration) bool {
+	deadline := time.Now().Add(d)
+	for !t.gone() {
+		if !time.Now().Before(deadline) {
+			return false
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(killPollInterval):
+		}
+	}
+	return true
+}
+
+// terminate sends sig and, when asked to, waits out the grace period and
+// escalates to SIGKILL.
+func (t killTarget) terminate(ctx context.Context, sig syscall.Signal, grace time.Duration, escalate bool) (string, error) {
+	if err := t.signal(sig); err != nil {
+		return "", err
+	}
+	if sig == syscall.SIGKILL {
+		if t.waitGone(ctx, killWait) {
+			return killExited, nil
+		}
+		return killRunning, ctx.Err()
+	}
+	if grace <= 0 && !escalate {
+		if t.waitGone(ctx, killSettle) {
+			return killExited, nil
+		}
+		return killRunning, nil
+	}
+	if t.waitGone(ctx, grace) {
+		return killExited, nil
+	}
+	if !escalate || ctx.Err() != nil {
+		return killRunning, ctx.Err()
+	}
+	if err := t.signal(syscall.SIGKILL); err != nil && err != syscall.ESRCH {
+		return "", err
+	}
+	if t.waitGone(ctx, killWait) {
+		return killEscalated, nil
+	}
+	return killRunning, ctx.Err()
+}
@@ -33,6 +33,9 @@ func (s *Server) Close() error {
 	if s.grpcServer != nil {
 		s.grpcServer.GracefulStop()
 	}
+	if s.svc != nil {
+		s.svc.closeRegistry()
+	}
 	if s.ln != nil {
 		if err := s.ln.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
 			joined = errors.Join(joined, err)
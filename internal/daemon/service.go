@@ -2,46 +2,135 @@ package daemon
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"regexp"
 	"strings"
 	"syscall"
 	"time"
 
 	goprocv1 "goproc/api/proto/goproc/v1"
+	"goproc/internal/cgroup"
 	"goproc/internal/config"
+	"goproc/internal/logs"
+	"goproc/internal/pattern"
+	"goproc/internal/procfs"
+	"goproc/internal/query"
 	"goproc/internal/registry"
+	"goproc/internal/signals"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// logRotateInterval is how often captured output is checked against the size budget.
+const logRotateInterval = time.Second
+
+// reapWait bounds how long a pidfd exit notification waits for the parent to
+// reap the process, which is when its exit status becomes readable.
+const (
+	reapWait     = 2 * time.Second
+	reapWaitStep = 50 * time.Millisecond
 )
 
 // service implements the GoProc gRPC service backed by the registry.
 type service struct {
 	goprocv1.UnimplementedGoProcServer
 
-	cfg    config.Config
-	reg    *registry.Registry
-	cancel context.CancelFunc
+	cfg     config.Config
+	reg     *registry.Registry
+	sup     *supervisor
+	logs    *logs.Manager
+	cgroups *cgroup.Manager
+	cancel  context.CancelFunc
+
+	// samples holds the previous /proc reading per entry for CPU deltas.
+	// Only the liveness goroutine touches it.
+	samples map[registry.ProcID]pidSample
+	history *metricsHistory
+	exits   *exitTracker
+}
+
+// pidSample ties a reading to the PID it was taken from, so a respawned
+// entry does not compute CPU usage across two different processes.
+type pidSample struct {
+	pid int
+	procfs.Sample
 }
 
 func newService(cfg config.Config) (*service, error) {
-	reg, err := registry.New(SnapshotPath(), cfg.LastSeenUpdateInterval)
+	reg, err := registry.Open(registry.Options{
+		Store:            openStore(cfg),
+		LastSeenInterval: cfg.LastSeenUpdateInterval,
+		CompactEvery:     cfg.JournalCompactEvery,
+		History: registry.HistoryRetention{
+			MaxAge:     cfg.HistoryMaxAge,
+			MaxEntries: cfg.HistoryMaxEntries,
+		},
+	})
 	if err != nil {
 		return nil, err
 	}
 	ctx, cancel := context.WithCancel(context.Background())
+	lm := logs.NewManager(LogDir(), cfg.LogMaxSize, cfg.LogMaxFiles)
+	cm := cgroup.NewManager(cfg.CgroupRoot)
 	s := &service{
-		cfg:    cfg,
-		reg:    reg,
-		cancel: cancel,
+		cfg:     cfg,
+		reg:     reg,
+		sup:     newSupervisor(reg, lm, cm),
+		logs:    lm,
+		cgroups: cm,
+		cancel:  cancel,
+		samples: make(map[registry.ProcID]pidSample),
+		history: newMetricsHistory(cfg.MetricsHistory),
+		exits:   newExitTracker(),
+	}
+	s.sup.resume()
+	if cfg.LivenessBackend == config.LivenessPidfd {
+		if err := s.exits.notify(s.onPidfdExit); err != nil {
+			log.Printf("liveness: pidfd backend unavailable, polling every %s: %v", cfg.LivenessInterval, err)
+		}
 	}
 	go s.watchLiveness(ctx)
+	go s.watchLogs(ctx)
 	return s, nil
 }
 
+// openStore returns the registry store selected by cfg.
+func openStore(cfg config.Config) registry.Store {
+	if cfg.Store == config.StoreBolt {
+		return registry.NewBoltStore(registry.BoltStoreOptions{
+			Path:         StorePath(),
+			Sync:         registry.SyncMode(cfg.JournalSync),
+			SyncInterval: cfg.JournalSyncInterval,
+		})
+	}
+	return registry.NewJSONStore(registry.JSONStoreOptions{
+		SnapshotPath: SnapshotPath(),
+		JournalPath:  JournalPath(),
+		Sync:         registry.SyncMode(cfg.JournalSync),
+		SyncInterval: cfg.JournalSyncInterval,
+	})
+}
+
 func (s *service) Close() {
 	if s.cancel != nil {
 		s.cancel()
 	}
+	s.sup.close()
+	s.exits.close()
+}
+
+// closeRegistry flushes the registry journal into the snapshot. It runs
+// after the gRPC server has stopped, so no RPC changes the registry later.
+func (s *service) closeRegistry() {
+	if err := s.reg.Close(); err != nil {
+		log.Printf("close registry: %v", err)
+	}
 }
 
 func (s *service) Ping(ctx context.Context, _ *goprocv1.PingRequest) (*goprocv1.PingResponse, error) {
@@ -57,19 +146,99 @@ func (s *service) Add(ctx context.Context, req *goprocv1.AddRequest) (*goprocv1.
 	if err := syscall.Kill(pid, 0); err != nil {
 		return nil, status.Errorf(codes.NotFound, "pid %d not found or no permission: %v", pid, err)
 	}
+	annotations, err := registry.NormalizeAnnotations(req.GetAnnotations())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 
-	cmdLine := commandLine(pid)
-	id, existed, err := s.reg.AddByPID(pid, pgidOf(pid), cmdLine, req.GetName(), req.GetTags(), req.GetGroups())
+	id, existed, err := s.reg.Add(registry.AddParams{
+		PID:         pid,
+		PGID:        pgidOf(pid),
+		Cmd:         commandLine(pid),
+		Name:        req.GetName(),
+		Tags:        req.GetTags(),
+		Groups:      req.GetGroups(),
+		Annotations: annotations,
+		StartTicks:  startTicksOf(pid),
+		BootID:      currentBootID(),
+
+		TrackChildren: req.GetTrackChildren(),
+	})
+	if errors.Is(err, registry.ErrNameInUse) {
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "add failed: %v", err)
 	}
 	if existed {
 		return nil, status.Errorf(codes.AlreadyExists, "pid %d already registered as id %d", pid, id)
 	}
+	s.exits.track(id, pid)
 	return &goprocv1.AddResponse{Id: uint64(id)}, nil
 }
 
 func (s *service) List(ctx context.Context, req *goprocv1.ListRequest) (*goprocv1.ListResponse, error) {
+	filter, err := filterFromRequest(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	sortKey, err := registry.ParseSortKey(req.GetSort())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	project, err := fieldProjection(req.GetFields())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	page, err := s.reg.ListPage(filter, registry.PageRequest{
+		Sort:  sortKey,
+		Desc:  req.GetDesc(),
+		Limit: int(req.GetLimit()),
+		Token: req.GetPageToken(),
+	})
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	resp := &goprocv1.ListResponse{
+		Procs:         make([]*goprocv1.Proc, 0, len(page.Procs)),
+		Total:         uint32(page.Total),
+		NextPageToken: page.NextToken,
+	}
+	for i := range page.Procs {
+		resp.Procs = append(resp.Procs, project(procToProto(page.Procs[i])))
+	}
+	return resp, nil
+}
+
+// fieldProjection returns a function that clears every Proc field not named
+// in fields, so clients that only need a few columns do not receive full
+// entries. The id is always kept; no fields keeps everything.
+func fieldProjection(fields []string) (func(*goprocv1.Proc) *goprocv1.Proc, error) {
+	if len(fields) == 0 {
+		return func(p *goprocv1.Proc) *goprocv1.Proc { return p }, nil
+	}
+	desc := (&goprocv1.Proc{}).ProtoReflect().Descriptor().Fields()
+	keep := map[protoreflect.FieldNumber]bool{desc.ByName("id").Number(): true}
+	for _, name := range fields {
+		fd := desc.ByName(protoreflect.Name(strings.TrimSpace(name)))
+		if fd == nil {
+			return nil, fmt.Errorf("unknown process field %q", name)
+		}
+		keep[fd.Number()] = true
+	}
+	return func(p *goprocv1.Proc) *goprocv1.Proc {
+		m := p.ProtoReflect()
+		for i := 0; i < desc.Len(); i++ {
+			if fd := desc.Get(i); !keep[fd.Number()] {
+				m.Clear(fd)
+			}
+		}
+		return p
+	}, nil
+}
+
+// filterFromRequest converts the wire selector into a registry filter.
+func filterFromRequest(req *goprocv1.ListRequest) (registry.ListFilter, error) {
 	filter := registry.ListFilter{
 		TagsAny:    req.GetTagsAny(),
 		TagsAll:    req.GetTagsAll(),
@@ -91,70 +260,300 @@ func (s *service) List(ctx context.Context, req *goprocv1.ListRequest) (*goprocv
 			filter.PIDs = append(filter.PIDs, int(pid))
 		}
 	}
+	for _, sel := range req.GetAnnotations() {
+		op, err := registry.ParseAnnotationOp(sel.GetOp())
+		if err != nil {
+			return registry.ListFilter{}, err
+		}
+		key := strings.TrimSpace(sel.GetKey())
+		if key == "" {
+			return registry.ListFilter{}, errors.New("annotation selector key must not be empty")
+		}
+		filter.Annotations = append(filter.Annotations, registry.AnnotationSelector{Key: key, Op: op, Value: sel.GetValue()})
+	}
+	expr, err := query.Parse(req.GetQuery())
+	if err != nil {
+		return registry.ListFilter{}, err
+	}
+	filter.Query = expr
 
-	ps := s.reg.List(filter)
-	resp := &goprocv1.ListResponse{
-		Procs: make([]*goprocv1.Proc, 0, len(ps)),
-	}
-	for i := range ps {
-		p := ps[i]
-		resp.Procs = append(resp.Procs, &goprocv1.Proc{
-			Id:           uint64(p.ID),
-			Pid:          int32(p.PID),
-			Pgid:         int32(p.PGID),
-			Cmd:          p.Cmd,
-			Alive:        p.Alive,
-			Tags:         append([]string(nil), p.Meta.Tags...),
-			Groups:       append([]string(nil), p.Meta.Groups...),
-			AddedAtUnix:  p.AddedAt.Unix(),
-			LastSeenUnix: p.LastSeen.Unix(),
-			Name:         p.Name,
-		})
+	for _, pf := range []struct {
+		field string
+		globs []string
+		regex string
+		dst   *[]*regexp.Regexp
+	}{
+		{"name", req.GetNameGlobs(), req.GetNameRegex(), &filter.NamePatterns},
+		{"tag", req.GetTagGlobs(), req.GetTagRegex(), &filter.TagPatterns},
+		{"group", req.GetGroupGlobs(), req.GetGroupRegex(), &filter.GroupPatterns},
+		{"cmd", req.GetCmdGlobs(), req.GetCmdRegex(), &filter.CmdPatterns},
+	} {
+		patterns, err := pattern.Compile(pf.globs, pf.regex)
+		if err != nil {
+			return registry.ListFilter{}, fmt.Errorf("invalid %s %w", pf.field, err)
+		}
+		*pf.dst = append(*pf.dst, patterns...)
 	}
-	return resp, nil
+	return filter, nil
+}
+
+func (s *service) Spawn(ctx context.Context, req *goprocv1.SpawnRequest) (*goprocv1.SpawnResponse, error) {
+	params, err := spawnParamsFromProto(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	id, pid, err := s.sup.spawn(params)
+	if errors.Is(err, registry.ErrNameInUse) {
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "spawn failed: %v", err)
+	}
+	return &goprocv1.SpawnResponse{Id: uint64(id), Pid: int32(pid)}, nil
+}
+
+// spawnParamsFromProto validates a spawn request and converts it to the
+// registry parameters supervisor.spawn takes.
+func spawnParamsFromProto(req *goprocv1.SpawnRequest) (registry.AddParams, error) {
+	if len(req.GetArgv()) == 0 || strings.TrimSpace(req.GetArgv()[0]) == "" {
+		return registry.AddParams{}, errors.New("argv must not be empty")
+	}
+	policy, err := restartPolicyFromProto(req.GetRestart())
+	if err != nil {
+		return registry.AddParams{}, err
+	}
+	annotations, err := registry.NormalizeAnnotations(req.GetAnnotations())
+	if err != nil {
+		return registry.AddParams{}, err
+	}
+	var cg *registry.CgroupSpec
+	if c := req.GetCgroup(); c != nil {
+		cg = &registry.CgroupSpec{MemoryMax: c.GetMemoryMaxBytes(), CPUMax: c.GetCpuMax(), PIDsMax: int(c.GetPidsMax())}
+		if err := cgroupLimits(*cg).Validate(); err != nil {
+			return registry.AddParams{}, err
+		}
+	}
+	return registry.AddParams{
+		Name:        req.GetName(),
+		Tags:        req.GetTags(),
+		Groups:      req.GetGroups(),
+		Annotations: annotations,
+		Spawn: &registry.SpawnSpec{
+			Argv: append([]string(nil), req.GetArgv()...),
+			Env:  append([]string(nil), req.GetEnv()...),
+			Dir:  req.GetCwd(),
+		},
+		Restart:       &policy,
+		TrackChildren: req.GetTrackChildren(),
+		Cgroup:        cg,
+	}, nil
 }
 
 func (s *service) Kill(ctx context.Context, req *goprocv1.KillRequest) (*goprocv1.KillResponse, error) {
 	if req == nil || req.GetTarget() == nil {
 		return nil, status.Error(codes.InvalidArgument, "target is required")
 	}
+	sig := syscall.SIGTERM
+	if req.GetSignal() != "" {
+		var err error
+		if sig, err = signals.Parse(req.GetSignal()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if req.GetGraceMs() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "grace_ms must not be negative")
+	}
 
-	var pid, pgid int
+	var (
+		pid, pgid int
+		proc      registry.Proc
+		known     bool
+	)
 	switch t := req.GetTarget().(type) {
 	case *goprocv1.KillRequest_Id:
-		proc, ok := s.reg.Get(registry.ProcID(t.Id))
+		var ok bool
+		proc, ok = s.reg.Get(registry.ProcID(t.Id))
 		if !ok {
 			return nil, status.Error(codes.NotFound, "id not found")
 		}
+		known = true
+		if proc.Detached {
+			// Its PID and process group belong to another machine or run.
+			return nil, status.Errorf(codes.FailedPrecondition, "id %d was imported without its process; there is nothing to kill", proc.ID)
+		}
+		if pidReused(proc) {
+			// The PID now belongs to an unrelated process; never signal it.
+			s.reg.MarkExited(proc.ID, unknownExit(time.Now()))
+			return nil, status.Errorf(codes.FailedPrecondition, "pid %d of id %d was reused by another process; entry marked dead", proc.PID, proc.ID)
+		}
 		pid = proc.PID
 		pgid = proc.PGID
+		s.sup.stop(proc.ID)
 	case *goprocv1.KillRequest_Pid:
 		pid = int(t.Pid)
 		pgid = pgidOf(pid)
+		for _, p := range s.reg.List(registry.ListFilter{PIDs: []int{pid}}) {
+			if !p.Detached {
+				proc, known = p, true
+				s.sup.stop(proc.ID)
+				break
+			}
+		}
 	default:
 		return nil, status.Error(codes.InvalidArgument, "unsupported target")
 	}
 
-	target := pid
-	if pgid > 0 {
-		target = -pgid
+	target := killTarget{pid: pid}
+	if !req.GetNoGroup() {
+		target.pgid = pgid
+		if known && proc.Cgroup != nil && proc.PID == pid {
+			target.cgroup = proc.Cgroup.Path
+		}
 	}
-	if err := syscall.Kill(target, syscall.SIGTERM); err != nil {
+	grace := time.Duration(req.GetGraceMs()) * time.Millisecond
+	outcome, err := target.terminate(ctx, sig, grace, req.GetEscalate())
+	if err != nil {
+		if st := status.FromContextError(err); st.Code() != codes.Unknown {
+			return nil, st.Err()
+		}
 		return nil, status.Errorf(codes.Internal, "kill failed: %v", err)
 	}
-	return &goprocv1.KillResponse{}, nil
+	// Record the death now instead of on the next liveness probe, so the
+	// caller sees the exit status right away. Zombies are left until their
+	// parent reaps them, and the supervisor handles its own children.
+	if outcome != killRunning && known && !s.sup.supervised(proc.ID) && syscall.Kill(proc.PID, 0) != nil {
+		s.processExited(proc)
+	}
+	return &goprocv1.KillResponse{Outcome: outcome}, nil
+}
+
+func (s *service) Signal(ctx context.Context, req *goprocv1.SignalRequest) (*goprocv1.SignalResponse, error) {
+	if req.GetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "id must be provided")
+	}
+	sig, err := signals.Parse(req.GetSignal())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	proc, ok := s.reg.Get(registry.ProcID(req.GetId()))
+	if !ok {
+		return nil, status.Error(codes.NotFound, "id not found")
+	}
+	if !proc.Alive {
+		return nil, status.Errorf(codes.FailedPrecondition, "id %d is not alive", proc.ID)
+	}
+	if pidReused(proc) {
+		s.reg.MarkExited(proc.ID, unknownExit(time.Now()))
+		return nil, status.Errorf(codes.FailedPrecondition, "pid %d of id %d was reused by another process; entry marked dead", proc.PID, proc.ID)
+	}
+	target := killTarget{pid: proc.PID}
+	if req.GetProcessGroup() {
+		if proc.PGID <= 0 {
+			return nil, status.Errorf(codes.FailedPrecondition, "id %d has no known process group", proc.ID)
+		}
+		target.pgid = proc.PGID
+	}
+	if err := target.signal(sig); err != nil {
+		return nil, status.Errorf(codes.Internal, "signal failed: %v", err)
+	}
+	return &goprocv1.SignalResponse{}, nil
 }
 
 func (s *service) Rm(ctx context.Context, req *goprocv1.RmRequest) (*goprocv1.RmResponse, error) {
 	if req.GetId() == 0 {
 		return nil, status.Error(codes.InvalidArgument, "id must be provided")
 	}
-	if ok := s.reg.Remove(registry.ProcID(req.GetId())); !ok {
+	reason := registry.RemovalRm
+	switch req.GetReason() {
+	case "", string(registry.RemovalRm):
+	case string(registry.RemovalKill):
+		reason = registry.RemovalKill
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid removal reason %q (expected rm or kill)", req.GetReason())
+	}
+	proc, ok := s.reg.Get(registry.ProcID(req.GetId()))
+	if !ok || !s.remove(proc, reason) {
 		return nil, status.Error(codes.NotFound, "id not found")
 	}
 	return &goprocv1.RmResponse{}, nil
 }
 
+// remove deletes an entry together with its supervision state, logs and
+// cgroup, keeping its final state in the history. It reports false if the
+// entry was already gone.
+func (s *service) remove(p registry.Proc, reason registry.RemovalReason) bool {
+	if !s.reg.Remove(p.ID, reason) {
+		return false
+	}
+	s.release(p)
+	return true
+}
+
+// release drops the supervision state, samples, logs and cgroup of an entry
+// that left the registry.
+func (s *service) release(p registry.Proc) {
+	s.sup.forget(p.ID)
+	s.history.forget(p.ID)
+	s.exits.release(p.ID)
+	if err := s.logs.Remove(uint64(p.ID)); err != nil {
+		log.Printf("remove logs for id %d: %v", p.ID, err)
+	}
+	s.removeCgroup(p)
+}
+
+func (s *service) UpdateLabels(ctx context.Context, req *goprocv1.UpdateLabelsRequest) (*goprocv1.UpdateLabelsResponse, error) {
+	if len(req.GetAddTags())+len(req.GetRemoveTags())+len(req.GetAddGroups())+len(req.GetRemoveGroups()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no label changes requested")
+	}
+	resp := &goprocv1.UpdateLabelsResponse{}
+	change := registry.LabelChange{
+		AddTags:      req.GetAddTags(),
+		RemoveTags:   req.GetRemoveTags(),
+		AddGroups:    req.GetAddGroups(),
+		RemoveGroups: req.GetRemoveGroups(),
+	}
+	filter, err := filterFromRequest(req.GetFilter())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	for _, p := range s.reg.List(filter) {
+		changed, err := s.reg.UpdateLabels(p.ID, change)
+		if err != nil {
+			// Removed concurrently; it no longer matches anything.
+			continue
+		}
+		if changed {
+			resp.Updated++
+		}
+		if updated, ok := s.reg.Get(p.ID); ok {
+			resp.Procs = append(resp.Procs, procToProto(updated))
+		}
+	}
+	return resp, nil
+}
+
+func (s *service) SetName(ctx context.Context, req *goprocv1.SetNameRequest) (*goprocv1.SetNameResponse, error) {
+	if req.GetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "id must be provided")
+	}
+	id := registry.ProcID(req.GetId())
+	if _, ok := s.reg.Get(id); !ok {
+		return nil, status.Error(codes.NotFound, "id not found")
+	}
+	prev, err := s.reg.SetName(id, req.GetName())
+	switch {
+	case errors.Is(err, registry.ErrNameInUse):
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	proc, ok := s.reg.Get(id)
+	if !ok {
+		return nil, status.Error(codes.NotFound, "id not found")
+	}
+	return &goprocv1.SetNameResponse{Proc: procToProto(proc), PreviousName: prev}, nil
+}
+
 func (s *service) RenameTag(ctx context.Context, req *goprocv1.RenameTagRequest) (*goprocv1.RenameTagResponse, error) {
 	if req == nil {
 		return nil, status.Error(codes.InvalidArgument, "request required")
@@ -182,10 +581,283 @@ func (s *service) RenameGroup(ctx context.Context, req *goprocv1.RenameGroupRequ
 }
 
 func (s *service) Reset(ctx context.Context, _ *goprocv1.ResetRequest) (*goprocv1.ResetResponse, error) {
-	s.reg.Reset()
+	for _, p := range s.reg.Reset() {
+		s.release(p)
+	}
 	return &goprocv1.ResetResponse{}, nil
 }
 
+// removeCgroup deletes the cgroup leaf of a removed entry. A leaf that still
+// holds processes is left in place; they keep running, and their limits
+// keep applying, now untracked. The leaf of a detached entry is not ours.
+func (s *service) removeCgroup(p registry.Proc) {
+	if p.Cgroup == nil || p.Detached {
+		return
+	}
+	if err := cgroup.Remove(p.Cgroup.Path); err != nil && !errors.Is(err, syscall.EBUSY) {
+		log.Printf("remove cgroup for id %d: %v", p.ID, err)
+	}
+}
+
+func (s *service) Logs(req *goprocv1.LogsRequest, stream goprocv1.GoProc_LogsServer) error {
+	id := registry.ProcID(req.GetId())
+	if id == 0 {
+		return status.Error(codes.InvalidArgument, "id must be provided")
+	}
+	p, ok := s.reg.Get(id)
+	if !ok {
+		return status.Error(codes.NotFound, "id not found")
+	}
+	if p.Spawn == nil {
+		return status.Errorf(codes.FailedPrecondition, "no logs captured for id %d (only processes started with run are captured)", id)
+	}
+	which := logs.Stdout
+	if req.GetStderr() {
+		which = logs.Stderr
+	}
+	path := s.logs.Path(uint64(id), which)
+
+	data, offset, err := logs.Tail(path, int(req.GetTail()))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return status.Errorf(codes.Internal, "read logs: %v", err)
+	}
+	if err := sendLogData(stream, data); err != nil {
+		return err
+	}
+	if !req.GetFollow() {
+		return nil
+	}
+	err = logs.Follow(stream.Context(), path, offset, func(chunk []byte) error {
+		return sendLogData(stream, chunk)
+	})
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return nil
+	}
+	return err
+}
+
+func (s *service) Watch(req *goprocv1.WatchRequest, stream goprocv1.GoProc_WatchServer) error {
+	filter, err := filterFromRequest(req.GetFilter())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	sub := s.reg.Subscribe()
+	defer sub.Close()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-sub.C():
+			if !ok {
+				if err := sub.Err(); err != nil {
+					return status.Error(codes.ResourceExhausted, err.Error())
+				}
+				return nil
+			}
+			if !eventMatches(filter, ev) {
+				continue
+			}
+			if err := stream.Send(eventToProto(ev)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// eventMatches applies a selector to an event. Entries are matched on their
+// state before the transition where that matters, so an alive-only watcher
+// still learns about deaths and a name selector sees the rename away from it.
+func eventMatches(f registry.ListFilter, ev registry.Event) bool {
+	if ev.Type == registry.EventReset {
+		return true
+	}
+	p := ev.Proc
+	if ev.Type == registry.EventDied {
+		p.Alive = true
+	}
+	if f.Match(p) {
+		return true
+	}
+	if ev.Type == registry.EventRenamed {
+		p.Name = ev.PreviousName
+		return f.Match(p)
+	}
+	return false
+}
+
+func eventToProto(ev registry.Event) *goprocv1.WatchEvent {
+	out := &goprocv1.WatchEvent{
+		Type:         string(ev.Type),
+		AtUnixMs:     ev.At.UnixMilli(),
+		PreviousName: ev.PreviousName,
+	}
+	if ev.Type != registry.EventReset {
+		out.Proc = procToProto(ev.Proc)
+	}
+	return out
+}
+
+func (s *service) History(ctx context.Context, req *goprocv1.HistoryRequest) (*goprocv1.HistoryResponse, error) {
+	filter, err := filterFromRequest(req.GetFilter())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	hf := registry.HistoryFilter{ListFilter: filter, Limit: int(req.GetLimit())}
+	if ms := req.GetSinceUnixMs(); ms > 0 {
+		hf.Since = time.UnixMilli(ms)
+	}
+	for _, raw := range req.GetReasons() {
+		reason, err := registry.ParseRemovalReason(raw)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		hf.Reasons = append(hf.Reasons, reason)
+	}
+	ts, err := s.reg.History(hf)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "history failed: %v", err)
+	}
+	resp := &goprocv1.HistoryResponse{Entries: make([]*goprocv1.Tombstone, 0, len(ts))}
+	for _, t := range ts {
+		resp.Entries = append(resp.Entries, &goprocv1.Tombstone{
+			Seq:             t.Seq,
+			RemovedAtUnixMs: t.RemovedAt.UnixMilli(),
+			Reason:          string(t.Reason),
+			Proc:            procToProto(t.Proc),
+		})
+	}
+	return resp, nil
+}
+
+func (s *service) Stats(ctx context.Context, req *goprocv1.StatsRequest) (*goprocv1.StatsResponse, error) {
+	var cutoff time.Time
+	if w := req.GetWindowMs(); w > 0 {
+		cutoff = time.Now().Add(-time.Duration(w) * time.Millisecond)
+	}
+	filter, err := filterFromRequest(req.GetFilter())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	ps := s.reg.List(filter)
+	resp := &goprocv1.StatsResponse{Stats: make([]*goprocv1.ProcStats, 0, len(ps))}
+	for _, p := range ps {
+		points := s.history.since(p.ID, cutoff)
+		cpu := make([]float64, len(points))
+		rss := make([]float64, len(points))
+		samples := make([]*goprocv1.MetricSample, len(points))
+		for i, pt := range points {
+			cpu[i] = pt.CPU
+			rss[i] = float64(pt.RSS)
+			samples[i] = &goprocv1.MetricSample{AtUnixMs: pt.At.UnixMilli(), CpuPercent: pt.CPU, RssBytes: pt.RSS}
+		}
+		resp.Stats = append(resp.Stats, &goprocv1.ProcStats{
+			Proc:       procToProto(p),
+			CpuPercent: summaryToProto(summarize(cpu)),
+			RssBytes:   summaryToProto(summarize(rss)),
+			Samples:    samples,
+		})
+	}
+	return resp, nil
+}
+
+func summaryToProto(s summary) *goprocv1.MetricSummary {
+	return &goprocv1.MetricSummary{Min: s.Min, Avg: s.Avg, Max: s.Max, P95: s.P95}
+}
+
+func sendLogData(stream goprocv1.GoProc_LogsServer, data []byte) error {
+	const maxChunk = 64 * 1024
+	for len(data) > 0 {
+		n := min(len(data), maxChunk)
+		if err := stream.Send(&goprocv1.LogChunk{Data: data[:n]}); err != nil {
+			return err
+		}
+		data = data[n:]
+	}
+	return nil
+}
+
+func procToProto(p registry.Proc) *goprocv1.Proc {
+	out := &goprocv1.Proc{
+		Id:           uint64(p.ID),
+		Pid:          int32(p.PID),
+		Pgid:         int32(p.PGID),
+		Cmd:          p.Cmd,
+		Alive:        p.Alive,
+		Tags:         append([]string(nil), p.Meta.Tags...),
+		Groups:       append([]string(nil), p.Meta.Groups...),
+		AddedAtUnix:  p.AddedAt.Unix(),
+		LastSeenUnix: p.LastSeen.Unix(),
+		Name:         p.Name,
+		Restarts:     uint32(p.Restarts),
+		Annotations:  maps.Clone(p.Meta.Annotations),
+
+		ParentId:      uint64(p.ParentID),
+		TrackChildren: p.TrackChildren,
+		Children:      procNodesToProto(p.Children),
+	}
+	if p.Manifest != nil {
+		out.Manifest = p.Manifest.Name
+	}
+	if cg := p.Cgroup; cg != nil {
+		out.Cgroup = &goprocv1.Cgroup{
+			Path:           cg.Path,
+			MemoryMaxBytes: cg.MemoryMax,
+			CpuMax:         cg.CPUMax,
+			PidsMax:        uint32(cg.PIDsMax),
+		}
+	}
+	if e := p.Exit; e != nil {
+		out.Exit = &goprocv1.ExitStatus{
+			AtUnix:     e.At.Unix(),
+			Reason:     string(e.Reason),
+			Code:       int32(e.Code),
+			Signal:     e.Signal,
+			CoreDumped: e.CoreDumped,
+		}
+	}
+	if m := p.Metrics; m != nil {
+		out.Metrics = &goprocv1.ProcMetrics{
+			CpuPercent:    m.CPUPercent,
+			RssBytes:      m.RSS,
+			VmsBytes:      m.VMS,
+			Threads:       uint32(m.Threads),
+			OpenFds:       uint32(m.FDs),
+			ReadBytes:     m.ReadBytes,
+			WriteBytes:    m.WriteBytes,
+			SampledAtUnix: m.SampledAt.Unix(),
+		}
+	}
+	if p.Restart != nil {
+		out.Restart = &goprocv1.RestartPolicy{
+			Mode:         string(p.Restart.Mode),
+			MaxRestarts:  uint32(p.Restart.MaxRestarts),
+			WindowMs:     p.Restart.Window.Milliseconds(),
+			BackoffMs:    p.Restart.Backoff.Milliseconds(),
+			MaxBackoffMs: p.Restart.MaxBackoff.Milliseconds(),
+		}
+	}
+	return out
+}
+
+func restartPolicyFromProto(p *goprocv1.RestartPolicy) (registry.RestartPolicy, error) {
+	mode, err := registry.ParseRestartMode(p.GetMode())
+	if err != nil {
+		return registry.RestartPolicy{}, err
+	}
+	if p.GetWindowMs() < 0 || p.GetBackoffMs() < 0 || p.GetMaxBackoffMs() < 0 {
+		return registry.RestartPolicy{}, errors.New("restart durations must not be negative")
+	}
+	return registry.RestartPolicy{
+		Mode:        mode,
+		MaxRestarts: int(p.GetMaxRestarts()),
+		Window:      time.Duration(p.GetWindowMs()) * time.Millisecond,
+		Backoff:     time.Duration(p.GetBackoffMs()) * time.Millisecond,
+		MaxBackoff:  time.Duration(p.GetMaxBackoffMs()) * time.Millisecond,
+	}, nil
+}
+
 func pgidOf(pid int) int {
 	pgid, err := syscall.Getpgid(pid)
 	if err != nil {
@@ -199,6 +871,9 @@ func (s *service) watchLiveness(ctx context.Context) {
 	if interval <= 0 {
 		interval = 10 * time.Second
 	}
+	// Probe once up front so restored entries get pidfds (and are marked
+	// dead if they exited while the daemon was down) without waiting a tick.
+	s.refreshLiveness()
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 	for {
@@ -207,14 +882,120 @@ func (s *service) watchLiveness(ctx context.Context) {
 			return
 		case <-ticker.C:
 			s.refreshLiveness()
+			s.reg.PruneHistory()
+		}
+	}
+}
+
+func (s *service) watchLogs(ctx context.Context) {
+	ticker := time.NewTicker(logRotateInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, p := range s.reg.List(registry.ListFilter{}) {
+				if p.Spawn == nil {
+					continue
+				}
+				if err := s.logs.Rotate(uint64(p.ID)); err != nil {
+					log.Printf("rotate logs for id %d: %v", p.ID, err)
+				}
+			}
 		}
 	}
 }
 
 func (s *service) refreshLiveness() {
 	procs := s.reg.List(registry.ListFilter{})
+	seen := make(map[registry.ProcID]struct{}, len(procs))
 	for _, p := range procs {
-		err := syscall.Kill(p.PID, 0)
-		s.reg.SetAlive(p.ID, err == nil)
+		seen[p.ID] = struct{}{}
+		if isSameProcess(p) {
+			s.reg.SetAlive(p.ID, true)
+			if p.StartTicks == 0 {
+				s.reg.SetIdentity(p.ID, startTicksOf(p.PID), currentBootID())
+			}
+			if !s.sup.supervised(p.ID) {
+				s.exits.track(p.ID, p.PID)
+			}
+			s.sampleMetrics(p.ID, p.PID)
+			continue
+		}
+		delete(s.samples, p.ID)
+		// Children of the daemon are reaped by the supervisor, which records
+		// their wait status itself.
+		if !p.Alive || s.sup.supervised(p.ID) {
+			continue
+		}
+		s.processExited(p)
+	}
+	for id := range s.samples {
+		if _, ok := seen[id]; !ok {
+			delete(s.samples, id)
+		}
+	}
+	s.history.prune(seen)
+	s.exits.prune(seen)
+	s.refreshTrees()
+}
+
+// processExited records the death of an unsupervised entry. Both the
+// liveness tick and pidfd notifications may report the same death; only the
+// first to mark the entry dead hands it to the restart policy.
+func (s *service) processExited(p registry.Proc) {
+	now := time.Now()
+	st, ok := s.exits.exited(p.ID, now)
+	if !ok {
+		st = unknownExit(now)
+	}
+	if s.reg.MarkExited(p.ID, st) && p.Spawn != nil {
+		s.sup.adoptedExited(p.ID, st)
+	}
+}
+
+// onPidfdExit handles an epoll notification that a tracked process exited.
+// The pidfd fires as soon as the process becomes a zombie, but its exit
+// status is only available once the parent reaps it, so wait briefly for
+// that. Zombies that outlive the wait are left to the liveness tick, which
+// treats them as alive just like the polling backend does.
+func (s *service) onPidfdExit(id registry.ProcID) {
+	p, ok := s.reg.Get(id)
+	if !ok || !p.Alive || s.sup.supervised(id) {
+		return
+	}
+	for deadline := time.Now().Add(reapWait); syscall.Kill(p.PID, 0) == nil; {
+		if time.Now().After(deadline) {
+			return
+		}
+		time.Sleep(reapWaitStep)
+	}
+	s.processExited(p)
+}
+
+// sampleMetrics reads /proc for one live entry and stores the result. CPU
+// usage needs two readings, so the first sample of a process reports 0%.
+func (s *service) sampleMetrics(id registry.ProcID, pid int) {
+	cur, err := procfs.Read(pid)
+	if err != nil {
+		delete(s.samples, id)
+		s.reg.SetMetrics(id, nil)
+		return
+	}
+	m := &registry.Metrics{
+		RSS:        cur.RSS,
+		VMS:        cur.VMS,
+		Threads:    cur.Threads,
+		FDs:        cur.FDs,
+		ReadBytes:  cur.ReadBytes,
+		WriteBytes: cur.WriteBytes,
+		SampledAt:  cur.At,
+	}
+	if prev, ok := s.samples[id]; ok && prev.pid == pid && prev.StartTicks == cur.StartTicks {
+		m.CPUPercent = procfs.CPUPercent(prev.Sample, cur)
 	}
+	s.samples[id] = pidSample{pid: pid, Sample: cur}
+	s.reg.SetMetrics(id, m)
+	s.history.add(id, metricPoint{At: cur.At, CPU: m.CPUPercent, RSS: m.RSS})
 }
@@ -0,0 +1,104 @@
+package daemon
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	goprocv1 "goproc/api/proto/goproc/v1"
+	"goproc/internal/config"
+	"goproc/internal/registry"
+)
+
+// newTestService starts a service whose files live in a temporary
+// directory. configure, if set, adjusts the default configuration.
+func newTestService(t *testing.T, configure func(*config.Config)) *service {
+	t.Helper()
+	t.Setenv("GOPROC_SOCKET", filepath.Join(t.TempDir(), SocketBaseName))
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if configure != nil {
+		configure(&cfg)
+	}
+	s, err := newService(cfg)
+	if err != nil {
+		t.Fatalf("newService: %v", err)
+	}
+	t.Cleanup(func() {
+		s.Close()
+		s.closeRegistry()
+	})
+	return s
+}
+
+func TestResetForgetsSupervisedChildren(t *testing.T) {
+	s := newTestService(t, nil)
+	// The child exits quickly and would be restarted right away, so the
+	// reset lands either while it runs or while a restart is pending.
+	resp, err := s.Spawn(context.Background(), &goprocv1.SpawnRequest{
+		Argv:    []string{"sleep", "0.1"},
+		Restart: &goprocv1.RestartPolicy{Mode: "always", BackoffMs: 10},
+	})
+	if err != nil {
+		t.Fatalf("Spawn: %v", err)
+	}
+	if _, err := s.Reset(context.Background(), &goprocv1.ResetRequest{}); err != nil {
+		t.Fatalf("Reset: %v", err)
+	}
+
+	other := exec.Command("sleep", "30")
+	if err := other.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = other.Process.Kill()
+		_ = other.Wait()
+	}()
+	id, _, err := s.reg.Add(registry.AddParams{PID: other.Process.Pid, Cmd: "sleep 30"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+	p, ok := s.reg.Get(id)
+	if !ok || !p.Alive || p.Exit != nil || p.PID != other.Process.Pid {
+		t.Fatalf("entry %d (the spawned entry was %d) was touched after reset: %+v", id, resp.GetId(), p)
+	}
+	if s.sup.supervised(id) {
+		t.Fatalf("entry %d is supervised", id)
+	}
+}
+
+func TestRestartClearsExit(t *testing.T) {
+	s := newTestService(t, nil)
+	// The first run fails; the restarted one keeps running.
+	marker := filepath.Join(t.TempDir(), "ran")
+	resp, err := s.Spawn(context.Background(), &goprocv1.SpawnRequest{
+		Argv:    []string{"sh", "-c", `test -e "$0" && exec sleep 30; touch "$0"; exit 3`, marker},
+		Restart: &goprocv1.RestartPolicy{Mode: "always", BackoffMs: 10},
+	})
+	if err != nil {
+		t.Fatalf("Spawn: %v", err)
+	}
+	id := registry.ProcID(resp.GetId())
+	defer s.Kill(context.Background(), &goprocv1.KillRequest{Target: &goprocv1.KillRequest_Id{Id: uint64(id)}, GraceMs: 2000, Escalate: true})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		p, ok := s.reg.Get(id)
+		if ok && p.Alive && p.Restarts == 1 {
+			if p.Exit != nil {
+				t.Fatalf("restarted entry still has the previous exit: %+v", p.Exit)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("entry was not restarted: %+v", p)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,417 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"goproc/internal/cgroup"
+	"goproc/internal/logs"
+	"goproc/internal/registry"
+)
+
+const (
+	defaultRestartWindow = time.Minute
+	defaultBackoff       = time.Second
+	defaultMaxBackoff    = time.Minute
+)
+
+// supervisor owns processes started by the daemon: it waits on them and
+// relaunches them according to their restart policy, keeping the registry ID.
+type supervisor struct {
+	reg     *registry.Registry
+	logs    *logs.Manager
+	cgroups *cgroup.Manager
+
+	mu       sync.Mutex
+	children map[registry.ProcID]*child
+	closed   bool
+}
+
+// child tracks one supervised registry entry across restarts.
+type child struct {
+	cmd      *exec.Cmd
+	stopping bool
+	restarts []time.Time // restart timestamps inside the policy window
+	timer    *time.Timer // pending delayed restart, if any
+}
+
+func newSupervisor(reg *registry.Registry, lm *logs.Manager, cm *cgroup.Manager) *supervisor {
+	return &supervisor{
+		reg:      reg,
+		logs:     lm,
+		cgroups:  cm,
+		children: make(map[registry.ProcID]*child),
+	}
+}
+
+// spawn starts the process described by params.Spawn and registers it with
+// the labels, policy and options in params; the process identity fields are
+// filled in here. The registry entry carries the spec and policy so restarts
+// (including after a daemon restart) reuse the ID. A non-nil params.Cgroup
+// runs the process in its own cgroup leaf with those limits.
+func (s *supervisor) spawn(params registry.AddParams) (registry.ProcID, int, error) {
+	if _, err := s.reg.CheckName(params.Name); err != nil {
+		return 0, 0, err
+	}
+	var leaf *cgroup.Leaf
+	if params.Cgroup != nil {
+		var err error
+		if leaf, err = s.cgroups.Create(cgroupLimits(*params.Cgroup)); err != nil {
+			return 0, 0, err
+		}
+		cg := *params.Cgroup
+		cg.Path = leaf.Path()
+		params.Cgroup = &cg
+	}
+	stdout, stderr, err := s.logs.OpenPending()
+	if err != nil {
+		s.discardLeaf(leaf)
+		return 0, 0, fmt.Errorf("open log files: %w", err)
+	}
+	cmd, err := startSpec(*params.Spawn, stdout, stderr, leaf)
+	if err != nil {
+		s.logs.Discard(stdout, stderr)
+		s.discardLeaf(leaf)
+		return 0, 0, err
+	}
+	pid := cmd.Process.Pid
+	params.PID = pid
+	params.PGID = pgidOf(pid)
+	params.Cmd = strings.Join(params.Spawn.Argv, " ")
+	params.StartTicks = startTicksOf(pid)
+	params.BootID = currentBootID()
+	id, existed, err := s.reg.Add(params)
+	if err == nil && existed {
+		err = fmt.Errorf("pid %d already registered as id %d", pid, id)
+	}
+	if err != nil {
+		if leaf != nil {
+			_ = cgroup.Kill(leaf.Path())
+		} else {
+			_ = cmd.Process.Kill()
+		}
+		_ = cmd.Wait()
+		s.logs.Discard(stdout, stderr)
+		s.discardLeaf(leaf)
+		return 0, 0, err
+	}
+	if err := s.logs.Adopt(uint64(id), stdout, stderr); err != nil {
+		log.Printf("supervisor: keep logs for id %d: %v", id, err)
+	}
+	stdout.Close()
+	stderr.Close()
+	if leaf != nil {
+		leaf.Close()
+	}
+
+	s.mu.Lock()
+	s.children[id] = &child{cmd: cmd}
+	s.mu.Unlock()
+	go s.wait(id, cmd)
+	return id, pid, nil
+}
+
+// resume relaunches supervised entries whose process died while the daemon was down.
+func (s *supervisor) resume() {
+	for _, p := range s.reg.List(registry.ListFilter{}) {
+		// Detached entries were imported without their process; only
+		// import --respawn starts them.
+		if p.Spawn == nil || p.Detached || p.Restart == nil || p.Restart.Mode == registry.RestartNever {
+			continue
+		}
+		if isSameProcess(p) {
+			// Still running but no longer our child; liveness probing reports its exit.
+			continue
+		}
+		s.reg.MarkExited(p.ID, unknownExit(time.Now()))
+		s.mu.Lock()
+		c := &child{}
+		s.children[p.ID] = c
+		s.scheduleLocked(p.ID, c, 0)
+		s.mu.Unlock()
+	}
+}
+
+// adoptedExited handles the death of a supervised entry that is not our child
+// (it was started by a previous daemon instance). st comes from the exit
+// tracker; an unknown status counts as a failure.
+func (s *supervisor) adoptedExited(id registry.ProcID, st registry.ExitStatus) {
+	s.mu.Lock()
+	if _, ok := s.children[id]; ok {
+		s.mu.Unlock()
+		return
+	}
+	c := &child{}
+	s.children[id] = c
+	s.mu.Unlock()
+	s.exited(id, c, st)
+}
+
+// stop disables restarts for an entry, typically right before it is killed.
+func (s *supervisor) stop(id registry.ProcID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.children[id]; ok {
+		c.stopping = true
+		if c.timer != nil {
+			c.timer.Stop()
+			c.timer = nil
+		}
+	}
+}
+
+// forget drops supervision for an entry that left the registry. A child
+// that is still running keeps being reaped by its wait goroutine, which no
+// longer finds it and reports nothing, so its exit cannot reach an entry
+// that later takes the same ID.
+func (s *supervisor) forget(id registry.ProcID) {
+	s.stop(id)
+	s.mu.Lock()
+	delete(s.children, id)
+	s.mu.Unlock()
+}
+
+// close cancels pending restarts. Running children are left alone so they
+// outlive the daemon; the next instance picks them up from the snapshot.
+func (s *supervisor) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for _, c := range s.children {
+		if c.timer != nil {
+			c.timer.Stop()
+			c.timer = nil
+		}
+	}
+}
+
+// supervised reports whether the daemon currently owns the entry's process.
+func (s *supervisor) supervised(id registry.ProcID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.children[id]
+	return ok && c.cmd != nil
+}
+
+func (s *supervisor) wait(id registry.ProcID, cmd *exec.Cmd) {
+	_ = cmd.Wait()
+	st := unknownExit(time.Now())
+	if ws, ok := cmd.ProcessState.Sys().(syscall.WaitStatus); ok {
+		st = exitStatusFromWait(ws, time.Now())
+	}
+
+	s.mu.Lock()
+	c, ok := s.children[id]
+	if !ok || c.cmd != cmd {
+		s.mu.Unlock()
+		return
+	}
+	c.cmd = nil
+	s.mu.Unlock()
+
+	s.exited(id, c, st)
+}
+
+func (s *supervisor) exited(id registry.ProcID, c *child, st registry.ExitStatus) {
+	s.reg.MarkExited(id, st)
+
+	p, ok := s.reg.Get(id)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !ok || s.closed || c.stopping || p.Restart == nil || p.Spawn == nil {
+		delete(s.children, id)
+		return
+	}
+	policy := withPolicyDefaults(*p.Restart)
+	switch policy.Mode {
+	case registry.RestartAlways:
+	case registry.RestartOnFailure:
+		if st.Success() {
+			delete(s.children, id)
+			return
+		}
+	default:
+		delete(s.children, id)
+		return
+	}
+
+	cutoff := time.Now().Add(-policy.Window)
+	recent := c.restarts[:0]
+	for _, at := range c.restarts {
+		if at.After(cutoff) {
+			recent = append(recent, at)
+		}
+	}
+	c.restarts = recent
+	if policy.MaxRestarts > 0 && len(c.restarts) >= policy.MaxRestarts {
+		log.Printf("supervisor: id %d exceeded %d restarts within %s; giving up", id, policy.MaxRestarts, policy.Window)
+		delete(s.children, id)
+		return
+	}
+	s.scheduleLocked(id, c, backoffFor(policy, len(c.restarts)))
+}
+
+// scheduleLocked arranges a restart after delay. Caller holds s.mu.
+func (s *supervisor) scheduleLocked(id registry.ProcID, c *child, delay time.Duration) {
+	c.timer = time.AfterFunc(delay, func() { s.restart(id, c) })
+}
+
+func (s *supervisor) restart(id registry.ProcID, c *child) {
+	s.mu.Lock()
+	c.timer = nil
+	if s.closed || c.stopping || s.children[id] != c {
+		s.mu.Unlock()
+		return
+	}
+	s.mu.Unlock()
+
+	p, ok := s.reg.Get(id)
+	if !ok || p.Spawn == nil {
+		s.forget(id)
+		return
+	}
+	cmd, err := s.startLogged(id, *p.Spawn, p.Cgroup)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c.restarts = append(c.restarts, time.Now())
+	if err != nil {
+		log.Printf("supervisor: restart of id %d failed: %v", id, err)
+		policy := withPolicyDefaults(*p.Restart)
+		if policy.MaxRestarts > 0 && len(c.restarts) >= policy.MaxRestarts {
+			delete(s.children, id)
+			return
+		}
+		s.scheduleLocked(id, c, backoffFor(policy, len(c.restarts)))
+		return
+	}
+	pid := cmd.Process.Pid
+	if err := s.reg.Respawned(id, pid, pgidOf(pid), startTicksOf(pid), currentBootID()); err != nil {
+		log.Printf("supervisor: id %d respawned as pid %d but registry update failed: %v", id, pid, err)
+		_ = cmd.Process.Kill()
+		go func() { _ = cmd.Wait() }()
+		delete(s.children, id)
+		return
+	}
+	c.cmd = cmd
+	go s.wait(id, cmd)
+}
+
+// relaunch starts the spawn spec of an entry whose process is not running,
+// such as an imported one, under its existing ID. Its restart policy applies
+// from then on.
+func (s *supervisor) relaunch(id registry.ProcID) (int, error) {
+	p, ok := s.reg.Get(id)
+	if !ok {
+		return 0, fmt.Errorf("id %d not found", id)
+	}
+	if p.Spawn == nil {
+		return 0, fmt.Errorf("id %d was not started by the daemon and cannot be respawned", id)
+	}
+	cmd, err := s.startLogged(id, *p.Spawn, p.Cgroup)
+	if err != nil {
+		return 0, err
+	}
+	pid := cmd.Process.Pid
+	if err := s.reg.Respawned(id, pid, pgidOf(pid), startTicksOf(pid), currentBootID()); err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return 0, err
+	}
+	s.mu.Lock()
+	s.children[id] = &child{cmd: cmd}
+	s.mu.Unlock()
+	go s.wait(id, cmd)
+	return pid, nil
+}
+
+// startLogged relaunches an existing entry, appending to its log files and
+// reusing its cgroup leaf, if any.
+func (s *supervisor) startLogged(id registry.ProcID, spec registry.SpawnSpec, cg *registry.CgroupSpec) (*exec.Cmd, error) {
+	var leaf *cgroup.Leaf
+	if cg != nil {
+		var err error
+		if leaf, err = s.cgroups.Open(cg.Path, cgroupLimits(*cg)); err != nil {
+			return nil, err
+		}
+		defer leaf.Close()
+	}
+	stdout, stderr, err := s.logs.Open(uint64(id))
+	if err != nil {
+		return nil, fmt.Errorf("open log files: %w", err)
+	}
+	defer stdout.Close()
+	defer stderr.Close()
+	return startSpec(spec, stdout, stderr, leaf)
+}
+
+func (s *supervisor) discardLeaf(leaf *cgroup.Leaf) {
+	if leaf != nil {
+		s.cgroups.Discard(leaf)
+	}
+}
+
+func cgroupLimits(cg registry.CgroupSpec) cgroup.Limits {
+	return cgroup.Limits{MemoryMax: cg.MemoryMax, CPUMax: cg.CPUMax, PIDsMax: cg.PIDsMax}
+}
+
+// startSpec launches spec in its own process group with stdout/stderr
+// redirected to the given files; stdin is /dev/null. A non-nil leaf is the
+// cgroup the process starts in.
+func startSpec(spec registry.SpawnSpec, stdout, stderr *os.File, leaf *cgroup.Leaf) (*exec.Cmd, error) {
+	if len(spec.Argv) == 0 {
+		return nil, errors.New("argv must not be empty")
+	}
+	cmd := exec.Command(spec.Argv[0], spec.Argv[1:]...)
+	cmd.Dir = spec.Dir
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if len(spec.Env) > 0 {
+		cmd.Env = append([]string(nil), spec.Env...)
+	}
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if leaf != nil {
+		if err := leaf.Attach(cmd.SysProcAttr); err != nil {
+			return nil, err
+		}
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start %s: %w", spec.Argv[0], err)
+	}
+	return cmd, nil
+}
+
+func withPolicyDefaults(p registry.RestartPolicy) registry.RestartPolicy {
+	if p.Window <= 0 {
+		p.Window = defaultRestartWindow
+	}
+	if p.Backoff <= 0 {
+		p.Backoff = defaultBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaultMaxBackoff
+	}
+	if p.MaxBackoff < p.Backoff {
+		p.MaxBackoff = p.Backoff
+	}
+	return p
+}
+
+// backoffFor doubles the initial delay for every recent restart, capped at MaxBackoff.
+func backoffFor(p registry.RestartPolicy, recent int) time.Duration {
+	delay := p.Backoff
+	for i := 0; i < recent && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	return delay
+}
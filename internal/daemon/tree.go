@@ -0,0 +1,131 @@
+package daemon
+
+import (
+	"fmt"
+
+	goprocv1 "goproc/api/proto/goproc/v1"
+	"goproc/internal/procfs"
+	"goproc/internal/registry"
+)
+
+// maxTreeDepth bounds the descent below an entry. PID reuse during a scan
+// could in theory link processes into a cycle; real trees are far shallower.
+const maxTreeDepth = 64
+
+// refreshTrees rebuilds the process tree below every live entry from the
+// ppid links in /proc, registering newly discovered descendants of entries
+// with TrackChildren as child entries. Child entries are dropped once their
+// process is gone or their parent entry was removed: forking servers replace
+// workers routinely, and keeping every retired worker would bury the
+// registry. Without /proc (non-Linux) trees stay empty.
+func (s *service) refreshTrees() {
+	children, err := procfs.Children()
+	if err != nil {
+		return
+	}
+	procs := s.reg.List(registry.ListFilter{})
+	present := make(map[registry.ProcID]struct{}, len(procs))
+	byPID := make(map[int]registry.ProcID, len(procs))
+	for _, p := range procs {
+		present[p.ID] = struct{}{}
+		if p.Alive {
+			byPID[p.PID] = p.ID
+		}
+	}
+	// Entries are listed by ascending ID and child entries are always
+	// registered after their parent, so a removal cascades in one pass.
+	for _, p := range procs {
+		if p.ParentID == 0 {
+			continue
+		}
+		if _, ok := present[p.ParentID]; p.Alive && ok {
+			continue
+		}
+		if s.reg.Remove(p.ID, registry.RemovalGC) {
+			delete(present, p.ID)
+			if byPID[p.PID] == p.ID {
+				delete(byPID, p.PID)
+			}
+		}
+	}
+	for _, p := range procs {
+		if _, ok := present[p.ID]; !ok || !p.Alive {
+			continue
+		}
+		t := treeBuilder{s: s, children: children, byPID: byPID, track: p.TrackChildren}
+		s.reg.SetChildren(p.ID, t.build(p.PID, p.ID, 0))
+	}
+}
+
+type treeBuilder struct {
+	s        *service
+	children map[int][]int
+	byPID    map[int]registry.ProcID
+	track    bool
+}
+
+// build returns the descendants of pid. parent is the entry that newly
+// registered children are linked to.
+func (t *treeBuilder) build(pid int, parent registry.ProcID, depth int) []registry.ProcNode {
+	kids := t.children[pid]
+	if len(kids) == 0 || depth >= maxTreeDepth {
+		return nil
+	}
+	nodes := make([]registry.ProcNode, 0, len(kids))
+	for _, kid := range kids {
+		node := registry.ProcNode{PID: kid, Cmd: treeCommand(kid), ID: t.byPID[kid]}
+		if node.ID == 0 && t.track {
+			node.ID = t.register(kid, node.Cmd, parent)
+		}
+		next := parent
+		if node.ID != 0 {
+			next = node.ID
+		}
+		node.Children = t.build(kid, next, depth+1)
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+func (t *treeBuilder) register(pid int, cmd string, parent registry.ProcID) registry.ProcID {
+	id, existed, err := t.s.reg.Add(registry.AddParams{
+		PID:           pid,
+		PGID:          pgidOf(pid),
+		Cmd:           cmd,
+		StartTicks:    startTicksOf(pid),
+		BootID:        currentBootID(),
+		ParentID:      parent,
+		TrackChildren: true,
+	})
+	if err != nil || existed {
+		return id
+	}
+	t.byPID[pid] = id
+	t.s.exits.track(id, pid)
+	return id
+}
+
+// treeCommand reads the command line without the ps fallback of commandLine:
+// it runs for every descendant on every tick.
+func treeCommand(pid int) string {
+	if cmd, err := readProcCmdline(pid); err == nil && cmd != "" {
+		return cmd
+	}
+	return fmt.Sprintf("pid:%d", pid)
+}
+
+func procNodesToProto(nodes []registry.ProcNode) []*goprocv1.ProcNode {
+	if len(nodes) == 0 {
+		return nil
+	}
+	out := make([]*goprocv1.ProcNode, 0, len(nodes))
+	for _, n := range nodes {
+		out = append(out, &goprocv1.ProcNode{
+			Pid:      int32(n.PID),
+			Cmd:      n.Cmd,
+			Id:       uint64(n.ID),
+			Children: procNodesToProto(n.Children),
+		})
+	}
+	return out
+}
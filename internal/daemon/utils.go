@@ -20,6 +20,8 @@ const SocketBaseName = "goproc.sock"
 
 const pidFileName = "goproc.pid"
 const snapshotFileName = "goproc.snapshot.json"
+const journalFileName = "goproc.journal"
+const storeFileName = "goproc.db"
 
 // SocketPath returns the full path to the UNIX socket
 // Order of precedence (first wins):
@@ -71,6 +73,22 @@ func SnapshotPath() string {
 	return filepath.Join(filepath.Dir(SocketPath()), snapshotFileName)
 }
 
+// JournalPath returns the path of the registry journal, which records the
+// changes made since the snapshot was last written.
+func JournalPath() string {
+	return filepath.Join(filepath.Dir(SocketPath()), journalFileName)
+}
+
+// StorePath returns the path of the registry database used by the bolt store.
+func StorePath() string {
+	return filepath.Join(filepath.Dir(SocketPath()), storeFileName)
+}
+
+// LogDir returns the directory holding captured output of spawned processes.
+func LogDir() string {
+	return filepath.Dir(SocketPath())
+}
+
 // WritePID stores the provided pid into the pid file
 func WritePID(pid int) error {
 	if err := EnsureRuntimeDir(); err != nil {
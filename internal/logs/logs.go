@@ -0,0 +1,304 @@
+// Package logs stores stdout/stderr of daemon-spawned processes in per-ID
+// files and keeps them under a size budget.
+//
+// Children write to their files directly (O_APPEND), so output is not lost
+// when the daemon restarts. Rotation therefore uses copy-and-truncate: the
+// live file is copied to "<name>.1" (older copies shift up) and truncated in
+// place while the child keeps its descriptor.
+package logs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Stream selects one of the captured output streams.
+type Stream string
+
+const (
+	Stdout Stream = "stdout"
+	Stderr Stream = "stderr"
+)
+
+const (
+	filePrefix     = "goproc."
+	followInterval = 250 * time.Millisecond
+	readChunkSize  = 32 * 1024
+)
+
+// Manager creates, rotates, and removes log files inside a directory.
+type Manager struct {
+	dir      string
+	maxSize  int64
+	maxFiles int
+
+	mu sync.Mutex // serializes rotations
+}
+
+// NewManager returns a Manager writing into dir. maxSize <= 0 disables
+// rotation; maxFiles is the number of rotated copies retained.
+func NewManager(dir string, maxSize int64, maxFiles int) *Manager {
+	if maxFiles < 0 {
+		maxFiles = 0
+	}
+	return &Manager{dir: dir, maxSize: maxSize, maxFiles: maxFiles}
+}
+
+// Path returns the live log file for a registry ID and stream.
+func (m *Manager) Path(id uint64, stream Stream) string {
+	return filepath.Join(m.dir, fmt.Sprintf("%s%d.%s.log", filePrefix, id, stream))
+}
+
+// OpenPending opens a pair of log files for a process whose registry ID is
+// not known yet. Call Adopt once the ID is assigned.
+func (m *Manager) OpenPending() (stdout, stderr *os.File, err error) {
+	if err := os.MkdirAll(m.dir, 0o700); err != nil {
+		return nil, nil, err
+	}
+	stdout, err = createAppend(m.dir, filePrefix+"pending-*.stdout.log")
+	if err != nil {
+		return nil, nil, err
+	}
+	stderr, err = createAppend(m.dir, filePrefix+"pending-*.stderr.log")
+	if err != nil {
+		m.Discard(stdout)
+		return nil, nil, err
+	}
+	return stdout, stderr, nil
+}
+
+// Adopt renames pending files to their final per-ID location. The child keeps
+// writing to the same inode, so this is safe while it runs.
+func (m *Manager) Adopt(id uint64, stdout, stderr *os.File) error {
+	return errors.Join(
+		os.Rename(stdout.Name(), m.Path(id, Stdout)),
+		os.Rename(stderr.Name(), m.Path(id, Stderr)),
+	)
+}
+
+// Discard removes pending files that were never adopted.
+func (m *Manager) Discard(files ...*os.File) {
+	for _, f := range files {
+		if f == nil {
+			continue
+		}
+		f.Close()
+		os.Remove(f.Name())
+	}
+}
+
+// Open opens (appending) the live files for an existing registry ID.
+func (m *Manager) Open(id uint64) (stdout, stderr *os.File, err error) {
+	if err := os.MkdirAll(m.dir, 0o700); err != nil {
+		return nil, nil, err
+	}
+	stdout, err = openAppend(m.Path(id, Stdout))
+	if err != nil {
+		return nil, nil, err
+	}
+	stderr, err = openAppend(m.Path(id, Stderr))
+	if err != nil {
+		stdout.Close()
+		return nil, nil, err
+	}
+	return stdout, stderr, nil
+}
+
+// Rotate rotates both streams of id if they exceed the size budget.
+func (m *Manager) Rotate(id uint64) error {
+	if m.maxSize <= 0 {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return errors.Join(
+		m.rotateLocked(m.Path(id, Stdout)),
+		m.rotateLocked(m.Path(id, Stderr)),
+	)
+}
+
+func (m *Manager) rotateLocked(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if info.Size() < m.maxSize {
+		return nil
+	}
+	if m.maxFiles == 0 {
+		return os.Truncate(path, 0)
+	}
+	os.Remove(rotatedPath(path, m.maxFiles))
+	for i := m.maxFiles - 1; i >= 1; i-- {
+		if err := os.Rename(rotatedPath(path, i), rotatedPath(path, i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	if err := copyFile(path, rotatedPath(path, 1)); err != nil {
+		return err
+	}
+	return os.Truncate(path, 0)
+}
+
+// Remove deletes the live and rotated files of id.
+func (m *Manager) Remove(id uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var joined error
+	for _, stream := range []Stream{Stdout, Stderr} {
+		matches, _ := filepath.Glob(m.Path(id, stream) + "*")
+		for _, path := range matches {
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				joined = errors.Join(joined, err)
+			}
+		}
+	}
+	return joined
+}
+
+// Tail returns the last n lines of path (the whole file when n <= 0) and the
+// offset just past the returned data.
+func Tail(path string, n int) ([]byte, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+	size := info.Size()
+	if n <= 0 {
+		data, err := io.ReadAll(io.NewSectionReader(f, 0, size))
+		return data, int64(len(data)), err
+	}
+
+	// Walk backwards in chunks until enough newlines are seen.
+	start := size
+	lines := 0
+	buf := make([]byte, readChunkSize)
+	for start > 0 {
+		step := int64(len(buf))
+		if start < step {
+			step = start
+		}
+		start -= step
+		if _, err := f.ReadAt(buf[:step], start); err != nil && !errors.Is(err, io.EOF) {
+			return nil, 0, err
+		}
+		for i := step - 1; i >= 0; i-- {
+			if buf[i] != '\n' {
+				continue
+			}
+			// A trailing newline terminates the last line rather than starting a new one.
+			if start+i == size-1 {
+				continue
+			}
+			lines++
+			if lines == n {
+				start += i + 1
+				data, err := io.ReadAll(io.NewSectionReader(f, start, size-start))
+				return data, size, err
+			}
+		}
+	}
+	data, err := io.ReadAll(io.NewSectionReader(f, 0, size))
+	return data, size, err
+}
+
+// Follow polls path for data appended after offset and passes it to fn until
+// ctx is done or fn fails. A file that shrinks (rotation) is re-read from the start.
+func Follow(ctx context.Context, path string, offset int64, fn func([]byte) error) error {
+	ticker := time.NewTicker(followInterval)
+	defer ticker.Stop()
+	buf := make([]byte, readChunkSize)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return err
+		}
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return err
+		}
+		if info.Size() < offset {
+			offset = 0
+		}
+		for offset < info.Size() {
+			n, err := f.ReadAt(buf, offset)
+			if n > 0 {
+				offset += int64(n)
+				if ferr := fn(append([]byte(nil), buf[:n]...)); ferr != nil {
+					f.Close()
+					return ferr
+				}
+			}
+			if err != nil {
+				break
+			}
+		}
+		f.Close()
+	}
+}
+
+func rotatedPath(path string, n int) string {
+	return path + "." + strconv.Itoa(n)
+}
+
+func openAppend(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+}
+
+// createAppend creates a uniquely named file and reopens it in append mode,
+// which copy-and-truncate rotation relies on.
+func createAppend(dir, pattern string) (*os.File, error) {
+	tmp, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return nil, err
+	}
+	name := tmp.Name()
+	tmp.Close()
+	f, err := openAppend(name)
+	if err != nil {
+		os.Remove(name)
+		return nil, err
+	}
+	return f, nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
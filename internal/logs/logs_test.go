@@ -0,0 +1,82 @@
+package logs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTailReturnsLastLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.log")
+	if err := os.WriteFile(path, []byte("a\nb\nc\nd\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	data, offset, err := Tail(path, 2)
+	if err != nil {
+		t.Fatalf("tail: %v", err)
+	}
+	if string(data) != "c\nd\n" || offset != 8 {
+		t.Fatalf("unexpected tail %q offset %d", data, offset)
+	}
+	data, _, err = Tail(path, 10)
+	if err != nil || string(data) != "a\nb\nc\nd\n" {
+		t.Fatalf("expected whole file, got %q (%v)", data, err)
+	}
+}
+
+func TestRotateKeepsConfiguredCopies(t *testing.T) {
+	dir := t.TempDir()
+	m := NewManager(dir, 4, 2)
+	stdout, stderr, err := m.Open(7)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer stdout.Close()
+	defer stderr.Close()
+
+	for _, chunk := range []string{"first\n", "second\n", "third\n"} {
+		if _, err := stdout.WriteString(chunk); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Rotate(7); err != nil {
+			t.Fatalf("rotate: %v", err)
+		}
+	}
+
+	live := m.Path(7, Stdout)
+	read := func(path string) string {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read %s: %v", path, err)
+		}
+		return string(b)
+	}
+	if got := read(live); got != "" {
+		t.Fatalf("live file should be truncated, got %q", got)
+	}
+	if got := read(live + ".1"); got != "third\n" {
+		t.Fatalf("unexpected .1 contents %q", got)
+	}
+	if got := read(live + ".2"); got != "second\n" {
+		t.Fatalf("unexpected .2 contents %q", got)
+	}
+	if _, err := os.Stat(live + ".3"); !os.IsNotExist(err) {
+		t.Fatalf("expected only 2 rotated copies, stat err=%v", err)
+	}
+
+	// Appends after truncation land at the start of the file, not past a hole.
+	if _, err := stdout.WriteString("fourth\n"); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(live); !strings.HasPrefix(got, "fourth") {
+		t.Fatalf("expected appended data at offset 0, got %q", got)
+	}
+
+	if err := m.Remove(7); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, "*")); len(matches) != 0 {
+		t.Fatalf("expected no files after remove, got %v", matches)
+	}
+}
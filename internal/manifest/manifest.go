@@ -0,0 +1,217 @@
+// Package manifest reads declarative process manifests for `goproc apply`.
+//
+// A manifest is YAML (or JSON, which YAML accepts) listing named processes:
+//
+//	name: dev
+//	processes:
+//	  - name: api
+//	    command: ./bin/api
+//	    args: [--port, "8080"]
+//	    env: {LOG_LEVEL: debug}
+//	    cwd: services/api
+//	    tags: [web]
+//	    restart: {policy: on-failure, backoff: 2s}
+//
+// Relative working directories are resolved against the manifest's own
+// directory, which is also the default.
+package manifest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"goproc/internal/app"
+	"goproc/internal/units"
+)
+
+// defaultMaxRestarts matches the default of `goproc run --max-restarts`.
+const defaultMaxRestarts = 5
+
+// Manifest is a parsed manifest.
+type Manifest struct {
+	// Name identifies the manifest's entries in the registry; it defaults
+	// to the file name without its extension.
+	Name      string    `yaml:"name"`
+	Processes []Process `yaml:"processes"`
+}
+
+// Process declares one named process.
+type Process struct {
+	Name          string            `yaml:"name"`
+	Command       string            `yaml:"command"`
+	Args          []string          `yaml:"args"`
+	Env           map[string]string `yaml:"env"`
+	Cwd           string            `yaml:"cwd"`
+	Tags          []string          `yaml:"tags"`
+	Groups        []string          `yaml:"groups"`
+	Annotations   map[string]string `yaml:"annotations"`
+	Restart       *Restart          `yaml:"restart"`
+	Cgroup        *Cgroup           `yaml:"cgroup"`
+	TrackChildren bool              `yaml:"track_children"`
+}
+
+// Restart is the restart policy of a process; see `goproc run --restart`.
+type Restart struct {
+	Policy      string        `yaml:"policy"`
+	MaxRestarts *int          `yaml:"max_restarts"`
+	Window      time.Duration `yaml:"window"`
+	Backoff     time.Duration `yaml:"backoff"`
+	MaxBackoff  time.Duration `yaml:"max_backoff"`
+}
+
+// Cgroup requests a cgroup v2 leaf with optional limits; see `goproc run
+// --cgroup`.
+type Cgroup struct {
+	MemoryMax Size    `yaml:"memory_max"`
+	CPUMax    float64 `yaml:"cpu_max"`
+	PIDsMax   int     `yaml:"pids_max"`
+}
+
+// Size is a byte count written as a number or with a unit, e.g. 512MB.
+type Size uint64
+
+// UnmarshalYAML accepts plain numbers and sizes with binary units.
+func (s *Size) UnmarshalYAML(node *yaml.Node) error {
+	n, err := units.ParseBytes(node.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+	*s = Size(n)
+	return nil
+}
+
+// Load reads the manifest at path; "-" reads standard input, in which case
+// the manifest must declare its name and relative directories are resolved
+// against the current directory.
+func Load(path string) (*Manifest, error) {
+	var (
+		data    []byte
+		err     error
+		dir     string
+		name    string
+		display = path
+	)
+	if path == "-" {
+		display = "standard input"
+		data, err = io.ReadAll(os.Stdin)
+		if err == nil {
+			dir, err = os.Getwd()
+		}
+	} else {
+		data, err = os.ReadFile(path)
+		if err == nil {
+			dir, err = filepath.Abs(filepath.Dir(path))
+		}
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, err
+	}
+	m, err := Parse(data, dir)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", display, err)
+	}
+	if m.Name == "" {
+		if name == "" {
+			return nil, errors.New("a manifest read from standard input must set name")
+		}
+		m.Name = name
+	}
+	return m, nil
+}
+
+// Parse decodes and validates a manifest. Relative working directories are
+// resolved against dir.
+func Parse(data []byte, dir string) (*Manifest, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var m Manifest
+	if err := dec.Decode(&m); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("manifest is empty")
+		}
+		return nil, err
+	}
+	m.Name = strings.TrimSpace(m.Name)
+	seen := make(map[string]struct{}, len(m.Processes))
+	for i := range m.Processes {
+		p := &m.Processes[i]
+		p.Name = strings.TrimSpace(p.Name)
+		if p.Name == "" {
+			return nil, fmt.Errorf("process %d: name is required", i+1)
+		}
+		if _, dup := seen[p.Name]; dup {
+			return nil, fmt.Errorf("process %q is declared twice", p.Name)
+		}
+		seen[p.Name] = struct{}{}
+		if strings.TrimSpace(p.Command) == "" {
+			return nil, fmt.Errorf("process %q: command is required", p.Name)
+		}
+		for k := range p.Env {
+			if k == "" || strings.Contains(k, "=") {
+				return nil, fmt.Errorf("process %q: invalid env name %q", p.Name, k)
+			}
+		}
+		switch {
+		case p.Cwd == "":
+			p.Cwd = dir
+		case !filepath.IsAbs(p.Cwd):
+			p.Cwd = filepath.Join(dir, p.Cwd)
+		}
+	}
+	return &m, nil
+}
+
+// SpawnParams converts the declared processes for app.ApplyParams.
+func (m *Manifest) SpawnParams() []app.SpawnParams {
+	out := make([]app.SpawnParams, 0, len(m.Processes))
+	for _, p := range m.Processes {
+		params := app.SpawnParams{
+			Argv:          append([]string{p.Command}, p.Args...),
+			Env:           envList(p.Env),
+			Dir:           p.Cwd,
+			Tags:          p.Tags,
+			Groups:        p.Groups,
+			Name:          p.Name,
+			Annotations:   p.Annotations,
+			TrackChildren: p.TrackChildren,
+		}
+		if r := p.Restart; r != nil {
+			params.Restart = app.RestartPolicy{
+				Mode:        r.Policy,
+				MaxRestarts: defaultMaxRestarts,
+				Window:      r.Window,
+				Backoff:     r.Backoff,
+				MaxBackoff:  r.MaxBackoff,
+			}
+			if r.MaxRestarts != nil {
+				params.Restart.MaxRestarts = *r.MaxRestarts
+			}
+		}
+		if c := p.Cgroup; c != nil {
+			params.Cgroup = &app.CgroupLimits{MemoryMax: uint64(c.MemoryMax), CPUMax: c.CPUMax, PIDsMax: c.PIDsMax}
+		}
+		out = append(out, params)
+	}
+	return out
+}
+
+// envList renders env as sorted KEY=VALUE pairs, so the same manifest always
+// produces the same environment.
+func envList(env map[string]string) []string {
+	out := make([]string, 0, len(env))
+	for k, v := range env {
+		out = append(out, k+"="+v)
+	}
+	slices.Sort(out)
+	return out
+}
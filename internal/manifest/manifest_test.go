@@ -0,0 +1,91 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const stack = `
+processes:
+  - name: api
+    command: ./bin/api
+    args: [--port, "8080"]
+    env: {B: "2", A: "1"}
+    cwd: services/api
+    tags: [web]
+    restart: {policy: on-failure, backoff: 2s}
+    cgroup: {memory_max: 64MB, cpu_max: 0.5}
+  - name: worker
+    command: worker
+    cwd: /srv/worker
+`
+
+func TestLoadResolvesDefaultsAndPaths(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "dev.yaml")
+	if err := os.WriteFile(path, []byte(stack), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if m.Name != "dev" {
+		t.Fatalf("name = %q, want the file name", m.Name)
+	}
+	params := m.SpawnParams()
+	if len(params) != 2 {
+		t.Fatalf("got %d processes", len(params))
+	}
+	api := params[0]
+	if !reflect.DeepEqual(api.Argv, []string{"./bin/api", "--port", "8080"}) {
+		t.Fatalf("argv = %v", api.Argv)
+	}
+	if !reflect.DeepEqual(api.Env, []string{"A=1", "B=2"}) {
+		t.Fatalf("env = %v, want sorted pairs", api.Env)
+	}
+	if api.Dir != filepath.Join(dir, "services/api") {
+		t.Fatalf("cwd = %q", api.Dir)
+	}
+	if api.Restart.Mode != "on-failure" || api.Restart.MaxRestarts != defaultMaxRestarts || api.Restart.Backoff != 2*time.Second {
+		t.Fatalf("restart = %+v", api.Restart)
+	}
+	if api.Cgroup == nil || api.Cgroup.MemoryMax != 64<<20 || api.Cgroup.CPUMax != 0.5 {
+		t.Fatalf("cgroup = %+v", api.Cgroup)
+	}
+	worker := params[1]
+	if worker.Dir != "/srv/worker" || worker.Restart.Mode != "" || worker.Cgroup != nil {
+		t.Fatalf("worker = %+v", worker)
+	}
+}
+
+func TestParseDefaultsCwdToManifestDir(t *testing.T) {
+	m, err := Parse([]byte("name: x\nprocesses:\n  - {name: a, command: true}\n"), "/etc/stack")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if m.Processes[0].Cwd != "/etc/stack" {
+		t.Fatalf("cwd = %q", m.Processes[0].Cwd)
+	}
+}
+
+func TestParseRejectsInvalidManifests(t *testing.T) {
+	cases := map[string]string{
+		"":                                  "manifest is empty",
+		"processes:\n  - {command: true}\n": "name is required",
+		"processes:\n  - {name: a}\n":       "command is required",
+		"processes:\n  - {name: a, command: x}\n  - {name: a, command: y}\n":  "declared twice",
+		"processes:\n  - {name: a, command: x, image: y}\n":                   "field image not found",
+		"processes:\n  - {name: a, command: x, cgroup: {memory_max: lots}}\n": "invalid size",
+		"processes:\n  - {name: a, command: x, cgroup: {memory_max: inf}}\n":  "invalid size",
+	}
+	for input, want := range cases {
+		if _, err := Parse([]byte(input), "/"); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Parse(%q) = %v, want error containing %q", input, err, want)
+		}
+	}
+}
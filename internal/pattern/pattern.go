@@ -0,0 +1,90 @@
+// Package pattern compiles the glob and regular expression selectors shared
+// by the CLI, which validates them before calling the daemon, and the
+// daemon, which matches entries against them.
+package pattern
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Compile returns the matchers of a selector field: one for its globs, if
+// any, and one for its regular expression, if set. Errors read "glob: ..."
+// or "regex: ..." so callers can prefix the field, as in "invalid name glob".
+func Compile(globs []string, regex string) ([]*regexp.Regexp, error) {
+	var out []*regexp.Regexp
+	glob, err := CompileGlobs(globs)
+	if err != nil {
+		return nil, fmt.Errorf("glob: %w", err)
+	}
+	if glob != nil {
+		out = append(out, glob)
+	}
+	if regex != "" {
+		re, err := regexp.Compile(regex)
+		if err != nil {
+			return nil, fmt.Errorf("regex: %w", err)
+		}
+		out = append(out, re)
+	}
+	return out, nil
+}
+
+// CompileGlobs turns shell-style globs into one anchored regular expression
+// that matches when any glob matches. '*' matches any run of characters
+// (including '/', so command globs can span paths), '?' one character and
+// [...] a character class; '\' escapes the next character. It returns nil
+// for no globs.
+func CompileGlobs(globs []string) (*regexp.Regexp, error) {
+	if len(globs) == 0 {
+		return nil, nil
+	}
+	alts := make([]string, 0, len(globs))
+	for _, g := range globs {
+		expr, err := globToRegexp(g)
+		if err != nil {
+			return nil, err
+		}
+		alts = append(alts, expr)
+	}
+	return regexp.Compile(`^(?:` + strings.Join(alts, "|") + `)$`)
+}
+
+func globToRegexp(glob string) (string, error) {
+	if glob == "" {
+		return "", fmt.Errorf("glob pattern must not be empty")
+	}
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '\\':
+			if i+1 == len(glob) {
+				return "", fmt.Errorf("glob %q ends with a dangling '\\'", glob)
+			}
+			i++
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				return "", fmt.Errorf("glob %q has an unclosed '['", glob)
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			if class == "" || class == "^" {
+				return "", fmt.Errorf("glob %q has an empty character class", glob)
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String(), nil
+}
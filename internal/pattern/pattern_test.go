@@ -0,0 +1,55 @@
+package pattern
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompileGlobs(t *testing.T) {
+	re, err := CompileGlobs([]string{"api-*", "worker-[0-9]", `lit\*`, "db?", "x[!a-c]"})
+	if err != nil {
+		t.Fatalf("CompileGlobs: %v", err)
+	}
+	for s, want := range map[string]bool{
+		"api-":       true,
+		"api-a/b":    true,
+		"worker-3":   true,
+		"worker-x":   false,
+		"lit*":       true,
+		"litx":       false,
+		"db1":        true,
+		"db12":       false,
+		"xd":         true,
+		"xb":         false,
+		"my-api-1":   false,
+		"worker-3.5": false,
+	} {
+		if got := re.MatchString(s); got != want {
+			t.Errorf("match %q = %v, want %v", s, got, want)
+		}
+	}
+	if re, err := CompileGlobs(nil); re != nil || err != nil {
+		t.Fatalf("CompileGlobs(nil) = %v, %v", re, err)
+	}
+	for _, bad := range []string{"", `a\`, "a[bc", "a[]", "a[!]"} {
+		if _, err := CompileGlobs([]string{bad}); err == nil {
+			t.Errorf("CompileGlobs(%q) should fail", bad)
+		}
+	}
+}
+
+func TestCompile(t *testing.T) {
+	pats, err := Compile([]string{"api-*"}, "-[0-9]+$")
+	if err != nil || len(pats) != 2 {
+		t.Fatalf("Compile = %v, %v", pats, err)
+	}
+	if pats, err := Compile(nil, ""); pats != nil || err != nil {
+		t.Fatalf("Compile without selectors = %v, %v", pats, err)
+	}
+	if _, err := Compile([]string{"a["}, ""); err == nil || !strings.HasPrefix(err.Error(), "glob: ") {
+		t.Fatalf("bad glob error = %v", err)
+	}
+	if _, err := Compile(nil, "("); err == nil || !strings.HasPrefix(err.Error(), "regex: ") {
+		t.Fatalf("bad regex error = %v", err)
+	}
+}
@@ -0,0 +1,228 @@
+// Package procfs reads per-process resource usage from Linux /proc.
+package procfs
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ClockTicks is USER_HZ, the unit of the CPU counters in /proc/<pid>/stat.
+// It is 100 on every mainstream Linux architecture.
+const ClockTicks = 100
+
+// Root is the procfs mount point; overridable for tests.
+var Root = "/proc"
+
+// Sample is a point-in-time reading of one process.
+type Sample struct {
+	At         time.Time
+	State      byte   // R, S, D, Z, ... (stat field 3)
+	PPID       int    // parent process (stat field 4)
+	PGID       int    // process group (stat field 5)
+	CPUTicks   uint64 // utime + stime
+	StartTicks uint64 // start time after boot (stat field 22)
+	Threads    int
+	RSS        uint64 // bytes
+	VMS        uint64 // bytes
+	FDs        int
+	ReadBytes  uint64 // storage bytes read (io read_bytes)
+	WriteBytes uint64 // storage bytes written (io write_bytes)
+}
+
+// Read samples pid. stat and status are required; io and fd are read
+// best-effort because they need the same UID (or ptrace access).
+func Read(pid int) (Sample, error) {
+	s := Sample{At: time.Now()}
+	if err := readStat(pid, &s); err != nil {
+		return Sample{}, err
+	}
+	if err := readStatus(pid, &s); err != nil {
+		return Sample{}, err
+	}
+	_ = readIO(pid, &s)
+	if entries, err := os.ReadDir(path(pid, "fd")); err == nil {
+		s.FDs = len(entries)
+	}
+	return s, nil
+}
+
+// StartTicks returns the start time of pid in clock ticks after boot. Together
+// with the boot ID it distinguishes a process from a later one that reuses its PID.
+func StartTicks(pid int) (uint64, error) {
+	var s Sample
+	if err := readStat(pid, &s); err != nil {
+		return 0, err
+	}
+	return s.StartTicks, nil
+}
+
+// Stat reads only /proc/<pid>/stat, which is enough for State, PPID, PGID
+// and StartTicks and is readable for every process.
+func Stat(pid int) (Sample, error) {
+	s := Sample{At: time.Now()}
+	if err := readStat(pid, &s); err != nil {
+		return Sample{}, err
+	}
+	return s, nil
+}
+
+// Zombie reports whether the sampled process has exited but was not reaped yet.
+func (s Sample) Zombie() bool {
+	return s.State == 'Z' || s.State == 'X'
+}
+
+// PIDs lists the processes currently visible in /proc.
+func PIDs() ([]int, error) {
+	entries, err := os.ReadDir(Root)
+	if err != nil {
+		return nil, err
+	}
+	pids := make([]int, 0, len(entries))
+	for _, e := range entries {
+		if pid, err := strconv.Atoi(e.Name()); err == nil && pid > 0 {
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}
+
+// Children maps every process in /proc to its direct children (ascending),
+// using the ppid of each process. Processes that exit during the scan are
+// skipped.
+func Children() (map[int][]int, error) {
+	pids, err := PIDs()
+	if err != nil {
+		return nil, err
+	}
+	sort.Ints(pids)
+	children := make(map[int][]int)
+	for _, pid := range pids {
+		var s Sample
+		if readStat(pid, &s) != nil || s.PPID <= 0 {
+			continue
+		}
+		children[s.PPID] = append(children[s.PPID], pid)
+	}
+	return children, nil
+}
+
+// BootID returns the kernel's random identifier of the current boot.
+func BootID() (string, error) {
+	data, err := os.ReadFile(filepath.Join(Root, "sys", "kernel", "random", "boot_id"))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// CPUPercent returns the CPU usage between two samples of the same process,
+// where 100 means one fully used core.
+func CPUPercent(prev, cur Sample) float64 {
+	elapsed := cur.At.Sub(prev.At).Seconds()
+	if elapsed <= 0 || cur.CPUTicks < prev.CPUTicks {
+		return 0
+	}
+	return float64(cur.CPUTicks-prev.CPUTicks) / ClockTicks / elapsed * 100
+}
+
+func path(pid int, name string) string {
+	return filepath.Join(Root, strconv.Itoa(pid), name)
+}
+
+func readStat(pid int, s *Sample) error {
+	data, err := os.ReadFile(path(pid, "stat"))
+	if err != nil {
+		return err
+	}
+	// comm (field 2) may contain spaces and parentheses; fields resume after the last ')'.
+	end := bytes.LastIndexByte(data, ')')
+	if end < 0 {
+		return fmt.Errorf("parse %s: missing comm", path(pid, "stat"))
+	}
+	fields := strings.Fields(string(data[end+1:]))
+	// fields[0] is field 3 (state).
+	const (
+		state     = 3 - 3
+		ppid      = 4 - 3
+		pgrp      = 5 - 3
+		utime     = 14 - 3
+		stime     = 15 - 3
+		startTime = 22 - 3
+	)
+	if len(fields) <= startTime {
+		return fmt.Errorf("parse %s: too few fields", path(pid, "stat"))
+	}
+	u, err1 := strconv.ParseUint(fields[utime], 10, 64)
+	st, err2 := strconv.ParseUint(fields[stime], 10, 64)
+	start, err3 := strconv.ParseUint(fields[startTime], 10, 64)
+	pg, err4 := strconv.Atoi(fields[pgrp])
+	parent, err5 := strconv.Atoi(fields[ppid])
+	if err := errors.Join(err1, err2, err3, err4, err5); err != nil {
+		return fmt.Errorf("parse %s: %w", path(pid, "stat"), err)
+	}
+	s.State = fields[state][0]
+	s.PPID = parent
+	s.PGID = pg
+	s.CPUTicks = u + st
+	s.StartTicks = start
+	return nil
+}
+
+func readStatus(pid int, s *Sample) error {
+	return scanKV(path(pid, "status"), func(key, value string) {
+		switch key {
+		case "VmRSS":
+			s.RSS = parseKB(value)
+		case "VmSize":
+			s.VMS = parseKB(value)
+		case "Threads":
+			s.Threads, _ = strconv.Atoi(value)
+		}
+	})
+}
+
+func readIO(pid int, s *Sample) error {
+	return scanKV(path(pid, "io"), func(key, value string) {
+		switch key {
+		case "read_bytes":
+			s.ReadBytes, _ = strconv.ParseUint(value, 10, 64)
+		case "write_bytes":
+			s.WriteBytes, _ = strconv.ParseUint(value, 10, 64)
+		}
+	})
+}
+
+// scanKV reads "key: value" lines.
+func scanKV(file string, fn func(key, value string)) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		key, value, ok := strings.Cut(sc.Text(), ":")
+		if !ok {
+			continue
+		}
+		fn(key, strings.TrimSpace(value))
+	}
+	return sc.Err()
+}
+
+// parseKB converts "1234 kB" to bytes.
+func parseKB(value string) uint64 {
+	n, err := strconv.ParseUint(strings.TrimSpace(strings.TrimSuffix(value, "kB")), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n * 1024
+}
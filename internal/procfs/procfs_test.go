@@ -0,0 +1,129 @@
+package procfs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReadParsesProcFiles(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "42")
+	if err := os.MkdirAll(filepath.Join(dir, "fd"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	stat := "42 (my (odd) proc) S 1 42 42 0 -1 4194560 100 0 0 0 250 50 0 0 20 0 3 0 98765 1000 200\n"
+	status := "Name:\tmy proc\nThreads:\t3\nVmSize:\t  2048 kB\nVmRSS:\t   512 kB\n"
+	io := "rchar: 1\nwchar: 2\nread_bytes: 4096\nwrite_bytes: 8192\n"
+	for name, content := range map[string]string{"stat": stat, "status": status, "io": io, "fd/0": "", "fd/1": ""} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := Root
+	Root = root
+	t.Cleanup(func() { Root = old })
+
+	s, err := Read(42)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if s.State != 'S' || s.PGID != 42 || s.CPUTicks != 300 || s.StartTicks != 98765 {
+		t.Fatalf("unexpected stat values: %+v", s)
+	}
+	if s.Threads != 3 || s.RSS != 512*1024 || s.VMS != 2048*1024 {
+		t.Fatalf("unexpected status values: %+v", s)
+	}
+	if s.ReadBytes != 4096 || s.WriteBytes != 8192 || s.FDs != 2 {
+		t.Fatalf("unexpected io/fd values: %+v", s)
+	}
+}
+
+func TestStatAndPIDs(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"7", "12", "self", "sys"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stat := "12 (zombie) Z 1 7 7 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 555 0 0\n"
+	if err := os.WriteFile(filepath.Join(root, "12", "stat"), []byte(stat), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	old := Root
+	Root = root
+	t.Cleanup(func() { Root = old })
+
+	s, err := Stat(12)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if !s.Zombie() || s.PPID != 1 || s.PGID != 7 || s.StartTicks != 555 {
+		t.Fatalf("unexpected stat values: %+v", s)
+	}
+	pids, err := PIDs()
+	if err != nil {
+		t.Fatalf("pids: %v", err)
+	}
+	if len(pids) != 2 || pids[0] != 12 || pids[1] != 7 {
+		t.Fatalf("expected pids [12 7], got %v", pids)
+	}
+}
+
+func TestChildren(t *testing.T) {
+	root := t.TempDir()
+	stats := map[string]string{
+		"1":  "1 (init) S 0 1 1 0 -1 0 0 0 0 0 0 0 0 0 20 0 1 0 1 0 0\n",
+		"30": "30 (server) S 1 30 30 0 -1 0 0 0 0 0 0 0 0 0 20 0 1 0 10 0 0\n",
+		"32": "32 (worker) S 30 30 30 0 -1 0 0 0 0 0 0 0 0 0 20 0 1 0 11 0 0\n",
+		"31": "31 (worker) S 30 30 30 0 -1 0 0 0 0 0 0 0 0 0 20 0 1 0 11 0 0\n",
+		"40": "40 (helper) S 31 30 30 0 -1 0 0 0 0 0 0 0 0 0 20 0 1 0 12 0 0\n",
+	}
+	for pid, stat := range stats {
+		if err := os.MkdirAll(filepath.Join(root, pid), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, pid, "stat"), []byte(stat), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A process that vanished between listing and reading is skipped.
+	if err := os.MkdirAll(filepath.Join(root, "50"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	old := Root
+	Root = root
+	t.Cleanup(func() { Root = old })
+
+	children, err := Children()
+	if err != nil {
+		t.Fatalf("children: %v", err)
+	}
+	want := map[int][]int{1: {30}, 30: {31, 32}, 31: {40}}
+	if !reflect.DeepEqual(children, want) {
+		t.Fatalf("expected %v, got %v", want, children)
+	}
+}
+
+func TestReadMissingProcess(t *testing.T) {
+	old := Root
+	Root = t.TempDir()
+	t.Cleanup(func() { Root = old })
+	if _, err := Read(7); !os.IsNotExist(err) {
+		t.Fatalf("expected not-exist error, got %v", err)
+	}
+}
+
+func TestCPUPercent(t *testing.T) {
+	at := time.Unix(100, 0)
+	prev := Sample{At: at, CPUTicks: 1000}
+	cur := Sample{At: at.Add(2 * time.Second), CPUTicks: 1100}
+	if got := CPUPercent(prev, cur); got != 50 {
+		t.Fatalf("expected 50%%, got %v", got)
+	}
+	if got := CPUPercent(cur, prev); got != 0 {
+		t.Fatalf("expected 0 for reversed samples, got %v", got)
+	}
+}
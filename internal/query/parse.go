@@ -0,0 +1,350 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"goproc/internal/units"
+)
+
+// Parse parses a query. An empty or blank query returns a nil Expr, which
+// matches everything. Errors are *Error values carrying the position.
+//
+// Grammar, lowest precedence first (keywords are case-insensitive):
+//
+//	expr  = and { "or" and }
+//	and   = unary { "and" unary }
+//	unary = "not" unary | "(" expr ")" | cond
+//	cond  = field [ op value ]
+func Parse(src string) (Expr, error) {
+	p := &parser{src: src}
+	p.skipSpace()
+	if p.eof() {
+		return nil, nil
+	}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if !p.eof() {
+		if p.peek() == ')' {
+			return nil, p.errorf(p.pos, "unexpected \")\" without matching \"(\"")
+		}
+		return nil, p.errorf(p.pos, "expected \"and\" or \"or\", found %q", p.word())
+	}
+	return e, nil
+}
+
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) eof() bool  { return p.pos >= len(p.src) }
+func (p *parser) peek() byte { return p.src[p.pos] }
+
+func (p *parser) errorf(pos int, format string, args ...any) *Error {
+	return &Error{Query: p.src, Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) skipSpace() {
+	for !p.eof() && isSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+// word returns the identifier-like run at the current position without
+// consuming it; used for keywords and error messages.
+func (p *parser) word() string {
+	end := p.pos
+	for end < len(p.src) && isIdentByte(p.src[end]) {
+		end++
+	}
+	if end == p.pos && !p.eof() {
+		return p.src[p.pos : p.pos+1]
+	}
+	return p.src[p.pos:end]
+}
+
+// keyword consumes kw if it is the next word.
+func (p *parser) keyword(kw string) bool {
+	p.skipSpace()
+	if w := p.word(); strings.EqualFold(w, kw) {
+		p.pos += len(w)
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	p.skipSpace()
+	if p.eof() {
+		return nil, p.errorf(p.pos, "unexpected end of query, expected a condition")
+	}
+	if p.keyword("not") {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{X: x}, nil
+	}
+	if p.peek() == '(' {
+		open := p.pos
+		p.pos++
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.eof() || p.peek() != ')' {
+			if p.eof() {
+				return nil, p.errorf(open, "unclosed \"(\"")
+			}
+			return nil, p.errorf(p.pos, "expected \")\", found %q", p.word())
+		}
+		p.pos++
+		return e, nil
+	}
+	return p.parseCond()
+}
+
+func (p *parser) parseCond() (Expr, error) {
+	start := p.pos
+	name := p.word()
+	if name == "" || !isIdentByte(name[0]) {
+		return nil, p.errorf(start, "expected a field name, found %q", name)
+	}
+	switch strings.ToLower(name) {
+	case "and", "or":
+		return nil, p.errorf(start, "expected a condition before %q", name)
+	}
+	p.pos += len(name)
+
+	c := &Cond{Pos: start}
+	if key, ok := cutAnnotation(name); ok {
+		if key == "" {
+			return nil, p.errorf(start, "missing annotation key after %q", name)
+		}
+		c.Field, c.Key = FieldAnnotation, key
+	} else {
+		c.Field = Field(strings.ToLower(name))
+		if _, known := fieldKinds[c.Field]; !known || c.Field == FieldAnnotation {
+			return nil, p.errorf(start, "unknown field %q", name)
+		}
+	}
+
+	opPos := p.pos
+	c.Op = p.op()
+	if c.Op == OpExists {
+		if kind := c.Kind(); kind != KindBool && c.Field != FieldAnnotation {
+			return nil, p.errorf(opPos, "field %q needs an operator and a value, e.g. %s", name, example(c.Field))
+		}
+		return c, nil
+	}
+	if c.Kind() == KindBool {
+		return nil, p.errorf(opPos, "field %q takes no value; use %q or \"not %s\"", name, name, name)
+	}
+	if !opAllowed(c.Kind(), c.Op) {
+		return nil, p.errorf(opPos, "operator %q cannot be used with field %q", c.Op, name)
+	}
+
+	valPos := p.pos
+	val, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	c.Value = val
+	if err := c.compile(); err != nil {
+		return nil, p.errorf(valPos, "%v", err)
+	}
+	return c, nil
+}
+
+// cutAnnotation splits "ann.<key>" (also "annotation.<key>").
+func cutAnnotation(name string) (string, bool) {
+	lower := strings.ToLower(name)
+	for _, prefix := range []string{"ann.", "annotation."} {
+		if strings.HasPrefix(lower, prefix) {
+			return name[len(prefix):], true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) op() Op {
+	rest := p.src[p.pos:]
+	for _, op := range []Op{OpNe, OpNotMatch, OpLe, OpGe, OpHas, OpEq, OpMatch, OpLt, OpGt} {
+		if strings.HasPrefix(rest, string(op)) {
+			p.pos += len(op)
+			return op
+		}
+	}
+	return OpExists
+}
+
+// value reads a quoted string or a bare word. A bare word ends at
+// whitespace or at a ")" that closes a group opened outside the value, so
+// regular expressions like ^(a|b)$ need no quoting.
+func (p *parser) value() (string, error) {
+	if p.eof() || isSpace(p.peek()) {
+		return "", p.errorf(p.pos, "expected a value after the operator")
+	}
+	if q := p.peek(); q == '"' || q == '\'' {
+		return p.quoted(q)
+	}
+	start, depth := p.pos, 0
+	for ; !p.eof(); p.pos++ {
+		c := p.peek()
+		if isSpace(c) {
+			break
+		}
+		if c == '(' {
+			depth++
+		} else if c == ')' {
+			if depth == 0 {
+				break
+			}
+			depth--
+		}
+	}
+	if p.pos == start {
+		return "", p.errorf(p.pos, "expected a value after the operator")
+	}
+	return p.src[start:p.pos], nil
+}
+
+// quoted reads a quoted value. Double quotes support \" and \\ escapes;
+// single quotes are taken literally.
+func (p *parser) quoted(q byte) (string, error) {
+	open := p.pos
+	p.pos++
+	var b strings.Builder
+	for !p.eof() {
+		c := p.peek()
+		p.pos++
+		switch {
+		case c == q:
+			return b.String(), nil
+		case c == '\\' && q == '"' && !p.eof() && (p.peek() == '"' || p.peek() == '\\'):
+			b.WriteByte(p.peek())
+			p.pos++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf(open, "unterminated quoted value")
+}
+
+// compile validates the value for the field kind and fills Num or Re.
+func (c *Cond) compile() error {
+	if c.Op == OpMatch || c.Op == OpNotMatch {
+		re, err := regexp.Compile(c.Value)
+		if err != nil {
+			return fmt.Errorf("invalid regular expression: %v", strings.TrimPrefix(err.Error(), "error parsing regexp: "))
+		}
+		c.Re = re
+		return nil
+	}
+	var err error
+	switch c.Kind() {
+	case KindNumber:
+		c.Num, err = strconv.ParseFloat(strings.TrimSuffix(c.Value, "%"), 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", c.Value)
+		}
+	case KindSize:
+		var n uint64
+		n, err = units.ParseBytes(c.Value)
+		c.Num = float64(n)
+	case KindDuration:
+		c.Num, err = parseDuration(c.Value)
+	}
+	return err
+}
+
+func opAllowed(kind Kind, op Op) bool {
+	switch op {
+	case OpHas, OpEq, OpNe:
+		return true
+	case OpMatch, OpNotMatch:
+		return kind == KindString || kind == KindSet
+	case OpLt, OpLe, OpGt, OpGe:
+		return kind == KindNumber || kind == KindSize || kind == KindDuration
+	}
+	return false
+}
+
+func example(f Field) string {
+	switch fieldKinds[f] {
+	case KindSet:
+		return string(f) + ":web"
+	case KindSize:
+		return string(f) + ">500MB"
+	case KindDuration:
+		return string(f) + ">1h"
+	case KindNumber:
+		return string(f) + ">10"
+	default:
+		return string(f) + "~^api-"
+	}
+}
+
+// parseDuration reads Go durations plus a "d" suffix for days and returns
+// seconds.
+func parseDuration(raw string) (float64, error) {
+	if days, ok := strings.CutSuffix(raw, "d"); ok {
+		if n, err := strconv.ParseFloat(days, 64); err == nil && n >= 0 {
+			return n * 24 * 3600, nil
+		}
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q (expected e.g. 90s, 1h30m, 2d)", raw)
+	}
+	return d.Seconds(), nil
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// isIdentByte reports whether c may appear in a field name or keyword.
+// Annotation keys may contain '.', '-', '_' and '/'.
+func isIdentByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '_' || c == '.' || c == '-' || c == '/'
+}
@@ -0,0 +1,169 @@
+// Package query implements the selector expression language shared by the
+// CLI, the TUI and the daemon, for example:
+//
+//	tag:db and (group:prod or name~^api-) and alive and rss>500MB and age>1h
+//
+// Expressions are parsed and type-checked here; evaluation against registry
+// entries lives in the registry so it can use its indexes.
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Expr is a parsed expression: *And, *Or, *Not or *Cond.
+type Expr interface {
+	String() string
+}
+
+// And matches when both sides match.
+type And struct{ Left, Right Expr }
+
+// Or matches when either side matches.
+type Or struct{ Left, Right Expr }
+
+// Not inverts X.
+type Not struct{ X Expr }
+
+// Field identifies what a condition inspects.
+type Field string
+
+const (
+	FieldID         Field = "id"
+	FieldPID        Field = "pid"
+	FieldPGID       Field = "pgid"
+	FieldName       Field = "name"
+	FieldCmd        Field = "cmd"
+	FieldTag        Field = "tag"
+	FieldGroup      Field = "group"
+	FieldAlive      Field = "alive"
+	FieldRestarts   Field = "restarts"
+	FieldParent     Field = "parent"   // ID of the parent entry; 0 for top-level entries
+	FieldChildren   Field = "children" // number of direct child processes
+	FieldCPU        Field = "cpu"
+	FieldRSS        Field = "rss"
+	FieldVMS        Field = "vms"
+	FieldThreads    Field = "threads"
+	FieldFDs        Field = "fds"
+	FieldAge        Field = "age"
+	FieldAnnotation Field = "ann" // written ann.<key>
+)
+
+// Kind is the value type of a field, which decides the operators it accepts.
+type Kind int
+
+const (
+	KindString   Kind = iota // name, cmd, annotations
+	KindSet                  // tag, group: the condition holds for any member
+	KindNumber               // id, pid, pgid, restarts, parent, children, cpu, threads, fds
+	KindSize                 // rss, vms: bytes, written with units like 500MB
+	KindDuration             // age: seconds, written like 90s or 2d
+	KindBool                 // alive: written bare
+)
+
+var fieldKinds = map[Field]Kind{
+	FieldID:         KindNumber,
+	FieldPID:        KindNumber,
+	FieldPGID:       KindNumber,
+	FieldName:       KindString,
+	FieldCmd:        KindString,
+	FieldTag:        KindSet,
+	FieldGroup:      KindSet,
+	FieldAlive:      KindBool,
+	FieldRestarts:   KindNumber,
+	FieldParent:     KindNumber,
+	FieldChildren:   KindNumber,
+	FieldCPU:        KindNumber,
+	FieldRSS:        KindSize,
+	FieldVMS:        KindSize,
+	FieldThreads:    KindNumber,
+	FieldFDs:        KindNumber,
+	FieldAge:        KindDuration,
+	FieldAnnotation: KindString,
+}
+
+// Op is a comparison operator.
+type Op string
+
+const (
+	OpExists   Op = ""   // bare field: alive, ann.<key>
+	OpHas      Op = ":"  // membership for sets, substring for cmd, equality otherwise
+	OpEq       Op = "="  // equality (membership for sets)
+	OpNe       Op = "!=" // inequality (non-membership for sets)
+	OpMatch    Op = "~"  // regular expression
+	OpNotMatch Op = "!~" // negated regular expression
+	OpLt       Op = "<"
+	OpLe       Op = "<="
+	OpGt       Op = ">"
+	OpGe       Op = ">="
+)
+
+// Cond is a single field condition.
+type Cond struct {
+	Field Field
+	Key   string // annotation key for FieldAnnotation
+	Op    Op
+	Value string         // raw value as written (unquoted)
+	Num   float64        // parsed value for number, size and duration fields
+	Re    *regexp.Regexp // compiled value for ~ and !~
+	Pos   int            // byte offset of the field in the query
+}
+
+// Kind returns the value type of the condition's field.
+func (c *Cond) Kind() Kind { return fieldKinds[c.Field] }
+
+func (e *And) String() string { return "(" + e.Left.String() + " and " + e.Right.String() + ")" }
+func (e *Or) String() string  { return "(" + e.Left.String() + " or " + e.Right.String() + ")" }
+func (e *Not) String() string { return "not " + e.X.String() }
+
+func (c *Cond) String() string {
+	field := string(c.Field)
+	if c.Field == FieldAnnotation {
+		field += "." + c.Key
+	}
+	if c.Op == OpExists {
+		return field
+	}
+	return field + string(c.Op) + quoteValue(c.Value)
+}
+
+// quoteValue quotes v unless it would read back as the same bare value.
+func quoteValue(v string) string {
+	depth := 0
+	for _, c := range v {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		if depth < 0 {
+			break
+		}
+	}
+	if v == "" || depth < 0 || strings.ContainsAny(v, " \t\r\n\"'") {
+		return fmt.Sprintf("%q", v)
+	}
+	return v
+}
+
+// Error is a parse error at a position in the query.
+type Error struct {
+	Query string
+	Pos   int // byte offset
+	Msg   string
+}
+
+// Column is the 1-based character column of the error.
+func (e *Error) Column() int {
+	return utf8.RuneCountInString(e.Query[:e.Pos]) + 1
+}
+
+// Error renders the message followed by the query with a caret under the
+// offending position.
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid query at column %d: %s\n  %s\n  %s^", e.Column(), e.Msg, e.Query, strings.Repeat(" ", e.Column()-1))
+}
@@ -0,0 +1,95 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for src, want := range map[string]string{
+		"tag:db":                             "tag:db",
+		"alive":                              "alive",
+		"not alive":                          "not alive",
+		"tag:a or tag:b and tag:c":           "(tag:a or (tag:b and tag:c))",
+		"(tag:a or tag:b) and tag:c":         "((tag:a or tag:b) and tag:c)",
+		"NOT tag:a AND alive":                "(not tag:a and alive)",
+		"name~^api-":                         "name~^api-",
+		"(group:prod or name~^api-)":         "(group:prod or name~^api-)",
+		"name~^(a|b)$":                       "name~^(a|b)$",
+		`cmd:"sleep 30"`:                     `cmd:"sleep 30"`,
+		`cmd:'a "b"'`:                        `cmd:"a \"b\""`,
+		"ann.team/owner=alice":               "ann.team/owner=alice",
+		"ann.owner":                          "ann.owner",
+		"rss>=1.5GiB and cpu<50% and fds!=3": "((rss>=1.5GiB and cpu<50%) and fds!=3)",
+		"parent=3 or children>0":             "(parent=3 or children>0)",
+	} {
+		e, err := Parse(src)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", src, err)
+		}
+		if got := e.String(); got != want {
+			t.Fatalf("Parse(%q) = %s, want %s", src, got, want)
+		}
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	e, err := Parse("rss>1.5GB and age>2d and cpu>12.5%")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	and := e.(*And)
+	inner := and.Left.(*And)
+	if got := inner.Left.(*Cond).Num; got != 3<<29 {
+		t.Fatalf("rss = %v", got)
+	}
+	if got := inner.Right.(*Cond).Num; got != 2*24*3600 {
+		t.Fatalf("age = %v", got)
+	}
+	if got := and.Right.(*Cond).Num; got != 12.5 {
+		t.Fatalf("cpu = %v", got)
+	}
+	if e, err := Parse("  "); e != nil || err != nil {
+		t.Fatalf("blank query = %v, %v", e, err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tc := range []struct {
+		src    string
+		column int
+		msg    string
+	}{
+		{"tag:db and rsss>5MB", 12, `unknown field "rsss"`},
+		{"tag:db alive", 8, `expected "and" or "or", found "alive"`},
+		{"(tag:db or alive", 1, `unclosed "("`},
+		{"tag:db)", 7, `unexpected ")" without matching "("`},
+		{"tag:db and", 11, "unexpected end of query, expected a condition"},
+		{"rss>lots", 5, `invalid size "lots" (expected e.g. 512KB, 500MB, 2GiB)`},
+		{"rss>inf", 5, `invalid size "inf" (expected e.g. 512KB, 500MB, 2GiB)`},
+		{"tag>3", 4, `operator ">" cannot be used with field "tag"`},
+		{"tag", 4, `field "tag" needs an operator and a value, e.g. tag:web`},
+		{"alive=true", 6, `field "alive" takes no value; use "alive" or "not alive"`},
+		{"name~(", 6, "invalid regular expression: missing closing ): `(`"},
+		{`cmd:"open`, 5, "unterminated quoted value"},
+		{"name= x", 6, "expected a value after the operator"},
+		{"and alive", 1, `expected a condition before "and"`},
+	} {
+		_, err := Parse(tc.src)
+		var qerr *Error
+		if !errors.As(err, &qerr) {
+			t.Fatalf("Parse(%q) error = %v, want *Error", tc.src, err)
+		}
+		if qerr.Column() != tc.column || qerr.Msg != tc.msg {
+			t.Fatalf("Parse(%q) = column %d %q, want column %d %q", tc.src, qerr.Column(), qerr.Msg, tc.column, tc.msg)
+		}
+	}
+}
+
+func TestErrorShowsCaret(t *testing.T) {
+	_, err := Parse("alive and rsss>1")
+	want := "invalid query at column 11: unknown field \"rsss\"\n  alive and rsss>1\n            ^"
+	if err == nil || err.Error() != want {
+		t.Fatalf("error = %q, want %q", err, want)
+	}
+}
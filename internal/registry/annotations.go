@@ -0,0 +1,123 @@
+package registry
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"unicode"
+)
+
+const (
+	maxAnnotationKeyLen   = 63
+	maxAnnotationValueLen = 256
+)
+
+// AnnotationOp selects how an AnnotationSelector compares values.
+type AnnotationOp string
+
+const (
+	AnnotationExists AnnotationOp = "exists" // key is present, any value
+	AnnotationEquals AnnotationOp = "equals" // value == Value
+	AnnotationPrefix AnnotationOp = "prefix" // value starts with Value
+)
+
+// AnnotationSelector matches entries by one annotation.
+type AnnotationSelector struct {
+	Key   string
+	Op    AnnotationOp
+	Value string
+}
+
+// ParseAnnotationOp validates a wire operator; empty means AnnotationExists.
+func ParseAnnotationOp(raw string) (AnnotationOp, error) {
+	switch op := AnnotationOp(strings.TrimSpace(raw)); op {
+	case "", AnnotationExists:
+		return AnnotationExists, nil
+	case AnnotationEquals, AnnotationPrefix:
+		return op, nil
+	default:
+		return "", fmt.Errorf("invalid annotation operator %q (expected exists, equals, or prefix)", raw)
+	}
+}
+
+// Match reports whether annotations satisfy the selector.
+func (s AnnotationSelector) Match(annotations map[string]string) bool {
+	v, ok := annotations[s.Key]
+	if !ok {
+		return false
+	}
+	switch s.Op {
+	case AnnotationEquals:
+		return v == s.Value
+	case AnnotationPrefix:
+		return strings.HasPrefix(v, s.Value)
+	default:
+		return true
+	}
+}
+
+// NormalizeAnnotations trims and validates keys and values. Keys use the
+// name alphabet plus '/', so "team/owner" style keys work. It returns nil
+// for an empty map, so entries without annotations stay compact.
+func NormalizeAnnotations(in map[string]string) (map[string]string, error) {
+	if len(in) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]string, len(in))
+	for k, v := range in {
+		key := strings.TrimSpace(k)
+		if key == "" {
+			return nil, fmt.Errorf("annotation key must not be empty")
+		}
+		if len(key) > maxAnnotationKeyLen {
+			return nil, fmt.Errorf("annotation key %q is too long (max %d characters)", key, maxAnnotationKeyLen)
+		}
+		for _, r := range key {
+			if !isAllowedNameRune(r) && r != '/' {
+				return nil, fmt.Errorf("annotation key %q contains invalid character %q (allowed: letters, digits, '.', '-', '_', '/')", key, r)
+			}
+		}
+		value := strings.TrimSpace(v)
+		if len(value) > maxAnnotationValueLen {
+			return nil, fmt.Errorf("annotation %q value is too long (max %d characters)", key, maxAnnotationValueLen)
+		}
+		if strings.IndexFunc(value, unicode.IsControl) >= 0 {
+			return nil, fmt.Errorf("annotation %q value contains control characters", key)
+		}
+		out[key] = value
+	}
+	return out, nil
+}
+
+// SetAnnotations replaces the annotations of an entry, emitting a labels
+// event and saving if they changed. It reports whether anything changed.
+func (r *Registry) SetAnnotations(id ProcID, annotations map[string]string) (bool, error) {
+	annotations, err := NormalizeAnnotations(annotations)
+	if err != nil {
+		return false, err
+	}
+	r.mu.Lock()
+	p := r.byID[id]
+	if p == nil {
+		r.mu.Unlock()
+		return false, osErrNotFound(id)
+	}
+	if maps.Equal(p.Meta.Annotations, annotations) {
+		r.mu.Unlock()
+		return false, nil
+	}
+	for k := range p.Meta.Annotations {
+		delete(r.byAnnotation[k], id)
+		if len(r.byAnnotation[k]) == 0 {
+			delete(r.byAnnotation, k)
+		}
+	}
+	p.Meta.Annotations = annotations
+	r.indexAnnotationsLocked(p)
+	r.markLocked(id)
+	r.emitLocked(EventLabels, p, "")
+	r.mu.Unlock()
+
+	r.maybeSave()
+	return true, nil
+}
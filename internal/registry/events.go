@@ -0,0 +1,105 @@
+package registry
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// EventType classifies registry changes delivered to subscribers.
+type EventType string
+
+const (
+	EventAdded   EventType = "added"
+	EventRemoved EventType = "removed"
+	EventDied    EventType = "died"    // alive -> dead
+	EventRevived EventType = "revived" // dead -> alive (including supervisor restarts)
+	EventLabels  EventType = "labels"  // tags, groups or annotations changed
+	EventRenamed EventType = "renamed" // name set, changed or cleared
+	EventReset   EventType = "reset"
+)
+
+// Event describes one registry mutation.
+type Event struct {
+	Type EventType
+	// Proc is the entry after the change; for EventRemoved it is the last
+	// known state. Empty for EventReset.
+	Proc Proc
+	// PreviousName is set for EventRenamed.
+	PreviousName string
+	At           time.Time
+}
+
+// ErrSubscriberLagged is reported by a subscription that was dropped because
+// it did not drain its buffer fast enough.
+var ErrSubscriberLagged = errors.New("watch subscriber fell behind and was dropped")
+
+const defaultEventBuffer = 256
+
+// Subscription receives registry events until closed.
+type Subscription struct {
+	r  *Registry
+	ch chan Event
+
+	once   sync.Once
+	lagged bool
+}
+
+// C returns the event channel. It is closed when the subscription ends.
+func (s *Subscription) C() <-chan Event {
+	return s.ch
+}
+
+// Err reports why the channel was closed, if it was dropped for lagging.
+func (s *Subscription) Err() error {
+	s.r.mu.RLock()
+	defer s.r.mu.RUnlock()
+	if s.lagged {
+		return ErrSubscriberLagged
+	}
+	return nil
+}
+
+// Close stops delivery and releases the subscription.
+func (s *Subscription) Close() {
+	s.r.mu.Lock()
+	defer s.r.mu.Unlock()
+	s.closeLocked()
+}
+
+func (s *Subscription) closeLocked() {
+	s.once.Do(func() {
+		delete(s.r.subs, s)
+		close(s.ch)
+	})
+}
+
+// Subscribe registers a listener for registry events. Events are produced
+// while the registry lock is held, so every transition is observed in order;
+// a subscriber whose buffer fills up is dropped instead of blocking mutations.
+func (r *Registry) Subscribe() *Subscription {
+	s := &Subscription{r: r, ch: make(chan Event, defaultEventBuffer)}
+	r.mu.Lock()
+	r.subs[s] = struct{}{}
+	r.mu.Unlock()
+	return s
+}
+
+// emitLocked fans an event out to subscribers. Caller holds r.mu for writing.
+func (r *Registry) emitLocked(typ EventType, p *Proc, previousName string) {
+	if len(r.subs) == 0 {
+		return
+	}
+	ev := Event{Type: typ, PreviousName: previousName, At: now()}
+	if p != nil {
+		ev.Proc = *p
+	}
+	for s := range r.subs {
+		select {
+		case s.ch <- ev:
+		default:
+			s.lagged = true
+			s.closeLocked()
+		}
+	}
+}
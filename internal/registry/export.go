@@ -0,0 +1,292 @@
+package registry
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// exportDoc is the document written by Export. It shares the entry schema
+// and version of the snapshot, so older exports are migrated on import the
+// same way old snapshots are on load.
+type exportDoc struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	Procs      []Proc    `json:"procs"`
+}
+
+// Export returns the entries matching f as an indented JSON document for
+// Import, sorted by ID, and how many there are.
+func (r *Registry) Export(f ListFilter) ([]byte, int, error) {
+	doc := exportDoc{Version: snapshotVersion, ExportedAt: now().UTC(), Procs: r.List(f)}
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, 0, err
+	}
+	return append(b, '\n'), len(doc.Procs), nil
+}
+
+// ParseExport reads a document written by Export, migrating it from older
+// versions.
+func ParseExport(b []byte) ([]Proc, error) {
+	s, _, err := decodeSnapshot(b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid export: %w", err)
+	}
+	return s.Procs, nil
+}
+
+// NameConflict selects what Import does with an entry whose name is taken.
+type NameConflict string
+
+const (
+	ConflictRename  NameConflict = "rename"  // add it under the first free name-N
+	ConflictSkip    NameConflict = "skip"    // leave it out
+	ConflictReplace NameConflict = "replace" // remove the entry holding the name
+	ConflictFail    NameConflict = "fail"    // import nothing
+)
+
+// ParseNameConflict validates a conflict policy; empty means ConflictRename.
+func ParseNameConflict(raw string) (NameConflict, error) {
+	switch policy := NameConflict(strings.ToLower(strings.TrimSpace(raw))); policy {
+	case "":
+		return ConflictRename, nil
+	case ConflictRename, ConflictSkip, ConflictReplace, ConflictFail:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid name conflict policy %q (expected rename, skip, replace, or fail)", raw)
+	}
+}
+
+// ImportOptions configures Import.
+type ImportOptions struct {
+	// Replace removes every existing entry before importing; otherwise the
+	// imported entries are merged into the registry.
+	Replace bool
+	// OnConflict resolves imported names that are already taken, including
+	// by entries imported earlier in the same call.
+	OnConflict NameConflict
+	// Adopt reports whether the process an entry recorded is still running
+	// and should be tracked again. It is called before the registry is
+	// locked. Entries it rejects, or all entries when it is nil, are
+	// imported dead and Detached.
+	Adopt func(Proc) bool
+}
+
+// ImportAction is what Import did with one entry.
+type ImportAction string
+
+const (
+	ImportAdded    ImportAction = "added"
+	ImportRenamed  ImportAction = "renamed"  // added under another name
+	ImportReplaced ImportAction = "replaced" // added after removing the entry holding its name
+	ImportSkipped  ImportAction = "skipped"
+)
+
+// ImportResult reports the outcome for one imported entry.
+type ImportResult struct {
+	// From is the ID the entry had in the document and ID the one it was
+	// given; ID is 0 for skipped entries.
+	From   ProcID
+	ID     ProcID
+	Name   string
+	Action ImportAction
+	Alive  bool
+	// Replaced is the entry removed to free the name, for ImportReplaced.
+	Replaced ProcID
+	// Note explains a skip or rename.
+	Note string
+}
+
+// ImportReport is the outcome of Import.
+type ImportReport struct {
+	// Results follow the entries in the order of their original IDs.
+	Results []ImportResult
+	// Removed holds the entries that were removed, by Replace or by the
+	// replace conflict policy, so callers can release what they held.
+	Removed []Proc
+}
+
+// Import adds exported entries to the registry under new IDs taken from the
+// ID counter, which keeps counting up even with Replace, so IDs handed out
+// since the last Reset are never reused. Parent links are remapped to the new IDs and dropped when the
+// parent is not part of the import. Removed entries go to the history with
+// RemovalImport. Invalid entries, or any name conflict under ConflictFail,
+// fail the whole import before anything changes.
+func (r *Registry) Import(procs []Proc, opts ImportOptions) (ImportReport, error) {
+	var report ImportReport
+	policy := opts.OnConflict
+	if policy == "" {
+		policy = ConflictRename
+	}
+	in := slices.Clone(procs)
+	slices.SortStableFunc(in, func(a, b Proc) int { return cmp.Compare(a.ID, b.ID) })
+	seen := make(map[ProcID]struct{}, len(in))
+	alive := make([]bool, len(in))
+	for i := range in {
+		p, err := importedProc(in[i])
+		if err != nil {
+			return report, fmt.Errorf("entry %d: %w", in[i].ID, err)
+		}
+		if _, dup := seen[p.ID]; dup && p.ID != 0 {
+			return report, fmt.Errorf("entry %d appears twice", p.ID)
+		}
+		seen[p.ID] = struct{}{}
+		in[i] = p
+		if opts.Adopt != nil {
+			alive[i] = opts.Adopt(p)
+		}
+	}
+
+	r.mu.Lock()
+	if policy == ConflictFail {
+		if err := r.checkImportNamesLocked(in, opts.Replace); err != nil {
+			r.mu.Unlock()
+			return report, err
+		}
+	}
+	if opts.Replace {
+		ids := make([]ProcID, 0, len(r.byID))
+		for id := range r.byID {
+			ids = append(ids, id)
+		}
+		slices.Sort(ids)
+		for _, id := range ids {
+			p := r.byID[id]
+			report.Removed = append(report.Removed, *p)
+			r.removeLocked(p, RemovalImport)
+		}
+	}
+	newIDs := make(map[ProcID]ProcID, len(in))
+	for i := range in {
+		p := in[i]
+		res := ImportResult{From: p.ID, Name: p.Name, Action: ImportAdded}
+		if alive[i] {
+			if id, free := r.claimPIDLocked(p.PID, p.StartTicks, p.BootID); !free {
+				res.Action = ImportSkipped
+				res.Note = fmt.Sprintf("pid %d is already tracked as id %d", p.PID, id)
+				report.Results = append(report.Results, res)
+				continue
+			}
+		}
+		if holder, taken := r.byName[p.Name]; taken && p.Name != "" {
+			switch policy {
+			case ConflictSkip:
+				res.Action = ImportSkipped
+				res.Note = fmt.Sprintf("name %q is taken by id %d", p.Name, holder)
+				report.Results = append(report.Results, res)
+				continue
+			case ConflictReplace:
+				old := r.byID[holder]
+				report.Removed = append(report.Removed, *old)
+				r.removeLocked(old, RemovalImport)
+				res.Action = ImportReplaced
+				res.Replaced = holder
+			default:
+				res.Name = r.freeNameLocked(p.Name)
+				res.Action = ImportRenamed
+				res.Note = fmt.Sprintf("name %q is taken by id %d", p.Name, holder)
+			}
+		}
+
+		id := r.nextID
+		r.nextID++
+		if p.ID != 0 {
+			newIDs[p.ID] = id
+		}
+		if p.ParentID != 0 {
+			p.ParentID = newIDs[p.ParentID]
+		}
+		p.ID = id
+		p.Name = res.Name
+		if alive[i] {
+			p.Alive = true
+			p.LastSeen = now()
+		} else {
+			p.Alive = false
+			p.Detached = true
+			if p.Exit == nil {
+				p.Exit = &ExitStatus{At: p.LastSeen, Reason: ExitReasonUnknown, Code: -1}
+			}
+		}
+		r.indexLocked(&p)
+		r.markLocked(id)
+		r.emitLocked(EventAdded, &p, "")
+		res.ID, res.Alive = id, p.Alive
+		report.Results = append(report.Results, res)
+	}
+	r.mu.Unlock()
+
+	r.maybeSave()
+	return report, nil
+}
+
+// importedProc validates an exported entry and normalises it the way Add
+// would. The volatile fields are cleared and Detached reset, since it
+// described the exporting registry.
+func importedProc(p Proc) (Proc, error) {
+	if p.PID <= 0 {
+		return p, errors.New("pid must be > 0")
+	}
+	name, err := NormalizeName(p.Name)
+	if err != nil {
+		return p, err
+	}
+	annotations, err := NormalizeAnnotations(p.Meta.Annotations)
+	if err != nil {
+		return p, err
+	}
+	p.Name = name
+	p.Meta = ProcMeta{Tags: norm(p.Meta.Tags), Groups: norm(p.Meta.Groups), Annotations: annotations}
+	p.Detached = false
+	p.Metrics = nil
+	p.Children = nil
+	if p.AddedAt.IsZero() {
+		p.AddedAt = now()
+	}
+	if p.LastSeen.IsZero() {
+		p.LastSeen = p.AddedAt
+	}
+	return p, nil
+}
+
+// checkImportNamesLocked fails if any imported name is taken, either in the
+// registry (unless it is about to be replaced) or by another imported entry.
+// Caller holds r.mu.
+func (r *Registry) checkImportNamesLocked(procs []Proc, replace bool) error {
+	names := make(map[string]ProcID, len(procs))
+	for _, p := range procs {
+		if p.Name == "" {
+			continue
+		}
+		if other, dup := names[p.Name]; dup {
+			return fmt.Errorf("entries %d and %d: %w", other, p.ID, errNameInUse(p.Name))
+		}
+		names[p.Name] = p.ID
+		if holder, taken := r.byName[p.Name]; taken && !replace {
+			return fmt.Errorf("entry %d: %w by id %d", p.ID, errNameInUse(p.Name), holder)
+		}
+	}
+	return nil
+}
+
+// freeNameLocked returns the first of name-2, name-3, ... that no entry
+// holds, shortening name to keep it within maxNameLen. Caller holds r.mu.
+func (r *Registry) freeNameLocked(name string) string {
+	for n := 2; ; n++ {
+		suffix := fmt.Sprintf("-%d", n)
+		base := name
+		for len(base)+len(suffix) > maxNameLen {
+			_, size := utf8.DecodeLastRuneInString(base)
+			base = base[:len(base)-size]
+		}
+		if _, taken := r.byName[base+suffix]; !taken {
+			return base + suffix
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// exported returns the entries of r as they come out of an export document.
+func exported(t *testing.T, r *Registry, f ListFilter) []Proc {
+	t.Helper()
+	doc, _, err := r.Export(f)
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	procs, err := ParseExport(doc)
+	if err != nil {
+		t.Fatalf("ParseExport: %v", err)
+	}
+	return procs
+}
+
+// actions lists import results as "from>id:name:action" for comparisons.
+func actions(report ImportReport) []string {
+	var out []string
+	for _, res := range report.Results {
+		out = append(out, fmt.Sprintf("%d>%d:%s:%s", res.From, res.ID, res.Name, res.Action))
+	}
+	return out
+}
+
+func TestImportRemapsIDs(t *testing.T) {
+	src := openStoreRegistry(t, nil, 0)
+	api := mustAdd(t, src, 100, "api")
+	if err := src.Tag(api, []string{"web"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := src.Add(AddParams{PID: 101, Cmd: "worker", ParentID: api}); err != nil {
+		t.Fatal(err)
+	}
+	mustAdd(t, src, 200, "db")
+	procs := exported(t, src, ListFilter{})
+
+	dst := openRegistry(t, testOptions(t))
+	mustAdd(t, dst, 300, "api")
+	report, err := dst.Import(procs, ImportOptions{})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if got, want := actions(report), []string{"1>2:api-2:renamed", "2>3::added", "3>4:db:added"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("results = %v, want %v", got, want)
+	}
+	child, _ := dst.Get(3)
+	if child.ParentID != 2 {
+		t.Fatalf("parent of imported child = %d, want 2", child.ParentID)
+	}
+	for _, id := range []ProcID{2, 3, 4} {
+		p, _ := dst.Get(id)
+		if p.Alive || !p.Detached || p.Exit == nil || p.Exit.Reason != ExitReasonUnknown {
+			t.Fatalf("imported entry %d = %+v, want dead and detached", id, p)
+		}
+	}
+	if ps := dst.List(ListFilter{TagsAny: []string{"web"}}); len(ps) != 1 || ps[0].Name != "api-2" {
+		t.Fatalf("tag index after import: %+v", ps)
+	}
+	// A detached entry does not hold its PID.
+	if id, existed, err := dst.Add(AddParams{PID: 100, Cmd: "new"}); err != nil || existed || id != 5 {
+		t.Fatalf("Add of an imported PID = %d, %v, %v", id, existed, err)
+	}
+}
+
+func TestImportReplaceKeepsCounter(t *testing.T) {
+	src := openStoreRegistry(t, nil, 0)
+	mustAdd(t, src, 100, "a")
+	procs := exported(t, src, ListFilter{})
+
+	dst := historyRegistry(t, nil, HistoryRetention{MaxEntries: 10})
+	mustAdd(t, dst, 200, "a")
+	mustAdd(t, dst, 300, "b")
+	report, err := dst.Import(procs, ImportOptions{Replace: true})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if got, want := actions(report), []string{"1>3:a:added"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("results = %v, want %v", got, want)
+	}
+	if len(report.Removed) != 2 {
+		t.Fatalf("removed %d entries, want 2", len(report.Removed))
+	}
+	if got := seqs(history(t, dst, HistoryFilter{})); got != "2:2:import 1:1:import " {
+		t.Fatalf("history = %q", got)
+	}
+	if id := mustAdd(t, dst, 400, ""); id != 4 {
+		t.Fatalf("next id after replace = %d, want 4", id)
+	}
+
+	// Only Reset restarts the counter.
+	dst.Reset()
+	report, err = dst.Import(procs, ImportOptions{})
+	if err != nil {
+		t.Fatalf("Import after Reset: %v", err)
+	}
+	if got, want := actions(report), []string{"1>1:a:added"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("results after reset = %v, want %v", got, want)
+	}
+}
+
+func TestImportConflictPolicies(t *testing.T) {
+	src := openStoreRegistry(t, nil, 0)
+	mustAdd(t, src, 100, "a")
+	mustAdd(t, src, 200, "b")
+	procs := exported(t, src, ListFilter{})
+
+	for _, tc := range []struct {
+		policy NameConflict
+		want   []string
+		names  []string
+	}{
+		{ConflictSkip, []string{"1>0:a:skipped", "2>2:b:added"}, []string{"a", "b"}},
+		{ConflictReplace, []string{"1>2:a:replaced", "2>3:b:added"}, []string{"a", "b"}},
+	} {
+		t.Run(string(tc.policy), func(t *testing.T) {
+			dst := openStoreRegistry(t, nil, 0)
+			mustAdd(t, dst, 300, "a")
+			report, err := dst.Import(procs, ImportOptions{OnConflict: tc.policy})
+			if err != nil {
+				t.Fatalf("Import: %v", err)
+			}
+			if got := actions(report); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("results = %v, want %v", got, tc.want)
+			}
+			var names []string
+			for _, p := range dst.List(ListFilter{}) {
+				names = append(names, p.Name)
+			}
+			if !reflect.DeepEqual(names, tc.names) {
+				t.Fatalf("names = %v, want %v", names, tc.names)
+			}
+		})
+	}
+
+	t.Run("fail", func(t *testing.T) {
+		dst := openStoreRegistry(t, nil, 0)
+		mustAdd(t, dst, 300, "b")
+		if _, err := dst.Import(procs, ImportOptions{OnConflict: ConflictFail}); !errors.Is(err, ErrNameInUse) {
+			t.Fatalf("Import = %v, want ErrNameInUse", err)
+		}
+		if got := summary(dst); !reflect.DeepEqual(got, []string{"1:b:"}) {
+			t.Fatalf("failed import changed the registry: %v", got)
+		}
+	})
+}
+
+func TestImportAdopt(t *testing.T) {
+	src := openStoreRegistry(t, nil, 0)
+	mustAdd(t, src, 100, "running")
+	mustAdd(t, src, 200, "gone")
+	mustAdd(t, src, 300, "tracked")
+	procs := exported(t, src, ListFilter{})
+
+	dst := openStoreRegistry(t, nil, 0)
+	mustAdd(t, dst, 300, "")
+	report, err := dst.Import(procs, ImportOptions{Adopt: func(p Proc) bool { return p.PID != 200 }})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if got, want := actions(report), []string{"1>2:running:added", "2>3:gone:added", "3>0:tracked:skipped"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("results = %v, want %v", got, want)
+	}
+	if !report.Results[0].Alive || report.Results[1].Alive {
+		t.Fatalf("alive flags = %+v", report.Results)
+	}
+	if ps := dst.List(ListFilter{PIDs: []int{100}}); len(ps) != 1 || !ps[0].Alive || ps[0].Detached {
+		t.Fatalf("adopted entry = %+v", ps)
+	}
+	if id, existed, _ := dst.Add(AddParams{PID: 100, Cmd: "cmd"}); !existed || id != 2 {
+		t.Fatalf("adopted entry does not hold its PID: %d, %v", id, existed)
+	}
+}
+
+func TestImportDetachedPersists(t *testing.T) {
+	src := openStoreRegistry(t, nil, 0)
+	mustAdd(t, src, 100, "a")
+	procs := exported(t, src, ListFilter{})
+
+	opts := testOptions(t)
+	r := openRegistry(t, opts)
+	if _, err := r.Import(procs, ImportOptions{}); err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r2 := openRegistry(t, opts)
+	defer r2.Close()
+	if p, _ := r2.Get(1); !p.Detached {
+		t.Fatalf("reloaded entry = %+v, want detached", p)
+	}
+	if _, existed, _ := r2.Add(AddParams{PID: 100, Cmd: "cmd"}); existed {
+		t.Fatal("reloaded detached entry holds its PID")
+	}
+	if err := r2.Respawned(1, 500, 500, 0, ""); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := r2.Get(1); p.Detached || !p.Alive || p.PID != 500 {
+		t.Fatalf("respawned entry = %+v", p)
+	}
+}
+
+func TestParseExportMigrates(t *testing.T) {
+	procs, err := ParseExport([]byte(`{"version": 1, "procs": [
+	  {"id": 4, "pid": 100, "cmd": "old", "alive": false, "last_seen": "2024-01-02T03:04:05Z"}
+	]}`))
+	if err != nil {
+		t.Fatalf("ParseExport: %v", err)
+	}
+	if len(procs) != 1 || procs[0].Exit == nil || procs[0].Exit.Reason != ExitReasonUnknown {
+		t.Fatalf("migrated entries = %+v", procs)
+	}
+	if _, err := ParseExport([]byte(`{"version": 99, "procs": []}`)); err == nil {
+		t.Fatal("ParseExport accepted a newer version")
+	}
+}
+
+func TestParseNameConflict(t *testing.T) {
+	if got, err := ParseNameConflict(""); err != nil || got != ConflictRename {
+		t.Fatalf("ParseNameConflict(\"\") = %q, %v", got, err)
+	}
+	if _, err := ParseNameConflict("merge"); err == nil {
+		t.Fatal("ParseNameConflict accepted an unknown policy")
+	}
+}
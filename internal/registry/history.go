@@ -0,0 +1,196 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// RemovalReason records why an entry left the registry, or RemovalDied for
+// the final state of a process whose entry stays registered.
+type RemovalReason string
+
+const (
+	RemovalRm     RemovalReason = "rm"     // removed on request
+	RemovalKill   RemovalReason = "kill"   // removed after kill terminated it
+	RemovalReset  RemovalReason = "reset"  // wiped by Reset
+	RemovalGC     RemovalReason = "gc"     // dropped by the daemon, e.g. a child whose parent died
+	RemovalImport RemovalReason = "import" // replaced by an imported entry
+	RemovalDied   RemovalReason = "died"   // the process died; the entry was kept
+)
+
+// ParseRemovalReason validates a removal reason.
+func ParseRemovalReason(raw string) (RemovalReason, error) {
+	switch reason := RemovalReason(strings.ToLower(strings.TrimSpace(raw))); reason {
+	case RemovalRm, RemovalKill, RemovalReset, RemovalGC, RemovalImport, RemovalDied:
+		return reason, nil
+	default:
+		return "", fmt.Errorf("invalid removal reason %q (expected rm, kill, reset, gc, import, or died)", raw)
+	}
+}
+
+// Tombstone is the final state of a removed entry or a dead process, kept in
+// the history. Seq orders tombstones and identifies them in the store; IDs
+// cannot, since Reset restarts them and an entry is buried once when its
+// process dies and again when it is removed.
+type Tombstone struct {
+	Seq       uint64        `json:"seq"`
+	RemovedAt time.Time     `json:"removed_at"`
+	Reason    RemovalReason `json:"reason"`
+	Proc      Proc          `json:"proc"`
+}
+
+// HistoryRetention bounds the history. Tombstones older than MaxAge or
+// beyond the newest MaxEntries are dropped; MaxEntries 0 disables the
+// history and MaxAge 0 keeps tombstones regardless of age.
+type HistoryRetention struct {
+	MaxAge     time.Duration
+	MaxEntries int
+}
+
+// HistoryFilter selects tombstones. ListFilter is matched against the final
+// state of each entry.
+type HistoryFilter struct {
+	ListFilter
+	// Since keeps tombstones removed at or after it; zero keeps all.
+	Since time.Time
+	// Reasons keeps tombstones with any of these reasons; empty keeps all.
+	Reasons []RemovalReason
+	// Limit caps the result to the newest matches; 0 returns all.
+	Limit int
+}
+
+// History returns the matching tombstones, newest first. With a
+// HistoryStore they are read from the store.
+func (r *Registry) History(f HistoryFilter) ([]Tombstone, error) {
+	r.smu.Lock()
+	defer r.smu.Unlock()
+	r.mu.RLock()
+	if f.Since.Before(r.historyCutoff()) {
+		f.Since = r.historyCutoff()
+	}
+	if !r.lazyHistory {
+		defer r.mu.RUnlock()
+		return f.newest(r.history, nil), nil
+	}
+	hs, ok := r.store.(HistoryStore)
+	if !ok {
+		r.mu.RUnlock()
+		return nil, errors.New("registry store is closed")
+	}
+	if len(r.history) == 0 {
+		r.mu.RUnlock()
+		return nil, nil
+	}
+	// Tombstones dropped by the retention may still be stored, waiting for
+	// the next save; those buried since the last save are not stored yet.
+	// Both are newer than every stored one, so they come first.
+	from := r.history[0].Seq
+	out := f.newest(r.buried, func(t Tombstone) bool { return t.Seq >= from })
+	r.mu.RUnlock()
+	if f.Limit > 0 && len(out) == f.Limit {
+		return out, nil
+	}
+	sf := f
+	if f.Limit > 0 {
+		sf.Limit -= len(out)
+	}
+	stored, err := hs.History(sf, from)
+	if err != nil {
+		return nil, fmt.Errorf("read history: %w", err)
+	}
+	return append(out, stored...), nil
+}
+
+// newest returns the tombstones of ts, ordered by Seq, that match f and keep,
+// newest first.
+func (f HistoryFilter) newest(ts []Tombstone, keep func(Tombstone) bool) []Tombstone {
+	var out []Tombstone
+	for i := len(ts) - 1; i >= 0; i-- {
+		t := ts[i]
+		if t.RemovedAt.Before(f.Since) {
+			// Ordered by removal, so everything older is excluded too.
+			break
+		}
+		if keep != nil && !keep(t) || !f.match(t) {
+			continue
+		}
+		out = append(out, t)
+		if f.Limit > 0 && len(out) == f.Limit {
+			break
+		}
+	}
+	return out
+}
+
+// match reports whether t has one of the reasons of f and its final state
+// matches the list filter. Since is left to the caller, which can stop at the
+// first older tombstone.
+func (f HistoryFilter) match(t Tombstone) bool {
+	if len(f.Reasons) > 0 && !slices.Contains(f.Reasons, t.Reason) {
+		return false
+	}
+	return f.ListFilter.Match(t.Proc)
+}
+
+// PruneHistory drops tombstones that outlived the retention age and returns
+// how many were dropped. The daemon calls it periodically.
+func (r *Registry) PruneHistory() int {
+	r.mu.Lock()
+	n := r.pruneHistoryLocked()
+	r.mu.Unlock()
+	if n > 0 {
+		r.maybeSave()
+	}
+	return n
+}
+
+// buryLocked copies the final state of a removed entry or a dead process into
+// the history.
+// Caller holds r.mu for writing.
+func (r *Registry) buryLocked(p *Proc, reason RemovalReason) {
+	if r.retention.MaxEntries <= 0 {
+		return
+	}
+	r.nextSeq++
+	t := Tombstone{Seq: r.nextSeq, RemovedAt: now(), Reason: reason, Proc: *p}
+	if r.lazyHistory {
+		r.history = append(r.history, Tombstone{Seq: t.Seq, RemovedAt: t.RemovedAt})
+	} else {
+		r.history = append(r.history, t)
+	}
+	if r.dirty != nil {
+		r.buried = append(r.buried, t)
+	}
+	r.pruneHistoryLocked()
+}
+
+// pruneHistoryLocked applies the retention and records the dropped
+// tombstones for the store. Caller holds r.mu for writing.
+func (r *Registry) pruneHistoryLocked() int {
+	cutoff := r.historyCutoff()
+	n := 0
+	for n < len(r.history) && (len(r.history)-n > r.retention.MaxEntries || r.history[n].RemovedAt.Before(cutoff)) {
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	if r.dirty != nil {
+		for _, t := range r.history[:n] {
+			r.forgotten = append(r.forgotten, t.Seq)
+		}
+	}
+	r.history = slices.Delete(r.history, 0, n)
+	return n
+}
+
+// historyCutoff is the removal time before which tombstones are expired.
+func (r *Registry) historyCutoff() time.Time {
+	if r.retention.MaxAge <= 0 {
+		return time.Time{}
+	}
+	return now().Add(-r.retention.MaxAge)
+}
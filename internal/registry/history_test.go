@@ -0,0 +1,193 @@
+package registry
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func historyRegistry(t *testing.T, store Store, retention HistoryRetention) *Registry {
+	t.Helper()
+	r, err := Open(Options{Store: store, History: retention})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	return r
+}
+
+func history(t *testing.T, r *Registry, f HistoryFilter) []Tombstone {
+	t.Helper()
+	ts, err := r.History(f)
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	return ts
+}
+
+// seqs lists tombstones as "seq:id:reason" for comparisons.
+func seqs(ts []Tombstone) string {
+	out := ""
+	for _, t := range ts {
+		out += fmt.Sprintf("%d:%d:%s ", t.Seq, t.Proc.ID, t.Reason)
+	}
+	return out
+}
+
+func TestRemoveKeepsTombstones(t *testing.T) {
+	r := historyRegistry(t, nil, HistoryRetention{MaxEntries: 10})
+	a := mustAdd(t, r, 100, "a")
+	b := mustAdd(t, r, 200, "b")
+	if err := r.GroupAssign(b, []string{"prod"}); err != nil {
+		t.Fatal(err)
+	}
+	r.MarkExited(b, ExitStatus{At: time.Now(), Reason: ExitReasonSignaled, Signal: "SIGTERM"})
+	r.Remove(b, RemovalKill)
+	r.Remove(a, RemovalRm)
+	mustAdd(t, r, 300, "c")
+	r.Reset()
+
+	if got, want := seqs(history(t, r, HistoryFilter{})), "4:3:reset 3:1:rm 2:2:kill 1:2:died "; got != want {
+		t.Fatalf("history = %q, want %q", got, want)
+	}
+	prod := history(t, r, HistoryFilter{ListFilter: ListFilter{GroupsAny: []string{"prod"}}})
+	if len(prod) != 2 {
+		t.Fatalf("prod history = %+v", prod)
+	}
+	for _, ts := range prod {
+		if ts.Proc.Name != "b" || ts.Proc.Alive || ts.Proc.Exit == nil || ts.Proc.Exit.Signal != "SIGTERM" {
+			t.Fatalf("prod tombstone = %+v", ts)
+		}
+	}
+	if got := seqs(history(t, r, HistoryFilter{Reasons: []RemovalReason{RemovalRm, RemovalReset}, Limit: 1})); got != "4:3:reset " {
+		t.Fatalf("filtered history = %q", got)
+	}
+	if got := history(t, r, HistoryFilter{Since: time.Now().Add(time.Hour)}); len(got) != 0 {
+		t.Fatalf("future since returned %+v", got)
+	}
+}
+
+func TestHistoryRetention(t *testing.T) {
+	r := historyRegistry(t, nil, HistoryRetention{MaxEntries: 2})
+	for pid := 100; pid < 104; pid++ {
+		r.Remove(mustAdd(t, r, pid, ""), RemovalRm)
+	}
+	if got, want := seqs(history(t, r, HistoryFilter{})), "4:4:rm 3:3:rm "; got != want {
+		t.Fatalf("history = %q, want %q", got, want)
+	}
+
+	off := historyRegistry(t, nil, HistoryRetention{})
+	off.Remove(mustAdd(t, off, 100, ""), RemovalRm)
+	if got := history(t, off, HistoryFilter{}); len(got) != 0 {
+		t.Fatalf("disabled history kept %+v", got)
+	}
+}
+
+func TestHistoryPersists(t *testing.T) {
+	for name, newStore := range map[string]func(t *testing.T) func() Store{
+		"json": func(t *testing.T) func() Store {
+			opts := testOptions(t)
+			return func() Store { return NewJSONStore(opts) }
+		},
+		"bolt": func(t *testing.T) func() Store {
+			opts := testBoltOptions(t)
+			return func() Store { return NewBoltStore(opts) }
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := newStore(t)
+			retention := HistoryRetention{MaxEntries: 2}
+			r := historyRegistry(t, store(), retention)
+			for pid := 100; pid < 103; pid++ {
+				r.Remove(mustAdd(t, r, pid, ""), RemovalKill)
+			}
+			want := seqs(history(t, r, HistoryFilter{}))
+			if name == "bolt" {
+				r.Close()
+			}
+
+			// The JSON store replays its journal without Close.
+			r2 := historyRegistry(t, store(), retention)
+			if got := seqs(history(t, r2, HistoryFilter{})); got != want {
+				t.Fatalf("reloaded history = %q, want %q", got, want)
+			}
+			r2.Remove(mustAdd(t, r2, 200, ""), RemovalGC)
+			if err := r2.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+
+			r3 := historyRegistry(t, store(), HistoryRetention{MaxEntries: 1})
+			defer r3.Close()
+			if got := seqs(history(t, r3, HistoryFilter{})); got != "4:4:gc " {
+				t.Fatalf("history after shrinking retention = %q", got)
+			}
+		})
+	}
+}
+
+func TestHistoryExpiresByAge(t *testing.T) {
+	old := time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)
+	recent := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	path := writeSnapshot(t, fmt.Sprintf(`{"version": 3, "next_id": 3, "procs": [], "history": [
+	  {"seq": 1, "removed_at": %q, "reason": "rm", "proc": {"id": 1, "pid": 100, "cmd": "old"}},
+	  {"seq": 2, "removed_at": %q, "reason": "kill", "proc": {"id": 2, "pid": 200, "cmd": "recent"}}
+	]}`, old, recent))
+	r := historyRegistry(t, NewJSONStore(JSONStoreOptions{SnapshotPath: path, Sync: SyncNone}), HistoryRetention{MaxAge: 24 * time.Hour, MaxEntries: 10})
+	if got := seqs(history(t, r, HistoryFilter{})); got != "2:2:kill " {
+		t.Fatalf("history = %q", got)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := InspectSnapshot(path); err != nil || info.History != 1 {
+		t.Fatalf("expired tombstone persisted: %+v, %v", info, err)
+	}
+}
+
+func TestDeathKeepsTombstone(t *testing.T) {
+	r := historyRegistry(t, nil, HistoryRetention{MaxEntries: 10})
+	a := mustAdd(t, r, 100, "a")
+	b := mustAddIdentity(t, r, 200, 7, "")
+	r.MarkExited(a, ExitStatus{At: time.Now(), Reason: ExitReasonExited, Code: 3})
+	// Only the first observer of a death records it.
+	r.MarkExited(a, ExitStatus{At: time.Now(), Reason: ExitReasonUnknown, Code: -1})
+	r.SetAlive(b, false)
+	// A recycled PID retires the entry that held it.
+	r.SetAlive(b, true)
+	mustAddIdentity(t, r, 200, 42, "")
+
+	if got, want := seqs(history(t, r, HistoryFilter{})), "3:2:died 2:2:died 1:1:died "; got != want {
+		t.Fatalf("history = %q, want %q", got, want)
+	}
+	died := history(t, r, HistoryFilter{Reasons: []RemovalReason{RemovalDied}, ListFilter: ListFilter{IDs: []ProcID{a}}})
+	if len(died) != 1 || died[0].Proc.Exit == nil || died[0].Proc.Exit.Code != 3 {
+		t.Fatalf("tombstone of a = %+v", died)
+	}
+	if p, ok := r.Get(a); !ok || p.Alive {
+		t.Fatalf("dead entry was not kept: %+v", p)
+	}
+}
+
+func TestRespawnedClearsExit(t *testing.T) {
+	r := historyRegistry(t, nil, HistoryRetention{MaxEntries: 10})
+	id := mustAdd(t, r, 100, "a")
+	r.MarkExited(id, ExitStatus{At: time.Now(), Reason: ExitReasonExited, Code: 3})
+	if err := r.Respawned(id, 200, 200, 0, ""); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := r.Get(id); !p.Alive || p.Exit != nil || p.Restarts != 1 {
+		t.Fatalf("respawned entry = %+v", p)
+	}
+	died := history(t, r, HistoryFilter{Reasons: []RemovalReason{RemovalDied}})
+	if len(died) != 1 || died[0].Proc.Exit == nil || died[0].Proc.Exit.Code != 3 {
+		t.Fatalf("previous exit not in the history: %+v", died)
+	}
+}
+
+func TestParseRemovalReason(t *testing.T) {
+	if got, err := ParseRemovalReason(" Kill "); err != nil || got != RemovalKill {
+		t.Fatalf("ParseRemovalReason = %q, %v", got, err)
+	}
+	if _, err := ParseRemovalReason("exit"); err == nil {
+		t.Fatal("ParseRemovalReason accepted an unknown reason")
+	}
+}
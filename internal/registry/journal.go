@@ -0,0 +1,434 @@
+package registry
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JSONStore keeps the registry in a snapshot plus a journal. The snapshot
+// holds the registry as of the last compaction; the journal is an
+// append-only file of JSON records, one per line, for every change since.
+// Records are Mutations, which carry full entry states, so replaying one
+// twice is harmless: compaction writes the snapshot before truncating the
+// journal, and a crash in between only leaves records the snapshot already
+// contains.
+
+// journalHeader starts every journal and names the snapshot schema version
+// its entries are written in. The other records use the MutationOp names.
+const journalHeader = "header"
+
+type journalRecord struct {
+	Op      string `json:"op"`
+	Version int    `json:"version,omitempty"` // header only
+	NextID  uint64 `json:"next_id,omitempty"`
+	ID      ProcID `json:"id,omitempty"` // delete only
+	Proc    *Proc  `json:"proc,omitempty"`
+	// Tombstone (bury) and Seq (forget) change the history.
+	Tombstone *Tombstone `json:"tombstone,omitempty"`
+	Seq       uint64     `json:"seq,omitempty"`
+}
+
+// JournalInfo summarises a journal file.
+type JournalInfo struct {
+	Path    string
+	Version int // 0 if the journal is empty
+	Records int // records after the header
+	// Torn is set when the journal ends in an incomplete record, e.g. after
+	// a crash mid-write; the record is dropped on the next load.
+	Torn bool
+}
+
+// InspectJournal reads the journal at path without replaying it.
+func InspectJournal(path string) (JournalInfo, error) {
+	info := JournalInfo{Path: path}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return info, err
+	}
+	version, records, torn, err := parseJournal(b)
+	if err != nil {
+		return info, err
+	}
+	info.Version, info.Records, info.Torn = version, len(records), torn
+	return info, nil
+}
+
+// parseJournal decodes the records of a journal without a schema, so they can
+// be replayed onto a snapshot document before it is migrated. A last line that
+// does not decode is reported as torn, since writes append whole records and
+// only a crash can cut one short; damage before it is an error.
+func parseJournal(b []byte) (int, []map[string]any, bool, error) {
+	var (
+		version int
+		records []map[string]any
+	)
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	sc.Buffer(make([]byte, 0, 64<<10), len(b)+1)
+	for line := 1; sc.Scan(); line++ {
+		var rec map[string]any
+		dec := json.NewDecoder(bytes.NewReader(sc.Bytes()))
+		dec.UseNumber()
+		if err := dec.Decode(&rec); err != nil || rec == nil {
+			if !sc.Scan() {
+				return version, records, true, nil
+			}
+			return 0, nil, false, fmt.Errorf("journal line %d is corrupt", line)
+		}
+		if line == 1 {
+			if rec["op"] != journalHeader {
+				return 0, nil, false, errors.New("journal has no header")
+			}
+			n, _ := rec["version"].(json.Number)
+			v, err := n.Int64()
+			if err != nil || v < 1 {
+				return 0, nil, false, fmt.Errorf("invalid journal version %q", n)
+			}
+			version = int(v)
+			continue
+		}
+		records = append(records, rec)
+	}
+	return version, records, false, sc.Err()
+}
+
+// replayJournal applies journal records to a snapshot document of the same
+// schema version.
+func replayJournal(doc snapshotDoc, records []map[string]any) error {
+	procs, err := doc.procs()
+	if err != nil {
+		return err
+	}
+	var order []string
+	byID := make(map[string]map[string]any, len(procs))
+	put := func(p map[string]any) {
+		id := fmt.Sprint(p["id"])
+		if _, ok := byID[id]; !ok {
+			order = append(order, id)
+		}
+		byID[id] = p
+	}
+	for _, p := range procs {
+		put(p)
+	}
+	history, _ := doc["history"].([]any)
+	for i, rec := range records {
+		switch rec["op"] {
+		case string(MutationPut):
+			p, ok := rec["proc"].(map[string]any)
+			if !ok {
+				return fmt.Errorf("journal record %d has no entry", i+1)
+			}
+			put(p)
+		case string(MutationDelete):
+			delete(byID, fmt.Sprint(rec["id"]))
+		case string(MutationReset):
+			clear(byID)
+		case string(MutationBury):
+			t, ok := rec["tombstone"].(map[string]any)
+			if !ok {
+				return fmt.Errorf("journal record %d has no tombstone", i+1)
+			}
+			// Unlike entry states, tombstones are appended, so skip one
+			// the snapshot already holds.
+			seq := fmt.Sprint(t["seq"])
+			if !slices.ContainsFunc(history, func(h any) bool {
+				m, _ := h.(map[string]any)
+				return fmt.Sprint(m["seq"]) == seq
+			}) {
+				history = append(history, t)
+			}
+		case string(MutationForget):
+			seq := fmt.Sprint(rec["seq"])
+			history = slices.DeleteFunc(history, func(t any) bool {
+				m, _ := t.(map[string]any)
+				return fmt.Sprint(m["seq"]) == seq
+			})
+		default:
+			return fmt.Errorf("journal record %d has unknown op %v", i+1, rec["op"])
+		}
+		if n, ok := rec["next_id"]; ok {
+			doc["next_id"] = n
+		}
+	}
+	out := make([]any, 0, len(byID))
+	for _, id := range order {
+		if p, ok := byID[id]; ok {
+			out = append(out, p)
+			delete(byID, id)
+		}
+	}
+	doc["procs"] = out
+	if history != nil {
+		doc["history"] = history
+	}
+	return nil
+}
+
+// journal is the open journal file. It is guarded by JSONStore.mu.
+type journal struct {
+	f        *os.File
+	mode     SyncMode
+	unsynced bool // written since the last fsync (SyncBatch)
+}
+
+func openJournal(path string, mode SyncMode) (*journal, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &journal{f: f, mode: mode}, nil
+}
+
+func (j *journal) append(b []byte) error {
+	info, err := j.f.Stat()
+	if err != nil {
+		return err
+	}
+	if _, err := j.f.Write(b); err != nil {
+		return errors.Join(err, j.f.Truncate(info.Size()))
+	}
+	switch j.mode {
+	case SyncAlways:
+		return j.f.Sync()
+	case SyncBatch:
+		j.unsynced = true
+	}
+	return nil
+}
+
+func (j *journal) sync() error {
+	if !j.unsynced {
+		return nil
+	}
+	j.unsynced = false
+	return j.f.Sync()
+}
+
+// truncate empties the journal after a compaction and writes a new header.
+func (j *journal) truncate() error {
+	if err := j.f.Truncate(0); err != nil {
+		return err
+	}
+	j.unsynced = false
+	b, err := json.Marshal(journalRecord{Op: journalHeader, Version: snapshotVersion})
+	if err != nil {
+		return err
+	}
+	if _, err := j.f.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	if j.mode == SyncNone {
+		return nil
+	}
+	return j.f.Sync()
+}
+
+func (j *journal) close() error {
+	err := j.sync()
+	return errors.Join(err, j.f.Close())
+}
+
+// JSONStore persists the registry as a JSON snapshot plus the journal.
+type JSONStore struct {
+	opts JSONStoreOptions
+
+	// mu guards journal against the batch sync loop.
+	mu      sync.Mutex
+	journal *journal
+	stop    chan struct{}
+}
+
+// JSONStoreOptions configures a JSONStore.
+type JSONStoreOptions struct {
+	SnapshotPath string
+	// JournalPath defaults to SnapshotPath with a .journal extension.
+	JournalPath string
+	// Sync selects the durability of journal writes (default SyncBatch).
+	Sync SyncMode
+	// SyncInterval is the flush period of SyncBatch (default 1s).
+	SyncInterval time.Duration
+}
+
+// NewJSONStore returns a store for the given files; nothing is read until
+// Load.
+func NewJSONStore(opts JSONStoreOptions) *JSONStore {
+	if opts.JournalPath == "" {
+		opts.JournalPath = strings.TrimSuffix(opts.SnapshotPath, filepath.Ext(opts.SnapshotPath)) + ".journal"
+	}
+	if opts.Sync == "" {
+		opts.Sync = SyncBatch
+	}
+	if opts.SyncInterval <= 0 {
+		opts.SyncInterval = defaultSyncInterval
+	}
+	return &JSONStore{opts: opts}
+}
+
+// Load restores the state from the snapshot and the journal and opens the
+// journal for appending. Snapshots from older versions are backed up (with
+// their journal) and migrated; the migrated state, like any replayed
+// journal, is compacted straight away.
+func (s *JSONStore) Load() (State, error) {
+	snapPath, journalPath := s.opts.SnapshotPath, s.opts.JournalPath
+
+	snapBytes, err := os.ReadFile(snapPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return State{}, err
+	}
+	doc := snapshotDoc{"version": json.Number(fmt.Sprint(snapshotVersion)), "next_id": json.Number("1")}
+	if snapBytes != nil {
+		if doc, err = decodeSnapshotDoc(snapBytes); err != nil {
+			return State{}, fmt.Errorf("snapshot %s: %w", snapPath, err)
+		}
+	}
+	from, err := snapshotDocVersion(doc)
+	if err != nil {
+		return State{}, fmt.Errorf("snapshot %s: %w", snapPath, err)
+	}
+
+	journalBytes, err := os.ReadFile(journalPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return State{}, err
+	}
+	jv, records, torn, err := parseJournal(journalBytes)
+	if err != nil {
+		return State{}, fmt.Errorf("journal %s: %w", journalPath, err)
+	}
+	compact := snapBytes == nil || torn || len(records) > 0 || from != snapshotVersion
+	switch {
+	case jv == 0:
+	case jv > snapshotVersion:
+		return State{}, fmt.Errorf("journal %s: version %d is newer than this build supports (%d)", journalPath, jv, snapshotVersion)
+	case jv < from:
+		// Written before the snapshot was migrated, which only happens
+		// after every record was compacted into it.
+		log.Printf("registry: ignoring version %d journal older than the version %d snapshot", jv, from)
+		compact = true
+	case jv > from:
+		return State{}, fmt.Errorf("journal %s: version %d does not match snapshot version %d", journalPath, jv, from)
+	default:
+		if err := replayJournal(doc, records); err != nil {
+			return State{}, fmt.Errorf("journal %s: %w", journalPath, err)
+		}
+	}
+	if torn {
+		log.Printf("registry: dropped an incomplete record at the end of %s", journalPath)
+	}
+	snap, err := migrateSnapshotDoc(doc, from)
+	if err != nil {
+		return State{}, fmt.Errorf("snapshot %s: %w", snapPath, err)
+	}
+	if from != snapshotVersion {
+		// Keep the originals around before the migrated form replaces them.
+		if snapBytes != nil {
+			if err := os.WriteFile(fmt.Sprintf("%s.v%d.bak", snapPath, from), snapBytes, 0o600); err != nil {
+				return State{}, fmt.Errorf("back up snapshot before migration: %w", err)
+			}
+		}
+		if len(records) > 0 {
+			if err := os.WriteFile(fmt.Sprintf("%s.v%d.bak", journalPath, from), journalBytes, 0o600); err != nil {
+				return State{}, fmt.Errorf("back up journal before migration: %w", err)
+			}
+		}
+	}
+	st := snap.state()
+
+	j, err := openJournal(journalPath, s.opts.Sync)
+	if err != nil {
+		return State{}, err
+	}
+	s.journal = j
+	if compact {
+		err = s.Compact(st)
+	} else if len(journalBytes) == 0 {
+		err = j.truncate()
+	}
+	if err != nil {
+		j.f.Close()
+		s.journal = nil
+		return State{}, err
+	}
+	if s.opts.Sync == SyncBatch {
+		s.stop = make(chan struct{})
+		go s.syncLoop(s.opts.SyncInterval, s.stop)
+	}
+	return st, nil
+}
+
+// Apply appends one journal record per change. A failed write is cut off
+// again so the journal never holds a partial record.
+func (s *JSONStore) Apply(changes []Mutation) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, m := range changes {
+		rec := journalRecord{Op: string(m.Op), NextID: uint64(m.NextID), Proc: m.Proc, Tombstone: m.Tombstone, Seq: m.Seq}
+		if m.Op == MutationDelete {
+			rec.ID = m.ID
+		}
+		if err := enc.Encode(rec); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.journal.append(buf.Bytes())
+}
+
+// Compact writes state as the new snapshot and truncates the journal.
+func (s *JSONStore) Compact(state State) error {
+	if err := saveSnapshot(s.opts.SnapshotPath, state, s.opts.Sync != SyncNone); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.journal.truncate()
+}
+
+// Close stops the sync loop and closes the journal.
+func (s *JSONStore) Close() error {
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.journal == nil {
+		return nil
+	}
+	err := s.journal.close()
+	s.journal = nil
+	return err
+}
+
+// syncLoop flushes batched journal writes until stop is closed.
+func (s *JSONStore) syncLoop(interval time.Duration, stop <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+			s.mu.Lock()
+			if s.journal != nil {
+				if err := s.journal.sync(); err != nil {
+					log.Printf("registry journal sync failed: %v", err)
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
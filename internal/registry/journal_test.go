@@ -0,0 +1,237 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testOptions(t *testing.T) JSONStoreOptions {
+	t.Helper()
+	dir := t.TempDir()
+	return JSONStoreOptions{
+		SnapshotPath: filepath.Join(dir, "goproc.snapshot.json"),
+		JournalPath:  filepath.Join(dir, "goproc.journal"),
+		Sync:         SyncNone,
+	}
+}
+
+func openRegistry(t *testing.T, opts JSONStoreOptions) *Registry {
+	t.Helper()
+	return openStoreRegistry(t, NewJSONStore(opts), 0)
+}
+
+func openStoreRegistry(t *testing.T, store Store, compactEvery int) *Registry {
+	t.Helper()
+	r, err := Open(Options{Store: store, CompactEvery: compactEvery})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	return r
+}
+
+// summary lists entries as "id:name:tags" for comparisons.
+func summary(r *Registry) []string {
+	var out []string
+	for _, p := range r.List(ListFilter{}) {
+		out = append(out, fmt.Sprintf("%d:%s:%s", p.ID, p.Name, strings.Join(p.Meta.Tags, ",")))
+	}
+	return out
+}
+
+func mustAdd(t *testing.T, r *Registry, pid int, name string) ProcID {
+	t.Helper()
+	id, _, err := r.Add(AddParams{PID: pid, Cmd: "cmd", Name: name})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	return id
+}
+
+func TestJournalReplaysWithoutClose(t *testing.T) {
+	opts := testOptions(t)
+	r := openRegistry(t, opts)
+	a := mustAdd(t, r, 100, "a")
+	b := mustAdd(t, r, 200, "b")
+	mustAdd(t, r, 300, "c")
+	if err := r.Tag(a, []string{"web", "api"}); err != nil {
+		t.Fatal(err)
+	}
+	r.Remove(b, RemovalRm)
+	if _, err := r.SetName(a, "renamed"); err != nil {
+		t.Fatal(err)
+	}
+	want := summary(r)
+
+	info, err := InspectJournal(opts.JournalPath)
+	if err != nil || info.Records == 0 {
+		t.Fatalf("journal = %+v, %v", info, err)
+	}
+
+	// Reopen without Close, as after a crash.
+	r2 := openRegistry(t, opts)
+	defer r2.Close()
+	if got := summary(r2); !reflect.DeepEqual(got, want) {
+		t.Fatalf("replayed %v, want %v", got, want)
+	}
+	if id := mustAdd(t, r2, 400, "d"); id != 4 {
+		t.Fatalf("next id after replay = %d, want 4", id)
+	}
+}
+
+func TestJournalCompacts(t *testing.T) {
+	opts := testOptions(t)
+	r := openStoreRegistry(t, NewJSONStore(opts), 3)
+	defer r.Close()
+	for pid := 100; pid < 105; pid++ {
+		mustAdd(t, r, pid, "")
+	}
+	info, err := InspectJournal(opts.JournalPath)
+	if err != nil || info.Records >= 3 {
+		t.Fatalf("journal not compacted: %+v, %v", info, err)
+	}
+	snap, err := InspectSnapshot(opts.SnapshotPath)
+	if err != nil || snap.Procs+info.Records < 5 || snap.Procs < 3 {
+		t.Fatalf("snapshot = %+v, %v", snap, err)
+	}
+}
+
+func TestJournalDropsTornRecord(t *testing.T) {
+	opts := testOptions(t)
+	r := openRegistry(t, opts)
+	mustAdd(t, r, 100, "a")
+	want := summary(r)
+
+	f, err := os.OpenFile(opts.JournalPath, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"op":"put","next_id":3,"proc":{"id":2,"pi`)
+	f.Close()
+
+	r2 := openRegistry(t, opts)
+	defer r2.Close()
+	if got := summary(r2); !reflect.DeepEqual(got, want) {
+		t.Fatalf("replayed %v, want %v", got, want)
+	}
+	if info, err := InspectJournal(opts.JournalPath); err != nil || info.Torn || info.Records != 0 {
+		t.Fatalf("torn journal not compacted: %+v, %v", info, err)
+	}
+}
+
+func TestJournalReplaysBuryOnce(t *testing.T) {
+	opts := testOptions(t)
+	retention := HistoryRetention{MaxEntries: 10}
+	r := historyRegistry(t, NewJSONStore(opts), retention)
+	r.Remove(mustAdd(t, r, 100, "a"), RemovalRm)
+	journal, err := os.ReadFile(opts.JournalPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A crash between writing the snapshot and truncating the journal
+	// leaves the bury record behind.
+	if err := os.WriteFile(opts.JournalPath, journal, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	r2 := historyRegistry(t, NewJSONStore(opts), retention)
+	defer r2.Close()
+	if got := seqs(history(t, r2, HistoryFilter{})); got != "1:1:rm " {
+		t.Fatalf("replayed history = %q", got)
+	}
+}
+
+func TestJournalReplaysReset(t *testing.T) {
+	opts := testOptions(t)
+	r := openRegistry(t, opts)
+	mustAdd(t, r, 100, "a")
+	mustAdd(t, r, 200, "b")
+	r.Reset()
+	mustAdd(t, r, 300, "c")
+
+	r2 := openRegistry(t, opts)
+	defer r2.Close()
+	ps := r2.List(ListFilter{})
+	if len(ps) != 1 || ps[0].ID != 1 || ps[0].Name != "c" {
+		t.Fatalf("after reset replay: %+v", ps)
+	}
+}
+
+func TestCloseCompactsJournal(t *testing.T) {
+	opts := testOptions(t)
+	opts.Sync = SyncBatch
+	r := openRegistry(t, opts)
+	mustAdd(t, r, 100, "a")
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if info, err := InspectJournal(opts.JournalPath); err != nil || info.Records != 0 || info.Version != snapshotVersion {
+		t.Fatalf("journal after Close = %+v, %v", info, err)
+	}
+	if snap, err := InspectSnapshot(opts.SnapshotPath); err != nil || snap.Procs != 1 {
+		t.Fatalf("snapshot after Close = %+v, %v", snap, err)
+	}
+	// Changes after Close stay in memory.
+	mustAdd(t, r, 200, "b")
+	if snap, _ := InspectSnapshot(opts.SnapshotPath); snap.Procs != 1 {
+		t.Fatalf("closed registry persisted a change: %+v", snap)
+	}
+}
+
+// flakyStore fails Apply or Compact on demand.
+type flakyStore struct {
+	Store
+	failApply, failCompact bool
+}
+
+func (s *flakyStore) Apply(changes []Mutation) error {
+	if s.failApply {
+		return errors.New("apply failed")
+	}
+	return s.Store.Apply(changes)
+}
+
+func (s *flakyStore) Compact(st State) error {
+	if s.failCompact {
+		return errors.New("compact failed")
+	}
+	return s.Store.Compact(st)
+}
+
+func TestFailedCompactionKeepsPendingChanges(t *testing.T) {
+	opts := testOptions(t)
+	store := &flakyStore{Store: NewJSONStore(opts), failApply: true}
+	r := openStoreRegistry(t, store, 0)
+	mustAdd(t, r, 100, "a")
+	want := summary(r)
+
+	// The add is still pending when Close cannot write the snapshot; it
+	// must reach the journal instead of being dropped.
+	store.failApply, store.failCompact = false, true
+	if err := r.Close(); err == nil {
+		t.Fatal("Close hid the compaction failure")
+	}
+	r2 := openRegistry(t, opts)
+	defer r2.Close()
+	if got := summary(r2); !reflect.DeepEqual(got, want) {
+		t.Fatalf("reopened %v, want %v", got, want)
+	}
+}
+
+func TestParseSyncMode(t *testing.T) {
+	for raw, want := range map[string]SyncMode{"": SyncBatch, "always": SyncAlways, " Batch ": SyncBatch, "none": SyncNone} {
+		if got, err := ParseSyncMode(raw); err != nil || got != want {
+			t.Errorf("ParseSyncMode(%q) = %q, %v", raw, got, err)
+		}
+	}
+	if _, err := ParseSyncMode("sometimes"); err == nil {
+		t.Error("ParseSyncMode accepted an unknown mode")
+	}
+}
@@ -1,6 +1,14 @@
 package registry
 
-import "time"
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"goproc/internal/query"
+)
 
 // ProcID is an internal stable identifier for tracked processes.
 type ProcID uint64
@@ -9,6 +17,9 @@ type ProcID uint64
 type ProcMeta struct {
 	Tags   []string `json:"tags,omitempty"`   // arbitrary labels
 	Groups []string `json:"groups,omitempty"` // used for bulk-ops (kill/list)
+	// Annotations are key/value pairs such as owner=alice. The map is never
+	// mutated once stored, so copies of a Proc may share it.
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 // Proc holds a tracked process entry. It is immutable outside registry methods.
@@ -22,6 +33,178 @@ type Proc struct {
 	AddedAt  time.Time `json:"added_at"`
 	LastSeen time.Time `json:"last_seen"`
 	Meta     ProcMeta  `json:"meta"`
+
+	// Spawn is set when the daemon launched the process itself.
+	Spawn    *SpawnSpec     `json:"spawn,omitempty"`
+	Restart  *RestartPolicy `json:"restart,omitempty"`
+	Restarts int            `json:"restarts,omitempty"`
+
+	// StartTicks is the kernel start time of the process (clock ticks after
+	// boot, /proc/<pid>/stat field 22) and BootID the boot it belongs to.
+	// Together with PID they identify the process even if the PID is reused.
+	// Zero values mean unknown (entries from older snapshots).
+	StartTicks uint64 `json:"start_ticks,omitempty"`
+	BootID     string `json:"boot_id,omitempty"`
+
+	// Exit describes how the process last terminated; nil while it has
+	// never been seen to exit. It is kept across supervisor restarts.
+	Exit *ExitStatus `json:"exit,omitempty"`
+
+	// ParentID links an entry that was registered automatically as a
+	// descendant of a TrackChildren entry to the entry of its parent.
+	ParentID ProcID `json:"parent_id,omitempty"`
+	// TrackChildren registers descendants of the process as child entries
+	// when the liveness loop discovers them.
+	TrackChildren bool `json:"track_children,omitempty"`
+
+	// Cgroup is set when the daemon runs the process in its own cgroup v2
+	// leaf; restarts reuse the leaf and its limits.
+	Cgroup *CgroupSpec `json:"cgroup,omitempty"`
+
+	// Manifest is set on entries started by `apply`; prune only ever
+	// removes entries of the manifest being applied.
+	Manifest *ManifestSource `json:"manifest,omitempty"`
+
+	// Detached is set on entries imported without their process (see
+	// Import): the PID belongs to another machine or an earlier run, so the
+	// entry is never probed, signalled or revived through it. Respawning the
+	// entry attaches it to the new process.
+	Detached bool `json:"detached,omitempty"`
+
+	// Metrics is the latest resource sample; it is not persisted.
+	Metrics *Metrics `json:"-"`
+	// Children is the process tree below PID as of the last liveness tick.
+	// Like Metrics it is volatile and never mutated once stored.
+	Children []ProcNode `json:"-"`
+}
+
+// ProcNode is one descendant in a process tree, discovered through the ppid
+// links in /proc.
+type ProcNode struct {
+	PID      int
+	Cmd      string
+	ID       ProcID // registry entry for PID, or 0 if it is not tracked
+	Children []ProcNode
+}
+
+// Metrics is resource usage sampled from /proc by the liveness loop.
+type Metrics struct {
+	CPUPercent float64 // since the previous sample; 100 = one core
+	RSS        uint64  // bytes
+	VMS        uint64  // bytes
+	Threads    int
+	FDs        int
+	ReadBytes  uint64
+	WriteBytes uint64
+	SampledAt  time.Time
+}
+
+// Reused reports whether a process with the given start time and boot ID is
+// a different process from the one this entry recorded under the same PID.
+// Unknown values on either side are treated as a match.
+func (p Proc) Reused(startTicks uint64, bootID string) bool {
+	if p.BootID != "" && bootID != "" && p.BootID != bootID {
+		return true
+	}
+	return p.StartTicks != 0 && startTicks != 0 && p.StartTicks != startTicks
+}
+
+// ExitReason classifies how a process terminated.
+type ExitReason string
+
+const (
+	ExitReasonExited   ExitReason = "exited"   // normal exit; Code is valid
+	ExitReasonSignaled ExitReason = "signaled" // terminated by Signal
+	ExitReasonUnknown  ExitReason = "unknown"  // died while unobserved (no wait status available)
+)
+
+// ExitStatus records the termination of a process.
+type ExitStatus struct {
+	At         time.Time  `json:"at"`
+	Reason     ExitReason `json:"reason"`
+	Code       int        `json:"code"`             // exit code; -1 unless Reason is exited
+	Signal     string     `json:"signal,omitempty"` // e.g. "SIGSEGV"
+	CoreDumped bool       `json:"core_dumped,omitempty"`
+}
+
+// Success reports whether the process exited with code 0.
+func (e ExitStatus) Success() bool {
+	return e.Reason == ExitReasonExited && e.Code == 0
+}
+
+// SpawnSpec records how a daemon-owned process was started so it can be relaunched.
+type SpawnSpec struct {
+	Argv []string `json:"argv"`
+	Env  []string `json:"env,omitempty"`
+	Dir  string   `json:"dir,omitempty"`
+}
+
+// CgroupSpec is the cgroup v2 leaf of a spawned process and the limits
+// applied to it. Zero limits leave a resource unlimited.
+type CgroupSpec struct {
+	Path      string  `json:"path"`
+	MemoryMax uint64  `json:"memory_max,omitempty"` // bytes
+	CPUMax    float64 `json:"cpu_max,omitempty"`    // CPUs
+	PIDsMax   int     `json:"pids_max,omitempty"`
+}
+
+// ManifestSource records which manifest declared an entry.
+type ManifestSource struct {
+	Name string `json:"name"`
+	// Env is the environment as declared, before it was added to the
+	// daemon's; changes to it are detected against this copy.
+	Env []string `json:"env,omitempty"`
+}
+
+// RestartMode selects when a spawned process is relaunched after it exits.
+type RestartMode string
+
+const (
+	RestartNever     RestartMode = "never"
+	RestartOnFailure RestartMode = "on-failure"
+	RestartAlways    RestartMode = "always"
+)
+
+// ParseRestartMode validates a user-supplied restart mode; empty means never.
+func ParseRestartMode(raw string) (RestartMode, error) {
+	switch mode := RestartMode(strings.TrimSpace(raw)); mode {
+	case "", RestartNever:
+		return RestartNever, nil
+	case RestartOnFailure, RestartAlways:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid restart policy %q (expected never, on-failure, or always)", raw)
+	}
+}
+
+// RestartPolicy describes supervision of a spawned process.
+type RestartPolicy struct {
+	Mode        RestartMode   `json:"mode"`
+	MaxRestarts int           `json:"max_restarts,omitempty"` // 0 = unlimited
+	Window      time.Duration `json:"window,omitempty"`       // sliding window for MaxRestarts
+	Backoff     time.Duration `json:"backoff,omitempty"`      // initial restart delay
+	MaxBackoff  time.Duration `json:"max_backoff,omitempty"`  // cap for the exponential delay
+}
+
+// AddParams describes a new registry entry.
+type AddParams struct {
+	PID         int
+	PGID        int
+	Cmd         string
+	Name        string
+	Tags        []string
+	Groups      []string
+	Annotations map[string]string
+	Spawn       *SpawnSpec
+	Restart     *RestartPolicy
+
+	StartTicks uint64 // kernel start time; 0 if unknown
+	BootID     string
+
+	ParentID      ProcID // set for entries registered as a descendant of another
+	TrackChildren bool
+	Cgroup        *CgroupSpec
+	Manifest      *ManifestSource
 }
 
 // ListFilter allows narrowing the registry query.
@@ -35,4 +218,103 @@ type ListFilter struct {
 	IDs        []ProcID
 	Names      []string
 	TextSearch string // naive substring search over Cmd
+	// Annotations must all match (AND).
+	Annotations []AnnotationSelector
+	// Query is a parsed query expression; nil matches everything.
+	Query query.Expr
+
+	// Compiled patterns; every pattern in a slice must match. Callers
+	// compile them once per request (see pattern.Compile).
+	NamePatterns  []*regexp.Regexp // matched against the name; unnamed entries never match
+	TagPatterns   []*regexp.Regexp // some tag must match
+	GroupPatterns []*regexp.Regexp // some group must match
+	CmdPatterns   []*regexp.Regexp // matched against the command
+}
+
+// Match reports whether a single entry satisfies the filter, using the same
+// semantics as List. It is used where evaluating against the indexes is not
+// possible, such as filtering change events.
+func (f ListFilter) Match(p Proc) bool {
+	if len(f.IDs) > 0 && !contains(f.IDs, p.ID) {
+		return false
+	}
+	if len(f.PIDs) > 0 && !contains(f.PIDs, p.PID) {
+		return false
+	}
+	if len(f.Names) > 0 && (p.Name == "" || !contains(f.Names, p.Name)) {
+		return false
+	}
+	tags := toSet(p.Meta.Tags)
+	if len(f.TagsAny) > 0 && !hasAny(tags, f.TagsAny) {
+		return false
+	}
+	if !hasAll(tags, f.TagsAll) {
+		return false
+	}
+	groups := toSet(p.Meta.Groups)
+	if len(f.GroupsAny) > 0 && !hasAny(groups, f.GroupsAny) {
+		return false
+	}
+	if !hasAll(groups, f.GroupsAll) {
+		return false
+	}
+	if f.AliveOnly && !p.Alive {
+		return false
+	}
+	if s := strings.TrimSpace(f.TextSearch); s != "" && !strings.Contains(p.Cmd, s) {
+		return false
+	}
+	for _, sel := range f.Annotations {
+		if !sel.Match(p.Meta.Annotations) {
+			return false
+		}
+	}
+	for _, re := range f.NamePatterns {
+		if p.Name == "" || !re.MatchString(p.Name) {
+			return false
+		}
+	}
+	for _, re := range f.TagPatterns {
+		if !slices.ContainsFunc(p.Meta.Tags, re.MatchString) {
+			return false
+		}
+	}
+	for _, re := range f.GroupPatterns {
+		if !slices.ContainsFunc(p.Meta.Groups, re.MatchString) {
+			return false
+		}
+	}
+	for _, re := range f.CmdPatterns {
+		if !re.MatchString(p.Cmd) {
+			return false
+		}
+	}
+	return matchQuery(f.Query, &p, time.Now())
+}
+
+func contains[T comparable](xs []T, v T) bool {
+	for _, x := range xs {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAny(set map[string]struct{}, want []string) bool {
+	for _, w := range want {
+		if _, ok := set[w]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAll(set map[string]struct{}, want []string) bool {
+	for _, w := range want {
+		if _, ok := set[w]; !ok {
+			return false
+		}
+	}
+	return true
 }
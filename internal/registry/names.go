@@ -8,7 +8,8 @@ import (
 
 const maxNameLen = 64
 
-func normalizeName(raw string) (string, error) {
+// NormalizeName trims and validates an entry name; empty means unnamed.
+func NormalizeName(raw string) (string, error) {
 	name := strings.TrimSpace(raw)
 	if name == "" {
 		return "", nil
@@ -0,0 +1,179 @@
+package registry
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SortKey selects the order of ListPage results.
+type SortKey string
+
+const (
+	SortID       SortKey = "id"
+	SortPID      SortKey = "pid"
+	SortName     SortKey = "name"
+	SortAddedAt  SortKey = "added_at"
+	SortLastSeen SortKey = "last_seen"
+	SortCPU      SortKey = "cpu"
+	SortRSS      SortKey = "rss"
+)
+
+// ParseSortKey validates a user-supplied sort key; empty means SortID.
+func ParseSortKey(raw string) (SortKey, error) {
+	switch key := SortKey(strings.ToLower(strings.TrimSpace(raw))); key {
+	case "":
+		return SortID, nil
+	case SortID, SortPID, SortName, SortAddedAt, SortLastSeen, SortCPU, SortRSS:
+		return key, nil
+	default:
+		return "", fmt.Errorf("invalid sort key %q (expected id, pid, name, added_at, last_seen, cpu, or rss)", raw)
+	}
+}
+
+// ErrInvalidPageToken is returned for page tokens that cannot be decoded or
+// were issued for a different sort order.
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// PageRequest selects one page of sorted results.
+type PageRequest struct {
+	Sort  SortKey
+	Desc  bool
+	Limit int    // 0 returns every remaining entry
+	Token string // NextToken of the previous page; empty starts at the top
+}
+
+// PageResult is one page of a listing.
+type PageResult struct {
+	Procs     []Proc
+	Total     int    // entries matching the filter, across all pages
+	NextToken string // empty on the last page
+}
+
+// pageCursor is the decoded page token: the sort position of the last entry
+// returned. Resuming after a position rather than an offset keeps pages
+// stable while entries are added or removed. Volatile keys (cpu, rss,
+// last_seen) may still move entries across pages between requests.
+type pageCursor struct {
+	Sort SortKey `json:"s"`
+	Desc bool    `json:"d,omitempty"`
+	Num  float64 `json:"n,omitempty"`
+	Str  string  `json:"t,omitempty"`
+	ID   ProcID  `json:"i"`
+}
+
+func encodeCursor(c pageCursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(token string, req PageRequest) (pageCursor, error) {
+	var c pageCursor
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || json.Unmarshal(raw, &c) != nil {
+		return c, ErrInvalidPageToken
+	}
+	if c.Sort != req.Sort || c.Desc != req.Desc {
+		return c, fmt.Errorf("%w: it was issued for a different sort order", ErrInvalidPageToken)
+	}
+	return c, nil
+}
+
+// sortValue returns the sort position of p. Times use milliseconds so they
+// survive the round trip through a float; ties are broken by ID.
+func sortValue(p *Proc, key SortKey) (float64, string) {
+	switch key {
+	case SortPID:
+		return float64(p.PID), ""
+	case SortName:
+		return 0, p.Name
+	case SortAddedAt:
+		return float64(p.AddedAt.UnixMilli()), ""
+	case SortLastSeen:
+		return float64(p.LastSeen.UnixMilli()), ""
+	case SortCPU:
+		if p.Metrics == nil {
+			return -1, ""
+		}
+		return p.Metrics.CPUPercent, ""
+	case SortRSS:
+		if p.Metrics == nil {
+			return -1, ""
+		}
+		return float64(p.Metrics.RSS), ""
+	default:
+		return float64(p.ID), ""
+	}
+}
+
+func comparePosition(a, b pageCursor) int {
+	if c := cmp.Compare(a.Num, b.Num); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Str, b.Str); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.ID, b.ID)
+}
+
+// ListPage returns one page of the entries matching f, ordered by req.Sort.
+// Entries without metrics sort below every sampled entry for cpu and rss.
+func (r *Registry) ListPage(f ListFilter, req PageRequest) (PageResult, error) {
+	if req.Sort == "" {
+		req.Sort = SortID
+	}
+	if req.Limit < 0 {
+		return PageResult{}, fmt.Errorf("limit must not be negative")
+	}
+	var after *pageCursor
+	if req.Token != "" {
+		c, err := decodeCursor(req.Token, req)
+		if err != nil {
+			return PageResult{}, err
+		}
+		after = &c
+	}
+
+	all := r.List(f)
+	positions := make([]pageCursor, len(all))
+	for i := range all {
+		num, str := sortValue(&all[i], req.Sort)
+		positions[i] = pageCursor{Sort: req.Sort, Desc: req.Desc, Num: num, Str: str, ID: all[i].ID}
+	}
+	order := make([]int, len(all))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(i, j int) int {
+		c := comparePosition(positions[i], positions[j])
+		if req.Desc {
+			return -c
+		}
+		return c
+	})
+
+	res := PageResult{Total: len(all)}
+	last := -1
+	for _, i := range order {
+		if after != nil {
+			c := comparePosition(positions[i], *after)
+			if req.Desc {
+				c = -c
+			}
+			if c <= 0 {
+				continue
+			}
+		}
+		if req.Limit > 0 && len(res.Procs) == req.Limit {
+			res.NextToken = encodeCursor(positions[last])
+			break
+		}
+		res.Procs = append(res.Procs, all[i])
+		last = i
+	}
+	return res, nil
+}
@@ -0,0 +1,17 @@
+package registry
+
+import "regexp"
+
+// matchAnyKey returns the union of index entries whose key matches re.
+func matchAnyKey(index map[string]map[ProcID]struct{}, re *regexp.Regexp) map[ProcID]struct{} {
+	out := make(map[ProcID]struct{})
+	for key, ids := range index {
+		if !re.MatchString(key) {
+			continue
+		}
+		for id := range ids {
+			out[id] = struct{}{}
+		}
+	}
+	return out
+}
@@ -0,0 +1,217 @@
+package registry
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"goproc/internal/query"
+)
+
+// queryCandidatesLocked narrows a query to entries found through the
+// indexes. ok is false when the expression cannot be answered from the
+// indexes and every entry has to be evaluated. The result is a superset of
+// the matches; callers still evaluate the query on each candidate.
+// Caller holds r.mu.
+func (r *Registry) queryCandidatesLocked(e query.Expr) (map[ProcID]struct{}, bool) {
+	switch e := e.(type) {
+	case *query.And:
+		left, lok := r.queryCandidatesLocked(e.Left)
+		right, rok := r.queryCandidatesLocked(e.Right)
+		switch {
+		case lok && rok:
+			out := make(map[ProcID]struct{})
+			for id := range left {
+				if _, ok := right[id]; ok {
+					out[id] = struct{}{}
+				}
+			}
+			return out, true
+		case lok:
+			return left, true
+		default:
+			return right, rok
+		}
+	case *query.Or:
+		left, lok := r.queryCandidatesLocked(e.Left)
+		right, rok := r.queryCandidatesLocked(e.Right)
+		if !lok || !rok {
+			return nil, false
+		}
+		out := make(map[ProcID]struct{}, len(left)+len(right))
+		for id := range left {
+			out[id] = struct{}{}
+		}
+		for id := range right {
+			out[id] = struct{}{}
+		}
+		return out, true
+	case *query.Cond:
+		return r.condCandidatesLocked(e)
+	}
+	return nil, false
+}
+
+func (r *Registry) condCandidatesLocked(c *query.Cond) (map[ProcID]struct{}, bool) {
+	if c.Field == query.FieldAnnotation {
+		// Every annotation condition requires the key to be present.
+		return r.byAnnotation[c.Key], true
+	}
+	if c.Op != query.OpHas && c.Op != query.OpEq {
+		return nil, false
+	}
+	single := func(id ProcID, ok bool) (map[ProcID]struct{}, bool) {
+		if !ok {
+			return map[ProcID]struct{}{}, true
+		}
+		return map[ProcID]struct{}{id: {}}, true
+	}
+	switch c.Field {
+	case query.FieldTag:
+		return r.byTag[c.Value], true
+	case query.FieldGroup:
+		return r.byGroup[c.Value], true
+	case query.FieldName:
+		id, ok := r.byName[c.Value]
+		return single(id, ok)
+	case query.FieldID:
+		_, ok := r.byID[ProcID(c.Num)]
+		return single(ProcID(c.Num), ok && float64(ProcID(c.Num)) == c.Num)
+	case query.FieldPID:
+		id, ok := r.byPID[int(c.Num)]
+		return single(id, ok && float64(int(c.Num)) == c.Num)
+	}
+	return nil, false
+}
+
+// matchQuery evaluates e against p. A nil expression matches everything.
+func matchQuery(e query.Expr, p *Proc, now time.Time) bool {
+	switch e := e.(type) {
+	case nil:
+		return true
+	case *query.And:
+		return matchQuery(e.Left, p, now) && matchQuery(e.Right, p, now)
+	case *query.Or:
+		return matchQuery(e.Left, p, now) || matchQuery(e.Right, p, now)
+	case *query.Not:
+		return !matchQuery(e.X, p, now)
+	case *query.Cond:
+		return matchCond(e, p, now)
+	}
+	return false
+}
+
+func matchCond(c *query.Cond, p *Proc, now time.Time) bool {
+	switch c.Field {
+	case query.FieldAlive:
+		return p.Alive
+	case query.FieldTag:
+		return matchSet(c, p.Meta.Tags)
+	case query.FieldGroup:
+		return matchSet(c, p.Meta.Groups)
+	case query.FieldName:
+		return matchString(c, p.Name, false)
+	case query.FieldCmd:
+		return matchString(c, p.Cmd, true)
+	case query.FieldAnnotation:
+		v, ok := p.Meta.Annotations[c.Key]
+		return ok && matchString(c, v, false)
+	}
+
+	var n float64
+	switch c.Field {
+	case query.FieldID:
+		n = float64(p.ID)
+	case query.FieldPID:
+		n = float64(p.PID)
+	case query.FieldPGID:
+		n = float64(p.PGID)
+	case query.FieldRestarts:
+		n = float64(p.Restarts)
+	case query.FieldParent:
+		n = float64(p.ParentID)
+	case query.FieldChildren:
+		n = float64(len(p.Children))
+	case query.FieldAge:
+		n = now.Sub(p.AddedAt).Seconds()
+	default:
+		// Metrics are only known for alive, sampled entries; nothing
+		// compares true against a missing sample.
+		m := p.Metrics
+		if m == nil {
+			return false
+		}
+		switch c.Field {
+		case query.FieldCPU:
+			n = m.CPUPercent
+		case query.FieldRSS:
+			n = float64(m.RSS)
+		case query.FieldVMS:
+			n = float64(m.VMS)
+		case query.FieldThreads:
+			n = float64(m.Threads)
+		case query.FieldFDs:
+			n = float64(m.FDs)
+		default:
+			return false
+		}
+	}
+	return compareNum(c.Op, n, c.Num)
+}
+
+// matchString compares a scalar value. ":" means substring for cmd and
+// equality elsewhere.
+func matchString(c *query.Cond, v string, substring bool) bool {
+	switch c.Op {
+	case query.OpExists:
+		return true
+	case query.OpHas:
+		if substring {
+			return strings.Contains(v, c.Value)
+		}
+		return v == c.Value
+	case query.OpEq:
+		return v == c.Value
+	case query.OpNe:
+		return v != c.Value
+	case query.OpMatch:
+		return c.Re.MatchString(v)
+	case query.OpNotMatch:
+		return !c.Re.MatchString(v)
+	}
+	return false
+}
+
+// matchSet applies a condition to tags or groups: positive operators hold
+// when any member matches, negated ones when none does.
+func matchSet(c *query.Cond, members []string) bool {
+	switch c.Op {
+	case query.OpHas, query.OpEq:
+		return slices.Contains(members, c.Value)
+	case query.OpNe:
+		return !slices.Contains(members, c.Value)
+	case query.OpMatch:
+		return slices.ContainsFunc(members, c.Re.MatchString)
+	case query.OpNotMatch:
+		return !slices.ContainsFunc(members, c.Re.MatchString)
+	}
+	return false
+}
+
+func compareNum(op query.Op, have, want float64) bool {
+	switch op {
+	case query.OpHas, query.OpEq:
+		return have == want
+	case query.OpNe:
+		return have != want
+	case query.OpLt:
+		return have < want
+	case query.OpLe:
+		return have <= want
+	case query.OpGt:
+		return have > want
+	case query.OpGe:
+		return have >= want
+	}
+	return false
+}
@@ -3,7 +3,7 @@ package registry
 import (
 	"errors"
 	"fmt"
-	"log"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -20,17 +20,68 @@ type Registry struct {
 	byName  map[string]ProcID
 	byTag   map[string]map[ProcID]struct{}
 	byGroup map[string]map[ProcID]struct{}
+	// byAnnotation indexes entries by annotation key.
+	byAnnotation map[string]map[ProcID]struct{}
 	// Interval between persisted lastSeen bumps while a process remains alive.
 	lastSeenInterval time.Duration
 
-	// Where to snapshot. If empty, snapshotting is disabled.
-	SnapshotPath string
+	// Active event subscribers; guarded by mu.
+	subs map[*Subscription]struct{}
+
+	// Tombstones of removed entries ordered by Seq (see History); guarded
+	// by mu. With a HistoryStore they hold only Seq and RemovedAt, enough
+	// for the retention, and queries read the rest from the store.
+	history     []Tombstone
+	nextSeq     uint64 // Seq of the newest tombstone
+	retention   HistoryRetention
+	lazyHistory bool
+
+	// Persistence (see Open). smu orders store writes and compactions and
+	// is always taken before mu. dirty collects the entries changed since
+	// they were last stored and dirtyReset a pending Reset; both are
+	// guarded by mu, and dirty is nil when persistence is disabled. So are
+	// buried and forgotten, the tombstones added to and dropped from the
+	// history since. store, pending and compactEvery are guarded by smu.
+	smu          sync.Mutex
+	store        Store
+	dirty        map[ProcID]struct{}
+	dirtyReset   bool
+	buried       []Tombstone
+	forgotten    []uint64
+	pending      int // changes stored since the last compaction
+	compactEvery int
+}
+
+// Options configures a registry for Open.
+type Options struct {
+	// Store persists the registry; nil keeps it in memory only.
+	Store Store
+	// LastSeenInterval is the period between persisted lastSeen bumps while
+	// a process remains alive (default 30s).
+	LastSeenInterval time.Duration
+	// CompactEvery is the number of stored changes after which the store is
+	// compacted (default 1000).
+	CompactEvery int
+	// History bounds the tombstones kept for removed entries; the zero
+	// value keeps none.
+	History HistoryRetention
 }
 
-// New loads snapshot if present and returns a ready registry.
+// New loads snapshot if present and returns a ready registry. It uses a
+// JSONStore with the default journal settings; see Open.
 func New(snapshotPath string, lastSeenInterval time.Duration) (*Registry, error) {
-	if lastSeenInterval <= 0 {
-		lastSeenInterval = 30 * time.Second
+	opts := Options{LastSeenInterval: lastSeenInterval}
+	if snapshotPath != "" {
+		opts.Store = NewJSONStore(JSONStoreOptions{SnapshotPath: snapshotPath})
+	}
+	return Open(opts)
+}
+
+// Open restores the registry from opts.Store and returns it ready for use.
+// Close compacts and closes the store.
+func Open(opts Options) (*Registry, error) {
+	if opts.LastSeenInterval <= 0 {
+		opts.LastSeenInterval = 30 * time.Second
 	}
 	r := &Registry{
 		nextID:           1,
@@ -39,11 +90,13 @@ func New(snapshotPath string, lastSeenInterval time.Duration) (*Registry, error)
 		byName:           make(map[string]ProcID),
 		byTag:            make(map[string]map[ProcID]struct{}),
 		byGroup:          make(map[string]map[ProcID]struct{}),
-		SnapshotPath:     snapshotPath,
-		lastSeenInterval: lastSeenInterval,
+		byAnnotation:     make(map[string]map[ProcID]struct{}),
+		subs:             make(map[*Subscription]struct{}),
+		lastSeenInterval: opts.LastSeenInterval,
+		retention:        opts.History,
 	}
-	if snapshotPath != "" {
-		if err := r.loadSnapshot(snapshotPath); err != nil {
+	if opts.Store != nil {
+		if err := r.openStore(opts.Store, opts.CompactEvery); err != nil {
 			return nil, err
 		}
 	}
@@ -52,23 +105,32 @@ func New(snapshotPath string, lastSeenInterval time.Duration) (*Registry, error)
 
 // AddByPID registers an existing process. Returns the ID plus a flag indicating whether it already existed.
 func (r *Registry) AddByPID(pid, pgid int, cmd, name string, tags, groups []string) (ProcID, bool, error) {
-	if pid <= 0 {
+	return r.Add(AddParams{PID: pid, PGID: pgid, Cmd: cmd, Name: name, Tags: tags, Groups: groups})
+}
+
+// Add registers a process described by params. Returns the ID plus a flag indicating whether the PID was already tracked.
+func (r *Registry) Add(params AddParams) (ProcID, bool, error) {
+	if params.PID <= 0 {
 		return 0, false, errors.New("pid must be > 0")
 	}
-	normName, err := normalizeName(name)
+	normName, err := NormalizeName(params.Name)
+	if err != nil {
+		return 0, false, err
+	}
+	annotations, err := NormalizeAnnotations(params.Annotations)
 	if err != nil {
 		return 0, false, err
 	}
 
 	r.mu.Lock()
-	if id, ok := r.byPID[pid]; ok {
+	if id, ok := r.claimPIDLocked(params.PID, params.StartTicks, params.BootID); !ok {
 		r.mu.Unlock()
 		return id, true, nil
 	}
 	if normName != "" {
 		if _, exists := r.byName[normName]; exists {
 			r.mu.Unlock()
-			return 0, false, fmt.Errorf("name %q is already in use", normName)
+			return 0, false, errNameInUse(normName)
 		}
 	}
 	id := r.nextID
@@ -76,36 +138,173 @@ func (r *Registry) AddByPID(pid, pgid int, cmd, name string, tags, groups []stri
 
 	p := &Proc{
 		ID:       id,
-		PID:      pid,
-		PGID:     pgid,
-		Cmd:      cmd,
+		PID:      params.PID,
+		PGID:     params.PGID,
+		Cmd:      params.Cmd,
 		Name:     normName,
 		Alive:    true, // optimistic; can be updated by watcher later
 		AddedAt:  now(),
 		LastSeen: now(),
-		Meta:     ProcMeta{Tags: norm(tags), Groups: norm(groups)},
+		Meta:     ProcMeta{Tags: norm(params.Tags), Groups: norm(params.Groups), Annotations: annotations},
+		Spawn:    params.Spawn,
+		Restart:  params.Restart,
+
+		StartTicks: params.StartTicks,
+		BootID:     params.BootID,
+
+		ParentID:      params.ParentID,
+		TrackChildren: params.TrackChildren,
+		Cgroup:        params.Cgroup,
+		Manifest:      params.Manifest,
+	}
+	r.indexLocked(p)
+	r.markLocked(id)
+	r.emitLocked(EventAdded, p, "")
+	r.mu.Unlock()
+
+	r.maybeSave()
+	return id, false, nil
+}
+
+// claimPIDLocked frees pid for a new entry. A live entry for the same process
+// (matching start time and boot ID, where known) keeps the PID and its ID is
+// returned with ok=false. A dead entry, or one whose PID was recycled by
+// another process, is unlinked from byPID and marked dead. Caller holds r.mu.
+func (r *Registry) claimPIDLocked(pid int, startTicks uint64, bootID string) (ProcID, bool) {
+	id, exists := r.byPID[pid]
+	if !exists {
+		return 0, true
+	}
+	p := r.byID[id]
+	if p == nil {
+		delete(r.byPID, pid)
+		return 0, true
+	}
+	if p.Alive && !p.Reused(startTicks, bootID) {
+		return id, false
+	}
+	delete(r.byPID, pid)
+	if p.Alive {
+		p.Alive = false
+		p.Metrics = nil
+		p.Children = nil
+		p.Exit = &ExitStatus{At: now(), Reason: ExitReasonUnknown, Code: -1}
+		r.markLocked(id)
+		r.buryLocked(p, RemovalDied)
+		r.emitLocked(EventDied, p, "")
+	}
+	return 0, true
+}
+
+// SetIdentity backfills the start time and boot ID of an entry registered
+// before they were recorded. Existing values are never replaced.
+func (r *Registry) SetIdentity(id ProcID, startTicks uint64, bootID string) {
+	r.mu.Lock()
+	p := r.byID[id]
+	if p == nil || p.StartTicks != 0 || startTicks == 0 {
+		r.mu.Unlock()
+		return
+	}
+	p.StartTicks = startTicks
+	p.BootID = bootID
+	r.markLocked(id)
+	r.mu.Unlock()
+
+	r.maybeSave()
+}
+
+// CheckName validates a prospective name and reports whether it is free.
+// Returns the normalized name (empty when no name was requested).
+func (r *Registry) CheckName(name string) (string, error) {
+	normName, err := NormalizeName(name)
+	if err != nil || normName == "" {
+		return normName, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if _, exists := r.byName[normName]; exists {
+		return "", errNameInUse(normName)
+	}
+	return normName, nil
+}
+
+// SetName renames an entry; an empty name clears it. It returns the previous
+// name and fails with ErrNameInUse when another entry holds the name.
+func (r *Registry) SetName(id ProcID, name string) (string, error) {
+	normName, err := NormalizeName(name)
+	if err != nil {
+		return "", err
+	}
+	r.mu.Lock()
+	p := r.byID[id]
+	if p == nil {
+		r.mu.Unlock()
+		return "", osErrNotFound(id)
+	}
+	prev := p.Name
+	if prev == normName {
+		r.mu.Unlock()
+		return prev, nil
 	}
-	r.byID[id] = p
-	r.byPID[pid] = id
 	if normName != "" {
+		if _, exists := r.byName[normName]; exists {
+			r.mu.Unlock()
+			return "", errNameInUse(normName)
+		}
 		r.byName[normName] = id
 	}
-	for _, t := range p.Meta.Tags {
-		if _, ok := r.byTag[t]; !ok {
-			r.byTag[t] = make(map[ProcID]struct{})
-		}
-		r.byTag[t][id] = struct{}{}
+	if prev != "" {
+		delete(r.byName, prev)
 	}
-	for _, g := range p.Meta.Groups {
-		if _, ok := r.byGroup[g]; !ok {
-			r.byGroup[g] = make(map[ProcID]struct{})
+	p.Name = normName
+	r.markLocked(id)
+	r.emitLocked(EventRenamed, p, prev)
+	r.mu.Unlock()
+
+	r.maybeSave()
+	return prev, nil
+}
+
+// Respawned moves an existing entry to the PID of a freshly relaunched
+// process, identified by startTicks and bootID. The exit status of the
+// previous process is cleared; its died tombstone keeps it in the history.
+func (r *Registry) Respawned(id ProcID, pid, pgid int, startTicks uint64, bootID string) error {
+	if pid <= 0 {
+		return errors.New("pid must be > 0")
+	}
+	r.mu.Lock()
+	p := r.byID[id]
+	if p == nil {
+		r.mu.Unlock()
+		return osErrNotFound(id)
+	}
+	if other, ok := r.byPID[pid]; ok && other != id {
+		if _, free := r.claimPIDLocked(pid, startTicks, bootID); !free {
+			r.mu.Unlock()
+			return fmt.Errorf("pid %d already registered as id %d", pid, other)
 		}
-		r.byGroup[g][id] = struct{}{}
 	}
+	if r.byPID[p.PID] == id {
+		delete(r.byPID, p.PID)
+	}
+	p.PID = pid
+	p.PGID = pgid
+	p.StartTicks = startTicks
+	p.BootID = bootID
+	p.Alive = true
+	p.Detached = false
+	p.LastSeen = now()
+	p.Restarts++
+	p.Exit = nil
+	p.Metrics = nil
+	p.Children = nil
+	r.byPID[pid] = id
+	r.markLocked(id)
+	r.emitLocked(EventRevived, p, "")
 	r.mu.Unlock()
 
 	r.maybeSave()
-	return id, false, nil
+	return nil
 }
 
 // Tag adds tags to the proc metadata atomically.
@@ -117,6 +316,7 @@ func (r *Registry) Tag(id ProcID, add []string) error {
 		return osErrNotFound(id)
 	}
 	old := toSet(p.Meta.Tags)
+	changed := false
 	for _, t := range norm(add) {
 		if _, ok := old[t]; ok {
 			continue
@@ -126,8 +326,13 @@ func (r *Registry) Tag(id ProcID, add []string) error {
 			r.byTag[t] = make(map[ProcID]struct{})
 		}
 		r.byTag[t][id] = struct{}{}
+		changed = true
 	}
 	p.Meta.Tags = setToSlice(old)
+	if changed {
+		r.markLocked(id)
+		r.emitLocked(EventLabels, p, "")
+	}
 	r.mu.Unlock()
 
 	r.maybeSave()
@@ -150,7 +355,12 @@ func (r *Registry) Untag(id ProcID, remove []string) error {
 		}
 		newSet[t] = struct{}{}
 	}
+	changed := len(newSet) != len(p.Meta.Tags)
 	p.Meta.Tags = setToSlice(newSet)
+	if changed {
+		r.markLocked(id)
+		r.emitLocked(EventLabels, p, "")
+	}
 	r.mu.Unlock()
 
 	r.maybeSave()
@@ -165,6 +375,7 @@ func (r *Registry) GroupAssign(id ProcID, groups []string) error {
 		return osErrNotFound(id)
 	}
 	old := toSet(p.Meta.Groups)
+	changed := false
 	for _, g := range norm(groups) {
 		if _, ok := old[g]; ok {
 			continue
@@ -174,8 +385,13 @@ func (r *Registry) GroupAssign(id ProcID, groups []string) error {
 			r.byGroup[g] = make(map[ProcID]struct{})
 		}
 		r.byGroup[g][id] = struct{}{}
+		changed = true
 	}
 	p.Meta.Groups = setToSlice(old)
+	if changed {
+		r.markLocked(id)
+		r.emitLocked(EventLabels, p, "")
+	}
 	r.mu.Unlock()
 
 	r.maybeSave()
@@ -198,42 +414,130 @@ func (r *Registry) GroupRemove(id ProcID, groups []string) error {
 		}
 		newSet[g] = struct{}{}
 	}
+	changed := len(newSet) != len(p.Meta.Groups)
 	p.Meta.Groups = setToSlice(newSet)
+	if changed {
+		r.markLocked(id)
+		r.emitLocked(EventLabels, p, "")
+	}
 	r.mu.Unlock()
 
 	r.maybeSave()
 	return nil
 }
 
-// Remove deletes an entry by ID.
-func (r *Registry) Remove(id ProcID) bool {
+// LabelChange lists tags and groups to remove from and add to an entry.
+// Removals are applied first, so a label in both lists ends up present.
+type LabelChange struct {
+	AddTags      []string
+	RemoveTags   []string
+	AddGroups    []string
+	RemoveGroups []string
+}
+
+// UpdateLabels applies a LabelChange atomically, emitting at most one labels
+// event and saving once. It reports whether anything changed.
+func (r *Registry) UpdateLabels(id ProcID, ch LabelChange) (bool, error) {
+	r.mu.Lock()
+	p := r.byID[id]
+	if p == nil {
+		r.mu.Unlock()
+		return false, osErrNotFound(id)
+	}
+	var tagsChanged, groupsChanged bool
+	p.Meta.Tags, tagsChanged = relabelLocked(id, p.Meta.Tags, r.byTag, ch.AddTags, ch.RemoveTags)
+	p.Meta.Groups, groupsChanged = relabelLocked(id, p.Meta.Groups, r.byGroup, ch.AddGroups, ch.RemoveGroups)
+	changed := tagsChanged || groupsChanged
+	if changed {
+		r.markLocked(id)
+		r.emitLocked(EventLabels, p, "")
+	}
+	r.mu.Unlock()
+
+	if changed {
+		r.maybeSave()
+	}
+	return changed, nil
+}
+
+// relabelLocked updates one label list of an entry and its index.
+func relabelLocked(id ProcID, cur []string, index map[string]map[ProcID]struct{}, add, remove []string) ([]string, bool) {
+	set := toSet(cur)
+	changed := false
+	for _, l := range norm(remove) {
+		if _, ok := set[l]; !ok {
+			continue
+		}
+		delete(set, l)
+		delete(index[l], id)
+		if len(index[l]) == 0 {
+			delete(index, l)
+		}
+		changed = true
+	}
+	for _, l := range norm(add) {
+		if _, ok := set[l]; ok {
+			continue
+		}
+		set[l] = struct{}{}
+		if _, ok := index[l]; !ok {
+			index[l] = make(map[ProcID]struct{})
+		}
+		index[l][id] = struct{}{}
+		changed = true
+	}
+	if !changed {
+		return cur, false
+	}
+	return setToSlice(set), true
+}
+
+// Remove deletes an entry by ID and moves its final state into the history.
+func (r *Registry) Remove(id ProcID, reason RemovalReason) bool {
 	r.mu.Lock()
 	p := r.byID[id]
 	if p == nil {
 		r.mu.Unlock()
 		return false
 	}
-	delete(r.byID, id)
-	delete(r.byPID, p.PID)
+	r.removeLocked(p, reason)
+	r.mu.Unlock()
+
+	r.maybeSave()
+	return true
+}
+
+// removeLocked deletes p from the registry and moves its final state into
+// the history. Caller holds r.mu for writing.
+func (r *Registry) removeLocked(p *Proc, reason RemovalReason) {
+	delete(r.byID, p.ID)
+	if r.byPID[p.PID] == p.ID {
+		delete(r.byPID, p.PID)
+	}
 	if p.Name != "" {
 		delete(r.byName, p.Name)
 	}
 	for _, t := range p.Meta.Tags {
-		delete(r.byTag[t], id)
+		delete(r.byTag[t], p.ID)
 		if len(r.byTag[t]) == 0 {
 			delete(r.byTag, t)
 		}
 	}
 	for _, g := range p.Meta.Groups {
-		delete(r.byGroup[g], id)
+		delete(r.byGroup[g], p.ID)
 		if len(r.byGroup[g]) == 0 {
 			delete(r.byGroup, g)
 		}
 	}
-	r.mu.Unlock()
-
-	r.maybeSave()
-	return true
+	for k := range p.Meta.Annotations {
+		delete(r.byAnnotation[k], p.ID)
+		if len(r.byAnnotation[k]) == 0 {
+			delete(r.byAnnotation, k)
+		}
+	}
+	r.markLocked(p.ID)
+	r.buryLocked(p, reason)
+	r.emitLocked(EventRemoved, p, "")
 }
 
 // SetAlive updates alive flag (and occasionally lastSeen) for the given process.
@@ -250,6 +554,14 @@ func (r *Registry) SetAlive(id ProcID, alive bool) bool {
 	if p.Alive != alive {
 		p.Alive = alive
 		changed = true
+		if alive {
+			r.emitLocked(EventRevived, p, "")
+		} else {
+			p.Metrics = nil
+			p.Children = nil
+			r.buryLocked(p, RemovalDied)
+			r.emitLocked(EventDied, p, "")
+		}
 	}
 	if alive {
 		now := now()
@@ -258,6 +570,9 @@ func (r *Registry) SetAlive(id ProcID, alive bool) bool {
 			changed = true
 		}
 	}
+	if changed {
+		r.markLocked(id)
+	}
 	r.mu.Unlock()
 
 	if changed {
@@ -266,6 +581,58 @@ func (r *Registry) SetAlive(id ProcID, alive bool) bool {
 	return changed
 }
 
+// MarkExited records that an entry's process terminated and marks it dead.
+// An unknown status never overwrites a more precise one recorded earlier,
+// so a late liveness probe cannot clobber the supervisor's wait status.
+// It reports whether the entry was alive, so that exactly one of several
+// concurrent observers of the same death acts on it.
+func (r *Registry) MarkExited(id ProcID, st ExitStatus) bool {
+	r.mu.Lock()
+	p := r.byID[id]
+	if p == nil {
+		r.mu.Unlock()
+		return false
+	}
+	if !p.Alive && p.Exit != nil && st.Reason == ExitReasonUnknown {
+		r.mu.Unlock()
+		return false
+	}
+	wasAlive := p.Alive
+	p.Alive = false
+	p.Metrics = nil
+	p.Children = nil
+	p.Exit = &st
+	r.markLocked(id)
+	if wasAlive {
+		r.buryLocked(p, RemovalDied)
+		r.emitLocked(EventDied, p, "")
+	}
+	r.mu.Unlock()
+
+	r.maybeSave()
+	return wasAlive
+}
+
+// SetMetrics stores the latest resource sample for an entry (nil clears it).
+// Metrics are volatile, so this neither snapshots nor emits an event.
+func (r *Registry) SetMetrics(id ProcID, m *Metrics) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p := r.byID[id]; p != nil {
+		p.Metrics = m
+	}
+}
+
+// SetChildren stores the process tree below an entry's PID. Like metrics the
+// tree is volatile, so this neither snapshots nor emits an event.
+func (r *Registry) SetChildren(id ProcID, nodes []ProcNode) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p := r.byID[id]; p != nil {
+		p.Children = nodes
+	}
+}
+
 // RenameTag renames a tag across all processes and returns affected count.
 func (r *Registry) RenameTag(from, to string) int {
 	from = strings.TrimSpace(from)
@@ -307,6 +674,8 @@ func (r *Registry) renameTagLocked(from, to string) int {
 		p.Meta.Tags = setToSlice(set)
 		delete(r.byTag[from], id)
 		r.byTag[to][id] = struct{}{}
+		r.markLocked(id)
+		r.emitLocked(EventLabels, p, "")
 		count++
 	}
 	if len(r.byTag[from]) == 0 {
@@ -356,6 +725,8 @@ func (r *Registry) renameGroupLocked(from, to string) int {
 		p.Meta.Groups = setToSlice(set)
 		delete(r.byGroup[from], id)
 		r.byGroup[to][id] = struct{}{}
+		r.markLocked(id)
+		r.emitLocked(EventLabels, p, "")
 		count++
 	}
 	if len(r.byGroup[from]) == 0 {
@@ -364,18 +735,34 @@ func (r *Registry) renameGroupLocked(from, to string) int {
 	return count
 }
 
-// Reset clears the registry and resets the ID counter.
-func (r *Registry) Reset() {
+// Reset clears the registry and resets the ID counter. The removed entries
+// are kept in the history and returned, sorted by ID, so callers can release
+// what they held.
+func (r *Registry) Reset() []Proc {
 	r.mu.Lock()
+	ids := make([]ProcID, 0, len(r.byID))
+	for id := range r.byID {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	removed := make([]Proc, 0, len(ids))
+	for _, id := range ids {
+		removed = append(removed, *r.byID[id])
+		r.buryLocked(r.byID[id], RemovalReset)
+	}
 	r.nextID = 1
 	r.byID = make(map[ProcID]*Proc)
 	r.byPID = make(map[int]ProcID)
 	r.byName = make(map[string]ProcID)
 	r.byTag = make(map[string]map[ProcID]struct{})
 	r.byGroup = make(map[string]map[ProcID]struct{})
+	r.byAnnotation = make(map[string]map[ProcID]struct{})
+	r.markResetLocked()
+	r.emitLocked(EventReset, nil, "")
 	r.mu.Unlock()
 
 	r.maybeSave()
+	return removed
 }
 
 // Get returns a copy of a Proc by ID.
@@ -395,9 +782,19 @@ func (r *Registry) List(f ListFilter) []Proc {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	ids := make([]ProcID, 0, len(r.byID))
-	for id := range r.byID {
-		ids = append(ids, id)
+	// A query answerable from the indexes seeds the candidates; otherwise
+	// every entry is a candidate.
+	var ids []ProcID
+	if cands, ok := r.queryCandidatesLocked(f.Query); ok {
+		ids = make([]ProcID, 0, len(cands))
+		for id := range cands {
+			ids = append(ids, id)
+		}
+	} else {
+		ids = make([]ProcID, 0, len(r.byID))
+		for id := range r.byID {
+			ids = append(ids, id)
+		}
 	}
 
 	if len(f.IDs) > 0 {
@@ -483,6 +880,15 @@ func (r *Registry) List(f ListFilter) []Proc {
 		})
 	}
 
+	for _, sel := range f.Annotations {
+		ids = filterIDs(ids, func(id ProcID) bool {
+			if _, ok := r.byAnnotation[sel.Key][id]; !ok {
+				return false
+			}
+			return sel.Match(r.byID[id].Meta.Annotations)
+		})
+	}
+
 	if f.AliveOnly {
 		ids = filterIDs(ids, func(id ProcID) bool {
 			return r.byID[id].Alive
@@ -495,6 +901,39 @@ func (r *Registry) List(f ListFilter) []Proc {
 		})
 	}
 
+	for _, re := range f.NamePatterns {
+		ids = filterIDs(ids, func(id ProcID) bool {
+			name := r.byID[id].Name
+			return name != "" && re.MatchString(name)
+		})
+	}
+	for _, re := range f.TagPatterns {
+		tagSet := matchAnyKey(r.byTag, re)
+		ids = filterIDs(ids, func(id ProcID) bool {
+			_, ok := tagSet[id]
+			return ok
+		})
+	}
+	for _, re := range f.GroupPatterns {
+		groupSet := matchAnyKey(r.byGroup, re)
+		ids = filterIDs(ids, func(id ProcID) bool {
+			_, ok := groupSet[id]
+			return ok
+		})
+	}
+	for _, re := range f.CmdPatterns {
+		ids = filterIDs(ids, func(id ProcID) bool {
+			return re.MatchString(r.byID[id].Cmd)
+		})
+	}
+
+	if f.Query != nil {
+		now := time.Now()
+		ids = filterIDs(ids, func(id ProcID) bool {
+			return matchQuery(f.Query, r.byID[id], now)
+		})
+	}
+
 	out := make([]Proc, 0, len(ids))
 	for _, id := range ids {
 		cp := *r.byID[id]
@@ -504,13 +943,38 @@ func (r *Registry) List(f ListFilter) []Proc {
 	return out
 }
 
-// maybeSave performs a best-effort snapshot write if a path is configured.
-func (r *Registry) maybeSave() {
-	if r.SnapshotPath == "" {
-		return
+// indexLocked adds p to byID and the secondary indexes. Detached entries do
+// not own their PID, so they stay out of byPID. Caller holds r.mu.
+func (r *Registry) indexLocked(p *Proc) {
+	r.byID[p.ID] = p
+	if !p.Detached {
+		r.byPID[p.PID] = p.ID
+	}
+	if p.Name != "" {
+		r.byName[p.Name] = p.ID
+	}
+	for _, t := range p.Meta.Tags {
+		if _, ok := r.byTag[t]; !ok {
+			r.byTag[t] = make(map[ProcID]struct{})
+		}
+		r.byTag[t][p.ID] = struct{}{}
 	}
-	if err := r.saveSnapshot(r.SnapshotPath); err != nil {
-		log.Printf("registry snapshot failed: %v", err)
+	for _, g := range p.Meta.Groups {
+		if _, ok := r.byGroup[g]; !ok {
+			r.byGroup[g] = make(map[ProcID]struct{})
+		}
+		r.byGroup[g][p.ID] = struct{}{}
+	}
+	r.indexAnnotationsLocked(p)
+}
+
+// indexAnnotationsLocked adds p to byAnnotation. Caller holds r.mu.
+func (r *Registry) indexAnnotationsLocked(p *Proc) {
+	for k := range p.Meta.Annotations {
+		if _, ok := r.byAnnotation[k]; !ok {
+			r.byAnnotation[k] = make(map[ProcID]struct{})
+		}
+		r.byAnnotation[k][p.ID] = struct{}{}
 	}
 }
 
@@ -563,3 +1027,15 @@ func filterIDs(ids []ProcID, keep func(ProcID) bool) []ProcID {
 func osErrNotFound(id ProcID) error {
 	return fmt.Errorf("proc %d not found", id)
 }
+
+// ErrNameInUse is matched (via errors.Is) by errors reporting a name conflict.
+var ErrNameInUse = errors.New("name is already in use")
+
+type nameInUseError struct{ name string }
+
+func (e nameInUseError) Error() string        { return fmt.Sprintf("name %q is already in use", e.name) }
+func (e nameInUseError) Is(target error) bool { return target == ErrNameInUse }
+
+func errNameInUse(name string) error {
+	return nameInUseError{name: name}
+}
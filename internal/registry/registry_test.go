@@ -0,0 +1,94 @@
+package registry
+
+import "testing"
+
+func TestProcReused(t *testing.T) {
+	p := Proc{PID: 100, StartTicks: 500, BootID: "boot-a"}
+	cases := []struct {
+		startTicks uint64
+		bootID     string
+		want       bool
+	}{
+		{500, "boot-a", false},
+		{501, "boot-a", true},
+		{500, "boot-b", true},
+		// Unknown values on either side count as a match.
+		{0, "boot-a", false},
+		{500, "", false},
+		{0, "", false},
+	}
+	for _, tc := range cases {
+		if got := p.Reused(tc.startTicks, tc.bootID); got != tc.want {
+			t.Errorf("Reused(%d, %q) = %v, want %v", tc.startTicks, tc.bootID, got, tc.want)
+		}
+	}
+	if (Proc{PID: 100}).Reused(501, "boot-b") {
+		t.Error("an entry without identity should match any process")
+	}
+}
+
+func TestAddKeepsSameProcess(t *testing.T) {
+	r := openStoreRegistry(t, nil, 0)
+	id, existed, err := r.Add(AddParams{PID: 100, Cmd: "a", StartTicks: 500, BootID: "boot-a"})
+	if err != nil || existed {
+		t.Fatalf("Add = %d, %v, %v", id, existed, err)
+	}
+	again, existed, err := r.Add(AddParams{PID: 100, Cmd: "a", StartTicks: 500, BootID: "boot-a"})
+	if err != nil || !existed || again != id {
+		t.Fatalf("re-adding the same process = %d, %v, %v; want %d, true", again, existed, err, id)
+	}
+	if p, _ := r.Get(id); !p.Alive {
+		t.Fatalf("entry of the same process died: %+v", p)
+	}
+}
+
+func TestAddReclaimsReusedPID(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		startTicks uint64
+		bootID     string
+	}{
+		{"start time", 501, "boot-a"},
+		{"boot id", 500, "boot-b"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := openStoreRegistry(t, nil, 0)
+			old := mustAddIdentity(t, r, 100, 500, "boot-a")
+			id, existed, err := r.Add(AddParams{PID: 100, Cmd: "b", StartTicks: tc.startTicks, BootID: tc.bootID})
+			if err != nil || existed || id == old {
+				t.Fatalf("Add of a reused PID = %d, %v, %v", id, existed, err)
+			}
+			p, _ := r.Get(old)
+			if p.Alive || p.Exit == nil || p.Exit.Reason != ExitReasonUnknown {
+				t.Fatalf("reused entry = %+v, want dead with an unknown exit", p)
+			}
+			if ps := r.List(ListFilter{PIDs: []int{100}, AliveOnly: true}); len(ps) != 1 || ps[0].ID != id {
+				t.Fatalf("live entries for pid 100 = %+v, want only %d", ps, id)
+			}
+			// The PID now belongs to the new entry.
+			if again, existed, _ := r.Add(AddParams{PID: 100, Cmd: "b", StartTicks: tc.startTicks, BootID: tc.bootID}); !existed || again != id {
+				t.Fatalf("re-adding the new process = %d, %v; want %d, true", again, existed, id)
+			}
+		})
+	}
+}
+
+func TestAddReclaimsDeadPID(t *testing.T) {
+	r := openStoreRegistry(t, nil, 0)
+	old := mustAddIdentity(t, r, 100, 500, "boot-a")
+	r.SetAlive(old, false)
+	// Same identity, but the entry is dead: the PID is free again.
+	id, existed, err := r.Add(AddParams{PID: 100, Cmd: "b", StartTicks: 500, BootID: "boot-a"})
+	if err != nil || existed || id == old {
+		t.Fatalf("Add of a dead entry's PID = %d, %v, %v", id, existed, err)
+	}
+}
+
+func mustAddIdentity(t *testing.T, r *Registry, pid int, startTicks uint64, bootID string) ProcID {
+	t.Helper()
+	id, _, err := r.Add(AddParams{PID: pid, Cmd: "a", StartTicks: startTicks, BootID: bootID})
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	return id
+}
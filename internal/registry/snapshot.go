@@ -1,96 +1,296 @@
 package registry
 
 import (
+	"bytes"
+	"cmp"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"time"
 )
 
-// Snapshot schema versioning for forward-compatibility.
-const snapshotVersion = 1
+// snapshotVersion is the schema version written by saveSnapshot. Bumping it
+// requires appending a migration from the previous version to migrations.
+const snapshotVersion = 3
 
 type snapshot struct {
-	Version int    `json:"version"`
-	NextID  uint64 `json:"next_id"`
-	Procs   []Proc `json:"procs"`
-	Created int64  `json:"created_unix"`
+	Version int         `json:"version"`
+	NextID  uint64      `json:"next_id"`
+	Procs   []Proc      `json:"procs"`
+	History []Tombstone `json:"history"`
+	Created int64       `json:"created_unix"`
 }
 
-func (r *Registry) loadSnapshot(path string) error {
+// SnapshotInfo summarises a snapshot file without loading it into a registry.
+type SnapshotInfo struct {
+	Path    string
+	Version int
+	// Supported is the version this build writes; older snapshots are
+	// migrated on load and newer ones are refused.
+	Supported int
+	Created   time.Time
+	NextID    uint64
+	Procs     int
+	Alive     int
+	Spawned   int // entries started by the daemon (run/apply)
+	Named     int
+	Children  int // entries registered as descendants of another entry
+	History   int // tombstones of removed entries
+}
+
+// InspectSnapshot reads the snapshot at path as stored, without migrating it.
+func InspectSnapshot(path string) (SnapshotInfo, error) {
+	info := SnapshotInfo{Path: path, Supported: snapshotVersion}
 	b, err := os.ReadFile(path)
 	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return nil
-		}
-		return err
+		return info, err
+	}
+	doc, err := decodeSnapshotDoc(b)
+	if err != nil {
+		return info, err
+	}
+	if info.Version, err = snapshotDocVersion(doc); err != nil {
+		return info, err
+	}
+	var s struct {
+		NextID  uint64        `json:"next_id"`
+		Created int64         `json:"created_unix"`
+		Procs   []inspectProc `json:"procs"`
+		History []struct{}    `json:"history"`
 	}
-	var s snapshot
 	if err := json.Unmarshal(b, &s); err != nil {
-		return err
+		return info, err
+	}
+	info.NextID = s.NextID
+	if s.Created > 0 {
+		info.Created = time.Unix(s.Created, 0)
+	}
+	for _, p := range s.Procs {
+		info.count(p)
+	}
+	info.History = len(s.History)
+	return info, nil
+}
+
+// inspectProc holds the entry fields SnapshotInfo counts. Only fields present
+// since version 1 are read, so every version the migrations understand can be
+// summarised.
+type inspectProc struct {
+	Name     string          `json:"name"`
+	Alive    bool            `json:"alive"`
+	Spawn    json.RawMessage `json:"spawn"`
+	ParentID ProcID          `json:"parent_id"`
+}
+
+func (info *SnapshotInfo) count(p inspectProc) {
+	info.Procs++
+	if p.Alive {
+		info.Alive++
 	}
-	if s.Version != snapshotVersion {
-		// Future migrations can be implemented here; for now we accept the data.
+	if len(p.Spawn) > 0 && string(p.Spawn) != "null" {
+		info.Spawned++
 	}
+	if p.Name != "" {
+		info.Named++
+	}
+	if p.ParentID != 0 {
+		info.Children++
+	}
+}
 
-	r.mu.Lock()
-	defer r.mu.Unlock()
+// state returns the contents of s.
+func (s snapshot) state() State {
+	st := State{NextID: ProcID(s.NextID), Procs: s.Procs, History: s.History}
+	slices.SortFunc(st.Procs, func(a, b Proc) int { return cmp.Compare(a.ID, b.ID) })
+	slices.SortFunc(st.History, func(a, b Tombstone) int { return cmp.Compare(a.Seq, b.Seq) })
+	return st
+}
 
-	r.nextID = ProcID(s.NextID)
+// restoreLocked replaces the registry contents with s. Caller holds r.mu.
+func (r *Registry) restoreLocked(s State) {
+	r.nextID = s.NextID
 	r.byID = make(map[ProcID]*Proc)
 	r.byPID = make(map[int]ProcID)
 	r.byName = make(map[string]ProcID)
 	r.byTag = make(map[string]map[ProcID]struct{})
 	r.byGroup = make(map[string]map[ProcID]struct{})
+	r.byAnnotation = make(map[string]map[ProcID]struct{})
 
 	for i := range s.Procs {
-		p := s.Procs[i]
 		// Store pointer to copy to avoid referencing slice backing array.
-		proc := p
-		r.byID[proc.ID] = &proc
-		r.byPID[proc.PID] = proc.ID
-		if proc.Name != "" {
-			r.byName[proc.Name] = proc.ID
+		proc := s.Procs[i]
+		r.indexLocked(&proc)
+	}
+	r.history = slices.Clone(s.History)
+	r.nextSeq = 0
+	if n := len(r.history); n > 0 {
+		r.nextSeq = r.history[n-1].Seq
+	}
+}
+
+// decodeSnapshot parses a snapshot of any supported version, migrating it to
+// the current schema. It returns the version the data was stored with.
+func decodeSnapshot(b []byte) (snapshot, int, error) {
+	doc, err := decodeSnapshotDoc(b)
+	if err != nil {
+		return snapshot{}, 0, err
+	}
+	from, err := snapshotDocVersion(doc)
+	if err != nil {
+		return snapshot{}, 0, err
+	}
+	s, err := migrateSnapshotDoc(doc, from)
+	return s, from, err
+}
+
+// migrateSnapshotDoc upgrades doc from version from to the current schema.
+func migrateSnapshotDoc(doc snapshotDoc, from int) (snapshot, error) {
+	var s snapshot
+	for v := from; v < snapshotVersion; v++ {
+		if err := migrations[v-1](doc); err != nil {
+			return s, fmt.Errorf("migrate version %d to %d: %w", v, v+1, err)
+		}
+		doc["version"] = json.Number(fmt.Sprint(v + 1))
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return s, err
+	}
+	err = json.Unmarshal(b, &s)
+	return s, err
+}
+
+// snapshotDoc is a snapshot decoded without a schema, as migrations see it.
+// Numbers are kept as json.Number so IDs and timestamps survive unchanged.
+type snapshotDoc map[string]any
+
+func decodeSnapshotDoc(b []byte) (snapshotDoc, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var doc snapshotDoc
+	if err := dec.Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("snapshot is empty")
 		}
-		for _, t := range proc.Meta.Tags {
-			if _, ok := r.byTag[t]; !ok {
-				r.byTag[t] = make(map[ProcID]struct{})
-			}
-			r.byTag[t][proc.ID] = struct{}{}
+		return nil, err
+	}
+	if doc == nil {
+		return nil, errors.New("snapshot is not a JSON object")
+	}
+	return doc, nil
+}
+
+// snapshotDocVersion validates the version of doc against the migrations this
+// build knows about.
+func snapshotDocVersion(doc snapshotDoc) (int, error) {
+	n, ok := doc["version"].(json.Number)
+	if !ok {
+		return 0, errors.New("snapshot has no version")
+	}
+	v, err := n.Int64()
+	switch {
+	case err != nil || v < 1:
+		return 0, fmt.Errorf("invalid snapshot version %s", n)
+	case v > snapshotVersion:
+		return 0, fmt.Errorf("snapshot version %d is newer than this build supports (%d); upgrade goproc or restore an older snapshot", v, snapshotVersion)
+	}
+	return int(v), nil
+}
+
+// procs returns the entries of doc for in-place edits.
+func (doc snapshotDoc) procs() ([]map[string]any, error) {
+	raw, _ := doc["procs"].([]any)
+	out := make([]map[string]any, 0, len(raw))
+	for i, item := range raw {
+		p, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("procs[%d] is not an object", i)
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// migrations[i] upgrades a snapshot from version i+1 to i+2. Migrations work
+// on the untyped document so they keep compiling as Proc evolves; each one
+// must leave data that is already in the new shape untouched.
+var migrations = []func(snapshotDoc) error{
+	migrateV1ToV2,
+	migrateV2ToV3,
+}
+
+// migrateV1ToV2 gives dead entries an exit status. Version 1 snapshots from
+// before exit statuses were recorded have dead entries without one, which
+// later code reads as "never exited"; version 2 marks them unknown, dated
+// when the entry was last seen alive.
+func migrateV1ToV2(doc snapshotDoc) error {
+	procs, err := doc.procs()
+	if err != nil {
+		return err
+	}
+	for _, p := range procs {
+		if alive, _ := p["alive"].(bool); alive || p["exit"] != nil {
+			continue
 		}
-		for _, g := range proc.Meta.Groups {
-			if _, ok := r.byGroup[g]; !ok {
-				r.byGroup[g] = make(map[ProcID]struct{})
-			}
-			r.byGroup[g][proc.ID] = struct{}{}
+		at := p["last_seen"]
+		if at == nil {
+			at = p["added_at"]
 		}
+		p["exit"] = map[string]any{"at": at, "reason": string(ExitReasonUnknown), "code": -1}
+	}
+	return nil
+}
+
+// migrateV2ToV3 adds the history of removed entries, which starts empty.
+func migrateV2ToV3(doc snapshotDoc) error {
+	if doc["history"] == nil {
+		doc["history"] = []any{}
 	}
 	return nil
 }
 
-func (r *Registry) saveSnapshot(path string) error {
+// saveSnapshot atomically replaces the snapshot at path with state; durable
+// also fsyncs it before the rename.
+func saveSnapshot(path string, state State, durable bool) error {
 	tmp := path + ".tmp"
 	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
 		return err
 	}
-
-	r.mu.RLock()
 	s := snapshot{
 		Version: snapshotVersion,
-		NextID:  uint64(r.nextID),
+		NextID:  uint64(state.NextID),
+		Procs:   state.Procs,
+		History: state.History,
 		Created: now().Unix(),
 	}
-	s.Procs = make([]Proc, 0, len(r.byID))
-	for _, p := range r.byID {
-		s.Procs = append(s.Procs, *p)
+	if s.Procs == nil {
+		s.Procs = []Proc{}
+	}
+	if s.History == nil {
+		s.History = []Tombstone{}
 	}
-	r.mu.RUnlock()
 
 	b, err := json.MarshalIndent(s, "", "  ")
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+	if err == nil && durable {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
 		return err
 	}
 	return os.Rename(tmp, path)
@@ -0,0 +1,111 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// v1Snapshot is a version 1 snapshot as written before exit statuses were
+// recorded, plus a dead entry that already has one.
+const v1Snapshot = `{
+  "version": 1,
+  "next_id": 18446744073709551000,
+  "procs": [
+    {"id": 1, "pid": 100, "pgid": 100, "cmd": "pid:100", "name": "web", "alive": true,
+     "added_at": "2025-01-02T03:04:05Z", "last_seen": "2025-01-02T04:00:00Z", "meta": {"tags": ["a"]}},
+    {"id": 2, "pid": 200, "pgid": 200, "cmd": "pid:200", "name": "", "alive": false,
+     "added_at": "2025-01-02T03:04:05Z", "last_seen": "2025-01-02T05:00:00Z", "meta": {"groups": ["g"]}},
+    {"id": 3, "pid": 300, "pgid": 300, "cmd": "sleep 1", "name": "job", "alive": false,
+     "added_at": "2025-01-02T03:04:05Z", "last_seen": "2025-01-02T05:00:00Z", "meta": {},
+     "spawn": {"argv": ["sleep", "1"]}, "exit": {"at": "2025-01-02T05:00:01Z", "reason": "exited", "code": 0}}
+  ],
+  "created_unix": 1735787045
+}`
+
+func writeSnapshot(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "goproc.snapshot.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMigrationsCoverEveryVersion(t *testing.T) {
+	if len(migrations) != snapshotVersion-1 {
+		t.Fatalf("%d migrations for snapshot version %d", len(migrations), snapshotVersion)
+	}
+}
+
+func TestDecodeSnapshotMigratesV1(t *testing.T) {
+	s, from, err := decodeSnapshot([]byte(v1Snapshot))
+	if err != nil {
+		t.Fatalf("decodeSnapshot: %v", err)
+	}
+	if from != 1 || s.Version != snapshotVersion || s.NextID != 18446744073709551000 || len(s.Procs) != 3 {
+		t.Fatalf("unexpected snapshot: from=%d %+v", from, s)
+	}
+	if s.Procs[0].Exit != nil {
+		t.Fatalf("live entry got an exit status: %+v", s.Procs[0].Exit)
+	}
+	want := time.Date(2025, 1, 2, 5, 0, 0, 0, time.UTC)
+	if e := s.Procs[1].Exit; e == nil || e.Reason != ExitReasonUnknown || e.Code != -1 || !e.At.Equal(want) {
+		t.Fatalf("dead entry exit = %+v", e)
+	}
+	if e := s.Procs[2].Exit; e == nil || e.Reason != ExitReasonExited || e.Code != 0 {
+		t.Fatalf("recorded exit was changed: %+v", e)
+	}
+}
+
+func TestLoadSnapshotBacksUpAndRewritesOldVersions(t *testing.T) {
+	path := writeSnapshot(t, v1Snapshot)
+	r, err := New(path, time.Minute)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer r.Close()
+	if p, ok := r.Get(1); !ok || p.Name != "web" {
+		t.Fatalf("entry not loaded: %+v %v", p, ok)
+	}
+	backup, err := os.ReadFile(path + ".v1.bak")
+	if err != nil || string(backup) != v1Snapshot {
+		t.Fatalf("backup = %q, %v", backup, err)
+	}
+	info, err := InspectSnapshot(path)
+	if err != nil {
+		t.Fatalf("InspectSnapshot: %v", err)
+	}
+	if info.Version != snapshotVersion || info.Procs != 3 {
+		t.Fatalf("snapshot not rewritten: %+v", info)
+	}
+}
+
+func TestLoadSnapshotRejectsNewerVersions(t *testing.T) {
+	path := writeSnapshot(t, `{"version": 99, "next_id": 1, "procs": []}`)
+	_, err := New(path, time.Minute)
+	if err == nil || !strings.Contains(err.Error(), "newer than this build supports") {
+		t.Fatalf("expected newer version error, got %v", err)
+	}
+	if _, err := os.Stat(path + ".v99.bak"); !os.IsNotExist(err) {
+		t.Fatalf("refused snapshot was backed up: %v", err)
+	}
+}
+
+func TestInspectSnapshot(t *testing.T) {
+	info, err := InspectSnapshot(writeSnapshot(t, v1Snapshot))
+	if err != nil {
+		t.Fatalf("InspectSnapshot: %v", err)
+	}
+	if info.Version != 1 || info.Supported != snapshotVersion || info.Created.Unix() != 1735787045 {
+		t.Fatalf("unexpected header: %+v", info)
+	}
+	if info.Procs != 3 || info.Alive != 1 || info.Spawned != 1 || info.Named != 2 || info.Children != 0 {
+		t.Fatalf("unexpected counts: %+v", info)
+	}
+	if _, err := InspectSnapshot(writeSnapshot(t, `{"procs": []}`)); err == nil {
+		t.Fatal("expected an error for a snapshot without version")
+	}
+}
@@ -0,0 +1,271 @@
+package registry
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Store persists the registry. The registry keeps every entry and its
+// indexes in memory and hands each batch of changes to the store, which
+// decides how to make them durable. Calls are serialised by the registry.
+type Store interface {
+	// Load returns the persisted state, migrated to the current schema.
+	Load() (State, error)
+	// Apply persists changes in order. On error nothing may be assumed
+	// persisted; the registry retries the affected entries later.
+	Apply(changes []Mutation) error
+	// Compact is called periodically and on Close with the full registry
+	// state. Stores that keep a change log fold it into state; stores that
+	// update entries in place may just flush.
+	Compact(state State) error
+	// Close releases the store. The registry compacts first.
+	Close() error
+}
+
+// HistoryStore is a Store that answers history queries itself, so the
+// history does not have to be held in memory. Its Load may return tombstones
+// with only Seq and RemovedAt set; the registry keeps just those, for the
+// retention, and passes them back to Compact in the same form.
+type HistoryStore interface {
+	Store
+	// History returns the stored tombstones matching f whose Seq is at
+	// least from, newest first.
+	History(f HistoryFilter, from uint64) ([]Tombstone, error)
+}
+
+// State is the full persisted contents of a registry.
+type State struct {
+	NextID  ProcID
+	Procs   []Proc      // sorted by ID
+	History []Tombstone // sorted by Seq
+}
+
+// MutationOp classifies a persisted change.
+type MutationOp string
+
+const (
+	MutationPut    MutationOp = "put"    // Proc is the new state of its entry
+	MutationDelete MutationOp = "delete" // entry ID was removed
+	MutationReset  MutationOp = "reset"  // every entry was removed
+	MutationBury   MutationOp = "bury"   // Tombstone was added to the history
+	MutationForget MutationOp = "forget" // tombstone Seq was dropped from the history
+)
+
+// Mutation is one persisted change. Puts carry the full state of the entry
+// rather than the operation that changed it, so applying a mutation twice is
+// harmless. NextID is the ID counter after the change.
+type Mutation struct {
+	Op        MutationOp
+	NextID    ProcID
+	ID        ProcID
+	Proc      *Proc
+	Tombstone *Tombstone
+	Seq       uint64
+}
+
+const defaultCompactEvery = 1000
+
+// SyncMode controls when store writes reach stable storage.
+type SyncMode string
+
+const (
+	SyncAlways SyncMode = "always" // fsync after every write
+	SyncBatch  SyncMode = "batch"  // fsync at most once per sync interval
+	SyncNone   SyncMode = "none"   // never fsync; the OS flushes eventually
+)
+
+const defaultSyncInterval = time.Second
+
+// ParseSyncMode validates a sync mode; empty means SyncBatch.
+func ParseSyncMode(raw string) (SyncMode, error) {
+	switch mode := SyncMode(strings.ToLower(strings.TrimSpace(raw))); mode {
+	case "":
+		return SyncBatch, nil
+	case SyncAlways, SyncBatch, SyncNone:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid sync mode %q (expected always, batch, or none)", raw)
+	}
+}
+
+// openStore restores the registry from store and starts tracking changes.
+func (r *Registry) openStore(store Store, compactEvery int) error {
+	if compactEvery <= 0 {
+		compactEvery = defaultCompactEvery
+	}
+	st, err := store.Load()
+	if err != nil {
+		return errors.Join(err, store.Close())
+	}
+	r.mu.Lock()
+	r.restoreLocked(st)
+	_, r.lazyHistory = store.(HistoryStore)
+	r.dirty = make(map[ProcID]struct{})
+	// Apply a retention that shrank while the daemon was down.
+	r.pruneHistoryLocked()
+	r.mu.Unlock()
+	r.store = store
+	r.compactEvery = compactEvery
+	r.maybeSave()
+	return nil
+}
+
+// markLocked records that the persisted state of an entry changed. Caller
+// holds r.mu for writing.
+func (r *Registry) markLocked(id ProcID) {
+	if r.dirty != nil {
+		r.dirty[id] = struct{}{}
+	}
+}
+
+// markResetLocked records a Reset; earlier changes no longer matter. Caller
+// holds r.mu for writing.
+func (r *Registry) markResetLocked() {
+	if r.dirty != nil {
+		clear(r.dirty)
+		r.dirtyReset = true
+	}
+}
+
+// maybeSave hands the entries changed since the last call to the store and
+// compacts it once enough changes have accumulated. Persistence is best
+// effort: failures are logged, the changes are retried with the next save,
+// and the in-memory registry stays authoritative.
+func (r *Registry) maybeSave() {
+	r.smu.Lock()
+	defer r.smu.Unlock()
+	if r.store == nil {
+		return
+	}
+	changes := r.drainLocked()
+	if len(changes) > 0 {
+		if err := r.store.Apply(changes); err != nil {
+			log.Printf("registry store failed: %v", err)
+			r.requeue(changes)
+			return
+		}
+		r.pending += len(changes)
+	}
+	if r.pending >= r.compactEvery {
+		if err := r.compactLocked(); err != nil {
+			log.Printf("registry compaction failed: %v", err)
+		}
+	}
+}
+
+// drainLocked turns the pending changes into mutations. Reading the entries
+// and clearing the dirty set happen under r.mu while r.smu keeps batches in
+// the order the changes were made. Caller holds r.smu.
+func (r *Registry) drainLocked() []Mutation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.mutationsLocked()
+}
+
+// mutationsLocked turns the pending changes into mutations and clears them.
+// Caller holds r.smu and r.mu for writing.
+func (r *Registry) mutationsLocked() []Mutation {
+	if len(r.dirty) == 0 && !r.dirtyReset && len(r.buried) == 0 && len(r.forgotten) == 0 {
+		return nil
+	}
+	var out []Mutation
+	if r.dirtyReset {
+		r.dirtyReset = false
+		out = append(out, Mutation{Op: MutationReset, NextID: r.nextID})
+	}
+	ids := make([]ProcID, 0, len(r.dirty))
+	for id := range r.dirty {
+		ids = append(ids, id)
+	}
+	clear(r.dirty)
+	slices.Sort(ids)
+	for _, id := range ids {
+		m := Mutation{Op: MutationDelete, NextID: r.nextID, ID: id}
+		if p := r.byID[id]; p != nil {
+			// Entries are never mutated in place below the top level, so a
+			// shallow copy is stable once the lock is released.
+			cp := *p
+			m = Mutation{Op: MutationPut, NextID: r.nextID, ID: id, Proc: &cp}
+		}
+		out = append(out, m)
+	}
+	for i := range r.buried {
+		out = append(out, Mutation{Op: MutationBury, NextID: r.nextID, Tombstone: &r.buried[i]})
+	}
+	for _, seq := range r.forgotten {
+		out = append(out, Mutation{Op: MutationForget, NextID: r.nextID, Seq: seq})
+	}
+	r.buried, r.forgotten = nil, nil
+	return out
+}
+
+// requeue marks the entries of changes that failed to persist as dirty again.
+func (r *Registry) requeue(changes []Mutation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var buried []Tombstone
+	var forgotten []uint64
+	for _, m := range changes {
+		switch m.Op {
+		case MutationReset:
+			r.dirtyReset = true
+		case MutationBury:
+			buried = append(buried, *m.Tombstone)
+		case MutationForget:
+			forgotten = append(forgotten, m.Seq)
+		default:
+			r.dirty[m.ID] = struct{}{}
+		}
+	}
+	// Keep the failed batch ahead of changes made since.
+	r.buried = append(buried, r.buried...)
+	r.forgotten = append(forgotten, r.forgotten...)
+}
+
+// compactLocked stores the pending changes and then hands the full state,
+// taken together with them, to the store. Stores that update entries in place
+// ignore the state, so the changes must reach them first. Caller holds r.smu.
+func (r *Registry) compactLocked() error {
+	r.mu.Lock()
+	changes := r.mutationsLocked()
+	st := State{NextID: r.nextID, Procs: make([]Proc, 0, len(r.byID)), History: slices.Clone(r.history)}
+	for _, p := range r.byID {
+		st.Procs = append(st.Procs, *p)
+	}
+	r.mu.Unlock()
+	slices.SortFunc(st.Procs, func(a, b Proc) int { return cmp.Compare(a.ID, b.ID) })
+
+	if len(changes) > 0 {
+		if err := r.store.Apply(changes); err != nil {
+			r.requeue(changes)
+			return err
+		}
+	}
+	if err := r.store.Compact(st); err != nil {
+		return err
+	}
+	r.pending = 0
+	return nil
+}
+
+// Close compacts and closes the store. Later changes are kept in memory only.
+func (r *Registry) Close() error {
+	r.smu.Lock()
+	defer r.smu.Unlock()
+	if r.store == nil {
+		return nil
+	}
+	err := r.compactLocked()
+	err = errors.Join(err, r.store.Close())
+	r.store = nil
+	r.mu.Lock()
+	r.dirty = nil
+	r.buried, r.forgotten = nil, nil
+	r.mu.Unlock()
+	return err
+}
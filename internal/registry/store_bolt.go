@@ -0,0 +1,381 @@
+package registry
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// BoltStore keeps the registry in a bbolt database with one key per entry
+// and one per tombstone, so changes are written in place and there is no
+// journal to compact. Each Apply is one transaction. The meta bucket holds
+// the schema version (the snapshot version, since entries are stored in the
+// same JSON form), the ID counter, and when the store was last compacted.
+// History queries read the tombstones from the database (see HistoryStore).
+type BoltStore struct {
+	opts BoltStoreOptions
+	db   *bolt.DB
+	stop chan struct{}
+}
+
+// BoltStoreOptions configures a BoltStore.
+type BoltStoreOptions struct {
+	Path string
+	// Sync selects the durability of commits (default SyncBatch): SyncAlways
+	// fsyncs every transaction, SyncBatch once per sync interval.
+	Sync SyncMode
+	// SyncInterval is the flush period of SyncBatch (default 1s).
+	SyncInterval time.Duration
+}
+
+var (
+	boltMetaBucket    = []byte("meta")
+	boltProcsBucket   = []byte("procs")
+	boltHistoryBucket = []byte("history")
+
+	boltVersionKey = []byte("version")
+	boltNextIDKey  = []byte("next_id")
+	boltCreatedKey = []byte("created_unix")
+)
+
+// boltOpenTimeout bounds the wait for the database lock, which the daemon
+// holds for as long as it runs.
+const boltOpenTimeout = time.Second
+
+// NewBoltStore returns a store for the database at opts.Path; nothing is
+// opened until Load.
+func NewBoltStore(opts BoltStoreOptions) *BoltStore {
+	if opts.Sync == "" {
+		opts.Sync = SyncBatch
+	}
+	if opts.SyncInterval <= 0 {
+		opts.SyncInterval = defaultSyncInterval
+	}
+	return &BoltStore{opts: opts}
+}
+
+// Load opens the database, creating it if needed, and reads every entry and
+// the Seq and removal time of every tombstone.
+// Databases from older schema versions are copied to a backup and migrated
+// in place.
+func (s *BoltStore) Load() (State, error) {
+	if err := os.MkdirAll(filepath.Dir(s.opts.Path), 0o700); err != nil {
+		return State{}, err
+	}
+	db, err := openBolt(s.opts.Path, &bolt.Options{Timeout: boltOpenTimeout, NoSync: s.opts.Sync != SyncAlways})
+	if err != nil {
+		return State{}, err
+	}
+	var st State
+	err = db.Update(func(tx *bolt.Tx) error {
+		meta, err := tx.CreateBucketIfNotExists(boltMetaBucket)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucketIfNotExists(boltProcsBucket); err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucketIfNotExists(boltHistoryBucket); err != nil {
+			return err
+		}
+		if meta.Get(boltVersionKey) == nil {
+			if err := meta.Put(boltVersionKey, []byte(strconv.Itoa(snapshotVersion))); err != nil {
+				return err
+			}
+			if err := meta.Put(boltNextIDKey, boltKey(1)); err != nil {
+				return err
+			}
+		}
+		from, err := boltVersion(tx)
+		if err != nil {
+			return err
+		}
+		if from != snapshotVersion {
+			if err := s.migrate(tx, from); err != nil {
+				return err
+			}
+		}
+		st, err = boltState(tx)
+		return err
+	})
+	if err == nil && s.opts.Sync != SyncAlways {
+		// Make creation and migrations durable regardless of the mode.
+		err = db.Sync()
+	}
+	if err != nil {
+		return State{}, errors.Join(fmt.Errorf("store %s: %w", s.opts.Path, err), db.Close())
+	}
+	s.db = db
+	if s.opts.Sync == SyncBatch {
+		s.stop = make(chan struct{})
+		go s.syncLoop(s.opts.SyncInterval, s.stop)
+	}
+	return st, nil
+}
+
+// migrate backs up the database and rewrites its entries from version from
+// to the current schema within tx.
+func (s *BoltStore) migrate(tx *bolt.Tx, from int) error {
+	if err := tx.CopyFile(fmt.Sprintf("%s.v%d.bak", s.opts.Path, from), 0o600); err != nil {
+		return fmt.Errorf("back up store before migration: %w", err)
+	}
+	meta, procs := tx.Bucket(boltMetaBucket), tx.Bucket(boltProcsBucket)
+	raw := []any{}
+	err := procs.ForEach(func(k, v []byte) error {
+		dec := json.NewDecoder(bytes.NewReader(v))
+		dec.UseNumber()
+		var p map[string]any
+		if err := dec.Decode(&p); err != nil {
+			return fmt.Errorf("entry %d: %w", boltID(k), err)
+		}
+		raw = append(raw, p)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	doc := snapshotDoc{"version": json.Number(strconv.Itoa(from)), "procs": raw}
+	snap, err := migrateSnapshotDoc(doc, from)
+	if err != nil {
+		return err
+	}
+	for i := range snap.Procs {
+		if err := boltPut(procs, &snap.Procs[i]); err != nil {
+			return err
+		}
+	}
+	return meta.Put(boltVersionKey, []byte(strconv.Itoa(snapshotVersion)))
+}
+
+// Apply writes changes in a single transaction.
+func (s *BoltStore) Apply(changes []Mutation) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		procs := tx.Bucket(boltProcsBucket)
+		var next ProcID
+		for _, m := range changes {
+			var err error
+			switch m.Op {
+			case MutationPut:
+				err = boltPut(procs, m.Proc)
+			case MutationDelete:
+				err = procs.Delete(boltKey(m.ID))
+			case MutationReset:
+				if err = tx.DeleteBucket(boltProcsBucket); err == nil {
+					procs, err = tx.CreateBucket(boltProcsBucket)
+				}
+			case MutationBury:
+				var v []byte
+				if v, err = json.Marshal(m.Tombstone); err == nil {
+					err = tx.Bucket(boltHistoryBucket).Put(boltUint(m.Tombstone.Seq), v)
+				}
+			case MutationForget:
+				err = tx.Bucket(boltHistoryBucket).Delete(boltUint(m.Seq))
+			default:
+				err = fmt.Errorf("unknown mutation %q", m.Op)
+			}
+			if err != nil {
+				return err
+			}
+			next = m.NextID
+		}
+		if next == 0 {
+			return nil
+		}
+		return tx.Bucket(boltMetaBucket).Put(boltNextIDKey, boltKey(next))
+	})
+}
+
+// History scans the tombstones from the newest down to Seq from.
+func (s *BoltStore) History(f HistoryFilter, from uint64) ([]Tombstone, error) {
+	var out []Tombstone
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(boltHistoryBucket).Cursor()
+		for k, v := c.Last(); k != nil && uint64(boltID(k)) >= from; k, v = c.Prev() {
+			var t Tombstone
+			if err := json.Unmarshal(v, &t); err != nil {
+				return fmt.Errorf("tombstone %d: %w", boltID(k), err)
+			}
+			if t.RemovedAt.Before(f.Since) {
+				break
+			}
+			if !f.match(t) {
+				continue
+			}
+			out = append(out, t)
+			if f.Limit > 0 && len(out) == f.Limit {
+				break
+			}
+		}
+		return nil
+	})
+	return out, err
+}
+
+// Compact records the compaction time and flushes; entries are already
+// stored in place.
+func (s *BoltStore) Compact(State) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltMetaBucket).Put(boltCreatedKey, boltUint(uint64(now().Unix())))
+	})
+	if err != nil || s.opts.Sync == SyncAlways {
+		return err
+	}
+	return s.db.Sync()
+}
+
+// Close stops the sync loop and closes the database.
+func (s *BoltStore) Close() error {
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+	if s.db == nil {
+		return nil
+	}
+	var err error
+	if s.opts.Sync == SyncBatch {
+		err = s.db.Sync()
+	}
+	err = errors.Join(err, s.db.Close())
+	s.db = nil
+	return err
+}
+
+// syncLoop flushes batched commits until stop is closed.
+func (s *BoltStore) syncLoop(interval time.Duration, stop <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+			if err := s.db.Sync(); err != nil {
+				log.Printf("registry store sync failed: %v", err)
+			}
+		}
+	}
+}
+
+// InspectBoltStore summarises the database at path without loading it into a
+// registry or migrating it. The daemon holds the database lock while it runs.
+func InspectBoltStore(path string) (SnapshotInfo, error) {
+	info := SnapshotInfo{Path: path, Supported: snapshotVersion}
+	db, err := openBolt(path, &bolt.Options{Timeout: boltOpenTimeout, ReadOnly: true})
+	if err != nil {
+		return info, err
+	}
+	defer db.Close()
+	err = db.View(func(tx *bolt.Tx) error {
+		meta, procs := tx.Bucket(boltMetaBucket), tx.Bucket(boltProcsBucket)
+		if meta == nil || procs == nil {
+			return errors.New("not a goproc store")
+		}
+		v, err := strconv.Atoi(string(meta.Get(boltVersionKey)))
+		if err != nil {
+			return fmt.Errorf("invalid store version %q", meta.Get(boltVersionKey))
+		}
+		info.Version = v
+		info.NextID = uint64(boltID(meta.Get(boltNextIDKey)))
+		if created := boltID(meta.Get(boltCreatedKey)); created > 0 {
+			info.Created = time.Unix(int64(created), 0)
+		}
+		if history := tx.Bucket(boltHistoryBucket); history != nil {
+			info.History = history.Stats().KeyN
+		}
+		return procs.ForEach(func(k, v []byte) error {
+			var p inspectProc
+			if err := json.Unmarshal(v, &p); err != nil {
+				return fmt.Errorf("entry %d: %w", boltID(k), err)
+			}
+			info.count(p)
+			return nil
+		})
+	})
+	return info, err
+}
+
+func openBolt(path string, opts *bolt.Options) (*bolt.DB, error) {
+	db, err := bolt.Open(path, 0o600, opts)
+	if errors.Is(err, bolt.ErrTimeout) {
+		return nil, fmt.Errorf("store %s is in use by another process", path)
+	}
+	return db, err
+}
+
+// boltVersion validates the schema version of the database in tx.
+func boltVersion(tx *bolt.Tx) (int, error) {
+	raw := tx.Bucket(boltMetaBucket).Get(boltVersionKey)
+	v, err := strconv.Atoi(string(raw))
+	switch {
+	case err != nil || v < 1:
+		return 0, fmt.Errorf("invalid store version %q", raw)
+	case v > snapshotVersion:
+		return 0, fmt.Errorf("store version %d is newer than this build supports (%d); upgrade goproc or restore an older store", v, snapshotVersion)
+	}
+	return v, nil
+}
+
+// boltState reads every entry in ID order, and the Seq and removal time of
+// every tombstone.
+func boltState(tx *bolt.Tx) (State, error) {
+	st := State{NextID: boltID(tx.Bucket(boltMetaBucket).Get(boltNextIDKey))}
+	err := tx.Bucket(boltProcsBucket).ForEach(func(k, v []byte) error {
+		var p Proc
+		if err := json.Unmarshal(v, &p); err != nil {
+			return fmt.Errorf("entry %d: %w", boltID(k), err)
+		}
+		st.Procs = append(st.Procs, p)
+		return nil
+	})
+	if err != nil {
+		return st, err
+	}
+	err = tx.Bucket(boltHistoryBucket).ForEach(func(k, v []byte) error {
+		var t struct {
+			RemovedAt time.Time `json:"removed_at"`
+		}
+		if err := json.Unmarshal(v, &t); err != nil {
+			return fmt.Errorf("tombstone %d: %w", boltID(k), err)
+		}
+		st.History = append(st.History, Tombstone{Seq: uint64(boltID(k)), RemovedAt: t.RemovedAt})
+		return nil
+	})
+	if st.NextID == 0 {
+		st.NextID = 1
+	}
+	return st, err
+}
+
+func boltPut(b *bolt.Bucket, p *Proc) error {
+	v, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return b.Put(boltKey(p.ID), v)
+}
+
+// boltKey encodes id big-endian so keys iterate in ID order.
+func boltKey(id ProcID) []byte {
+	return boltUint(uint64(id))
+}
+
+func boltUint(v uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, v)
+}
+
+func boltID(b []byte) ProcID {
+	if len(b) != 8 {
+		return 0
+	}
+	return ProcID(binary.BigEndian.Uint64(b))
+}
@@ -0,0 +1,152 @@
+package registry
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testBoltOptions(t *testing.T) BoltStoreOptions {
+	t.Helper()
+	return BoltStoreOptions{Path: filepath.Join(t.TempDir(), "goproc.db"), Sync: SyncNone}
+}
+
+func TestBoltStoreRoundTrip(t *testing.T) {
+	opts := testBoltOptions(t)
+	r := openStoreRegistry(t, NewBoltStore(opts), 0)
+	a := mustAdd(t, r, 100, "a")
+	b := mustAdd(t, r, 200, "b")
+	mustAdd(t, r, 300, "c")
+	if err := r.Tag(a, []string{"web"}); err != nil {
+		t.Fatal(err)
+	}
+	r.Remove(b, RemovalRm)
+	want := summary(r)
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	info, err := InspectBoltStore(opts.Path)
+	if err != nil {
+		t.Fatalf("InspectBoltStore: %v", err)
+	}
+	if info.Version != snapshotVersion || info.Procs != 2 || info.Named != 2 || info.NextID != 4 || info.Created.IsZero() {
+		t.Fatalf("unexpected info: %+v", info)
+	}
+
+	r2 := openStoreRegistry(t, NewBoltStore(opts), 0)
+	defer r2.Close()
+	if got := summary(r2); !reflect.DeepEqual(got, want) {
+		t.Fatalf("reloaded %v, want %v", got, want)
+	}
+	if id := mustAdd(t, r2, 400, "d"); id != 4 {
+		t.Fatalf("next id after reload = %d, want 4", id)
+	}
+}
+
+func TestBoltStoreReset(t *testing.T) {
+	opts := testBoltOptions(t)
+	r := openStoreRegistry(t, NewBoltStore(opts), 0)
+	mustAdd(t, r, 100, "a")
+	mustAdd(t, r, 200, "b")
+	r.Reset()
+	mustAdd(t, r, 300, "c")
+	r.Close()
+
+	r2 := openStoreRegistry(t, NewBoltStore(opts), 0)
+	defer r2.Close()
+	ps := r2.List(ListFilter{})
+	if len(ps) != 1 || ps[0].ID != 1 || ps[0].Name != "c" {
+		t.Fatalf("after reset reload: %+v", ps)
+	}
+}
+
+func TestBoltStoreIsLockedWhileOpen(t *testing.T) {
+	opts := testBoltOptions(t)
+	r := openStoreRegistry(t, NewBoltStore(opts), 0)
+	defer r.Close()
+	if _, err := InspectBoltStore(opts.Path); err == nil || !strings.Contains(err.Error(), "in use") {
+		t.Fatalf("expected in use error, got %v", err)
+	}
+}
+
+// flakyHistoryStore is a flakyStore that answers history queries.
+type flakyHistoryStore struct {
+	flakyStore
+}
+
+func (s *flakyHistoryStore) History(f HistoryFilter, from uint64) ([]Tombstone, error) {
+	return s.Store.(HistoryStore).History(f, from)
+}
+
+func TestBoltStoreServesHistory(t *testing.T) {
+	opts := testBoltOptions(t)
+	store := &flakyHistoryStore{flakyStore{Store: NewBoltStore(opts)}}
+	retention := HistoryRetention{MaxEntries: 3}
+	r := historyRegistry(t, store, retention)
+	for pid := 100; pid < 104; pid++ {
+		r.Remove(mustAdd(t, r, pid, ""), RemovalRm)
+	}
+	r.mu.RLock()
+	for _, ts := range r.history {
+		if ts.Proc.ID != 0 {
+			t.Errorf("tombstone %d held in memory: %+v", ts.Seq, ts)
+		}
+	}
+	r.mu.RUnlock()
+
+	// A tombstone that failed to persist comes first; the one it pushed out
+	// of the retention is gone although the store still has it.
+	store.failApply = true
+	r.Remove(mustAdd(t, r, 104, "e"), RemovalKill)
+	for _, tc := range []struct {
+		f    HistoryFilter
+		want string
+	}{
+		{HistoryFilter{}, "5:5:kill 4:4:rm 3:3:rm "},
+		{HistoryFilter{Limit: 2}, "5:5:kill 4:4:rm "},
+		{HistoryFilter{Reasons: []RemovalReason{RemovalRm}, Limit: 1}, "4:4:rm "},
+		{HistoryFilter{ListFilter: ListFilter{PIDs: []int{102, 104}}}, "5:5:kill 3:3:rm "},
+		{HistoryFilter{Since: time.Now().Add(time.Hour)}, ""},
+	} {
+		if got := seqs(history(t, r, tc.f)); got != tc.want {
+			t.Errorf("History(%+v) = %q, want %q", tc.f, got, tc.want)
+		}
+	}
+
+	store.failApply = false
+	r.Remove(mustAdd(t, r, 105, ""), RemovalGC)
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := r.History(HistoryFilter{}); err == nil {
+		t.Fatal("History read a closed store")
+	}
+	r2 := historyRegistry(t, NewBoltStore(opts), retention)
+	defer r2.Close()
+	if got := seqs(history(t, r2, HistoryFilter{})); got != "6:6:gc 5:5:kill 4:4:rm " {
+		t.Fatalf("reloaded history = %q", got)
+	}
+}
+
+func TestBoltStoreClosePersistsPendingChanges(t *testing.T) {
+	opts := testBoltOptions(t)
+	store := &flakyStore{Store: NewBoltStore(opts), failApply: true}
+	r := openStoreRegistry(t, store, 0)
+	mustAdd(t, r, 100, "a")
+	want := summary(r)
+
+	// Compaction only flushes the database, so the add that failed to
+	// persist must be applied on Close.
+	store.failApply = false
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	r2 := openStoreRegistry(t, NewBoltStore(opts), 0)
+	defer r2.Close()
+	if got := summary(r2); !reflect.DeepEqual(got, want) {
+		t.Fatalf("reopened %v, want %v", got, want)
+	}
+}
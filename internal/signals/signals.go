@@ -0,0 +1,39 @@
+// Package signals converts between signal names and numbers for the CLI and
+// the daemon, so both accept the same spellings.
+package signals
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// Parse accepts "TERM", "SIGTERM", "sigterm" or a number such as "15".
+func Parse(raw string) (syscall.Signal, error) {
+	s := strings.ToUpper(strings.TrimSpace(raw))
+	if s == "" {
+		return 0, fmt.Errorf("signal must not be empty")
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		if n <= 0 || Name(syscall.Signal(n)) == "" {
+			return 0, fmt.Errorf("unknown signal %q", raw)
+		}
+		return syscall.Signal(n), nil
+	}
+	if !strings.HasPrefix(s, "SIG") {
+		s = "SIG" + s
+	}
+	if sig := unix.SignalNum(s); sig != 0 {
+		return sig, nil
+	}
+	return 0, fmt.Errorf("unknown signal %q", raw)
+}
+
+// Name returns the conventional name of sig, e.g. "SIGTERM", or "" when the
+// platform does not define it.
+func Name(sig syscall.Signal) string {
+	return unix.SignalName(sig)
+}
@@ -0,0 +1,25 @@
+package signals
+
+import (
+	"syscall"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for raw, want := range map[string]syscall.Signal{
+		"TERM":    syscall.SIGTERM,
+		"sigkill": syscall.SIGKILL,
+		" HUP ":   syscall.SIGHUP,
+		"9":       syscall.SIGKILL,
+	} {
+		got, err := Parse(raw)
+		if err != nil || got != want {
+			t.Fatalf("Parse(%q) = %v, %v; want %v", raw, got, err, want)
+		}
+	}
+	for _, raw := range []string{"", "NOPE", "0", "-1", "999"} {
+		if _, err := Parse(raw); err == nil {
+			t.Fatalf("Parse(%q) should fail", raw)
+		}
+	}
+}
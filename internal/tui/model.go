@@ -2,16 +2,21 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"goproc/internal/app"
+	"goproc/internal/query"
+	"goproc/internal/units"
 )
 
 // Controller defines the subset of app.App behaviour the TUI needs.
@@ -19,16 +24,34 @@ type Controller interface {
 	Status() (app.DaemonStatus, error)
 	StartDaemon() (*app.DaemonHandle, error)
 	List(context.Context, app.ListParams) ([]app.Process, error)
+	Watch(context.Context, app.WatchParams, func(app.Event) error) error
+	UpdateLabels(context.Context, app.UpdateLabelsParams) (app.UpdateLabelsResult, error)
 }
 
+// watchRetryDelay is how long to wait before resubscribing after the event
+// stream ends (daemon restart, subscriber dropped for lagging).
+const watchRetryDelay = 2 * time.Second
+
+// maxTreeLines caps the process tree shown in the detail pane.
+const maxTreeLines = 12
+
+// metricsRefreshInterval reloads the list periodically, since metric updates
+// are not registry events.
+const metricsRefreshInterval = 5 * time.Second
+
 // Model represents the Bubble Tea state.
 type Model struct {
 	controller Controller
 
 	list      list.Model
-	processes []app.Process
+	processes []app.Process // rows of list, in order
+	all       []app.Process // last loaded entries; processes is derived from it
 	selected  map[uint64]bool
 
+	// treeView hides child entries from the list, since they appear in the
+	// tree of their parent shown in the detail pane.
+	treeView bool
+
 	daemonStatus app.DaemonStatus
 	statusMsg    string
 
@@ -40,6 +63,21 @@ type Model struct {
 
 	filters app.ListFilters
 
+	// events delivers registry changes while a watch is running; a change
+	// triggers a reload, and changes that arrive mid-reload set stale.
+	events chan tea.Msg
+	stale  bool
+
+	// labelInput is shown while editing labels of editTargets.
+	labelInput  textinput.Model
+	editing     bool
+	editTargets []uint64
+	notice      string
+
+	// queryInput edits filters.Query while querying is set.
+	queryInput textinput.Model
+	querying   bool
+
 	lastUpdated time.Time
 }
 
@@ -54,7 +92,17 @@ func New(ctrl Controller) *Model {
 	lst.SetShowPagination(false)
 	lst.DisableQuitKeybindings()
 
+	input := textinput.New()
+	input.Prompt = "labels> "
+	input.Placeholder = "+tag -tag +@group -@group"
+
+	queryInput := textinput.New()
+	queryInput.Prompt = "query> "
+	queryInput.Placeholder = "tag:db and (group:prod or name~^api-) and rss>500MB"
+
 	return &Model{
+		labelInput: input,
+		queryInput: queryInput,
 		controller: ctrl,
 		list:       lst,
 		filters:    app.ListFilters{},
@@ -74,7 +122,7 @@ func Run(ctrl Controller) error {
 
 // Init implements tea.Model.
 func (m *Model) Init() tea.Cmd {
-	return tea.Batch(checkDaemonStatusCmd(m.controller), loadProcessesCmd(m.controller, m.filters))
+	return tea.Batch(checkDaemonStatusCmd(m.controller), loadProcessesCmd(m.controller, m.filters), refreshTickCmd())
 }
 
 // Update implements tea.Model.
@@ -91,9 +139,15 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.daemonStatus = msg.status
 		if msg.status.Running {
 			if msg.status.PID > 0 {
-				m.statusMsg = fmt.Sprintf("Daemon running (pid %d). Press r to refresh, q to quit.", msg.status.PID)
+				m.statusMsg = fmt.Sprintf("Daemon running (pid %d). Updates live; press r to refresh, q to quit.", msg.status.PID)
 			} else {
-				m.statusMsg = "Daemon running. Press r to refresh, q to quit."
+				m.statusMsg = "Daemon running. Updates live; press r to refresh, q to quit."
+			}
+			if m.events == nil {
+				m.events = make(chan tea.Msg, 1)
+				// The watch is unfiltered so it survives query changes;
+				// any change just triggers a reload.
+				return m, tea.Batch(watchRegistryCmd(m.controller, app.ListFilters{}, m.events), waitForEventCmd(m.events))
 			}
 		} else {
 			m.statusMsg = "Daemon is not running. Press s to start it."
@@ -104,29 +158,55 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case processesLoadedMsg:
 		m.loading = false
 		m.err = nil
-		m.processes = msg.processes
-		newSelected := make(map[uint64]bool)
-		items := make([]list.Item, 0, len(msg.processes))
-		for _, proc := range msg.processes {
-			selected := m.selected[proc.ID]
-			if selected {
-				newSelected[proc.ID] = true
-			}
-			items = append(items, processItem{Process: proc, Selected: selected})
-		}
-		m.selected = newSelected
-		m.list.SetItems(items)
+		m.all = msg.processes
+		m.setItems()
 		m.lastUpdated = time.Now()
+		if m.stale {
+			m.stale = false
+			m.loading = true
+			return m, loadProcessesCmd(m.controller, m.filters)
+		}
+
+	case registryChangedMsg:
+		next := waitForEventCmd(m.events)
+		if m.loading {
+			m.stale = true
+			return m, next
+		}
+		m.loading = true
+		return m, tea.Batch(next, loadProcessesCmd(m.controller, m.filters))
+
+	case refreshTickMsg:
+		if m.daemonStatus.Running && !m.loading {
+			m.loading = true
+			return m, tea.Batch(refreshTickCmd(), loadProcessesCmd(m.controller, m.filters))
+		}
+		return m, refreshTickCmd()
+
+	case watchEndedMsg:
+		m.events = nil
+		return m, tea.Tick(watchRetryDelay, func(time.Time) tea.Msg {
+			return checkDaemonStatusCmd(m.controller)()
+		})
 
 	case daemonStartedMsg:
 		m.statusMsg = "Daemon started."
 		return m, tea.Batch(checkDaemonStatusCmd(m.controller), loadProcessesCmd(m.controller, m.filters))
 
+	case labelsUpdatedMsg:
+		m.notice = fmt.Sprintf("Updated labels on %d of %d process(es).", msg.updated, msg.matched)
+
 	case errMsg:
 		m.loading = false
 		m.err = msg.err
 
 	case tea.KeyMsg:
+		if m.editing {
+			return m, m.updateLabelEditor(msg)
+		}
+		if m.querying {
+			return m, m.updateQueryEditor(msg)
+		}
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, tea.Quit
@@ -144,6 +224,23 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if len(m.selected) > 0 {
 				m.clearSelection()
 			}
+		case "t":
+			m.treeView = !m.treeView
+			m.setItems()
+		case "e":
+			if targets := m.labelTargets(); len(targets) > 0 {
+				m.editing = true
+				m.editTargets = targets
+				m.notice = ""
+				m.labelInput.Reset()
+				return m, m.labelInput.Focus()
+			}
+		case "/":
+			m.querying = true
+			m.notice = ""
+			m.queryInput.SetValue(m.filters.Query)
+			m.queryInput.CursorEnd()
+			return m, m.queryInput.Focus()
 		}
 	}
 
@@ -191,12 +288,49 @@ func (m *Model) View() string {
 			strings.Join(current.Tags, ","),
 			strings.Join(current.Groups, ","),
 		)
+		if current.Alive {
+			detail += "\n" + formatMetrics(current.Metrics)
+		}
+		if exit := current.Exit; exit != nil {
+			label := "exit"
+			if current.Alive {
+				label = "last exit"
+			}
+			detail += fmt.Sprintf("\n%s=%s at %s", label, exit, exit.At.Format(time.DateTime))
+		}
+		if current.ParentID != 0 {
+			detail += fmt.Sprintf("\nparent=%d", current.ParentID)
+		}
+		if current.Cgroup != nil {
+			detail += "\ncgroup=" + current.Cgroup.Path
+		}
+		if m.treeView && len(current.Children) > 0 {
+			detail += "\n" + formatTree(current.Children)
+		}
 		detailStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1).MarginBottom(1)
 		b.WriteString(detailStyle.Render(detail))
 		b.WriteByte('\n')
 	}
 
-	help := "Commands: q quit • r reload • s start daemon • space select • c clear selection"
+	switch {
+	case m.editing:
+		b.WriteString(fmt.Sprintf("Edit labels of %d process(es) — enter apply • esc cancel\n", len(m.editTargets)))
+		b.WriteString(m.labelInput.View())
+		b.WriteByte('\n')
+	case m.querying:
+		b.WriteString("Filter with a query (empty clears) — enter apply • esc cancel\n")
+		b.WriteString(m.queryInput.View())
+		b.WriteByte('\n')
+	}
+	if m.notice != "" && !m.editing {
+		b.WriteString(m.notice)
+		b.WriteByte('\n')
+	}
+
+	help := "Commands: q quit • r reload • s start daemon • space select • c clear selection • e edit labels • / filter • t tree"
+	if m.filters.Query != "" {
+		help += fmt.Sprintf(" • query=%q", m.filters.Query)
+	}
 	if count := len(m.selected); count > 0 {
 		help += fmt.Sprintf(" • selected=%d", count)
 	}
@@ -248,6 +382,26 @@ func (m *Model) toggleCurrentSelection() {
 	m.list.SetItem(idx, item)
 }
 
+// setItems rebuilds the list rows from the loaded entries, keeping the
+// selection of entries that are still shown.
+func (m *Model) setItems() {
+	m.processes = m.all
+	if m.treeView {
+		m.processes = app.TreeRoots(m.all)
+	}
+	newSelected := make(map[uint64]bool)
+	items := make([]list.Item, 0, len(m.processes))
+	for _, proc := range m.processes {
+		selected := m.selected[proc.ID]
+		if selected {
+			newSelected[proc.ID] = true
+		}
+		items = append(items, processItem{Process: proc, Selected: selected})
+	}
+	m.selected = newSelected
+	m.list.SetItems(items)
+}
+
 func (m *Model) clearSelection() {
 	m.selected = make(map[uint64]bool)
 	items := m.list.Items()
@@ -259,6 +413,79 @@ func (m *Model) clearSelection() {
 	}
 }
 
+// labelTargets returns the selected IDs, or the highlighted process if
+// nothing is selected.
+func (m *Model) labelTargets() []uint64 {
+	if len(m.selected) > 0 {
+		ids := make([]uint64, 0, len(m.selected))
+		for id := range m.selected {
+			ids = append(ids, id)
+		}
+		slices.Sort(ids)
+		return ids
+	}
+	if current := m.currentProcess(); current != nil {
+		return []uint64{current.ID}
+	}
+	return nil
+}
+
+// updateLabelEditor routes keys to the label prompt while it is open.
+func (m *Model) updateLabelEditor(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.editing = false
+		m.labelInput.Blur()
+		return nil
+	case tea.KeyEnter:
+		changes, err := app.ParseLabelEdits(m.labelInput.Value())
+		if err != nil {
+			m.notice = err.Error()
+			return nil
+		}
+		m.editing = false
+		m.labelInput.Blur()
+		m.err = nil
+		return updateLabelsCmd(m.controller, m.editTargets, changes)
+	}
+	var cmd tea.Cmd
+	m.labelInput, cmd = m.labelInput.Update(msg)
+	return cmd
+}
+
+// updateQueryEditor routes keys to the query prompt while it is open. The
+// query is parsed locally so mistakes are reported with their column before
+// the filter is applied.
+func (m *Model) updateQueryEditor(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.querying = false
+		m.notice = ""
+		m.queryInput.Blur()
+		return nil
+	case tea.KeyEnter:
+		raw := strings.TrimSpace(m.queryInput.Value())
+		if _, err := query.Parse(raw); err != nil {
+			var qerr *query.Error
+			if errors.As(err, &qerr) {
+				m.notice = fmt.Sprintf("Query error at column %d: %s", qerr.Column(), qerr.Msg)
+			} else {
+				m.notice = err.Error()
+			}
+			return nil
+		}
+		m.querying = false
+		m.notice = ""
+		m.queryInput.Blur()
+		m.filters.Query = raw
+		m.loading = true
+		return loadProcessesCmd(m.controller, m.filters)
+	}
+	var cmd tea.Cmd
+	m.queryInput, cmd = m.queryInput.Update(msg)
+	return cmd
+}
+
 func (m *Model) currentProcess() *app.Process {
 	if len(m.processes) == 0 {
 		return nil
@@ -277,6 +504,40 @@ func valueOrDash(s string) string {
 	return s
 }
 
+func formatMetrics(m *app.Metrics) string {
+	if m == nil {
+		return "cpu=- rss=- threads=- fds=-"
+	}
+	return fmt.Sprintf(
+		"cpu=%.1f%% rss=%s vms=%s threads=%d fds=%d\nio read=%s write=%s",
+		m.CPUPercent,
+		units.FormatBytes(m.RSS),
+		units.FormatBytes(m.VMS),
+		m.Threads,
+		m.FDs,
+		units.FormatBytes(m.ReadBytes),
+		units.FormatBytes(m.WriteBytes),
+	)
+}
+
+// formatTree draws the descendants of a process, truncated to maxTreeLines.
+func formatTree(nodes []app.ProcNode) string {
+	lines := app.TreeLines(nodes)
+	out := make([]string, 0, min(len(lines), maxTreeLines)+1)
+	for i, line := range lines {
+		if i == maxTreeLines {
+			out = append(out, fmt.Sprintf("… %d more", len(lines)-maxTreeLines))
+			break
+		}
+		row := fmt.Sprintf("%s%d %s", line.Prefix, line.Node.PID, line.Node.Cmd)
+		if line.Node.ID != 0 {
+			row += fmt.Sprintf(" [id=%d]", line.Node.ID)
+		}
+		out = append(out, row)
+	}
+	return strings.Join(out, "\n")
+}
+
 type processDelegate struct {
 	styles processItemStyles
 }
@@ -352,6 +613,9 @@ func (d processDelegate) Render(w io.Writer, m list.Model, index int, listItem l
 	title := fmt.Sprintf("%s  pid=%d  %s", valueOrDash(item.Process.Name), item.Process.PID, statusStyle.Render(statusText))
 	desc := fmt.Sprintf("cmd: %s", item.Process.Cmd)
 	meta := fmt.Sprintf("tags: [%s]  groups: [%s]", strings.Join(item.Process.Tags, ","), strings.Join(item.Process.Groups, ","))
+	if n := len(item.Process.Children); n > 0 {
+		meta += fmt.Sprintf("  children: %d", n)
+	}
 
 	fmt.Fprintf(w, "%s %s\n", indicatorRendered, titleStyle.Render(title))
 	fmt.Fprintf(w, "  %s\n", descStyle.Render(desc))
@@ -368,6 +632,21 @@ type processesLoadedMsg struct {
 
 type daemonStartedMsg struct{}
 
+type registryChangedMsg struct{}
+
+type watchEndedMsg struct{}
+
+type refreshTickMsg struct{}
+
+type labelsUpdatedMsg struct {
+	matched int
+	updated int
+}
+
+func refreshTickCmd() tea.Cmd {
+	return tea.Tick(metricsRefreshInterval, func(time.Time) tea.Msg { return refreshTickMsg{} })
+}
+
 type errMsg struct{ err error }
 
 func (e errMsg) Error() string { return e.err.Error() }
@@ -397,6 +676,52 @@ func loadProcessesCmd(ctrl Controller, filters app.ListFilters) tea.Cmd {
 	}
 }
 
+// watchRegistryCmd streams registry events into ch until the stream ends,
+// then reports watchEndedMsg. Bursts collapse into one pending notification.
+func watchRegistryCmd(ctrl Controller, filters app.ListFilters, ch chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		_ = ctrl.Watch(context.Background(), app.WatchParams{
+			Filters: filters,
+			Timeout: 4 * time.Second,
+		}, func(app.Event) error {
+			select {
+			case ch <- registryChangedMsg{}:
+			default:
+			}
+			return nil
+		})
+		ch <- watchEndedMsg{}
+		return nil
+	}
+}
+
+func waitForEventCmd(ch chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-ch
+	}
+}
+
+// updateLabelsCmd applies label edits; the resulting registry events refresh the list.
+func updateLabelsCmd(ctrl Controller, ids []uint64, changes app.LabelChanges) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+		defer cancel()
+		filters := app.ListFilters{IDs: make([]int, 0, len(ids))}
+		for _, id := range ids {
+			filters.IDs = append(filters.IDs, int(id))
+		}
+		res, err := ctrl.UpdateLabels(ctx, app.UpdateLabelsParams{
+			Filters: filters,
+			Changes: changes,
+			Timeout: 4 * time.Second,
+		})
+		if err != nil {
+			return errMsg{err}
+		}
+		return labelsUpdatedMsg{matched: len(res.Processes), updated: res.Updated}
+	}
+}
+
 func startDaemonCmd(ctrl Controller) tea.Cmd {
 	return func() tea.Msg {
 		if _, err := ctrl.StartDaemon(); err != nil {
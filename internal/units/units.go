@@ -0,0 +1,51 @@
+// Package units formats and parses quantities, so the CLI, the TUI and the
+// config files read and render them the same way.
+package units
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// FormatBytes renders a byte count with binary units (e.g. 12.3MiB).
+func FormatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := uint64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// ParseBytes reads a byte count such as 512, 64KB or 1.5GiB. Units are
+// binary, as in FormatBytes, and case-insensitive; a fraction of a byte is
+// dropped.
+func ParseBytes(raw string) (uint64, error) {
+	s := strings.ToUpper(strings.TrimSpace(raw))
+	mult := 1.0
+	for _, unit := range []struct {
+		suffix string
+		mult   float64
+	}{
+		{"KIB", 1 << 10}, {"MIB", 1 << 20}, {"GIB", 1 << 30}, {"TIB", 1 << 40},
+		{"KB", 1 << 10}, {"MB", 1 << 20}, {"GB", 1 << 30}, {"TB", 1 << 40},
+		{"K", 1 << 10}, {"M", 1 << 20}, {"G", 1 << 30}, {"T", 1 << 40}, {"B", 1},
+	} {
+		if trimmed, ok := strings.CutSuffix(s, unit.suffix); ok {
+			s, mult = strings.TrimSpace(trimmed), unit.mult
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	// Also rejects NaN, infinities and sizes beyond uint64.
+	if err != nil || !(n >= 0 && n*mult < math.Exp2(64)) {
+		return 0, fmt.Errorf("invalid size %q (expected e.g. 512KB, 500MB, 2GiB)", raw)
+	}
+	return uint64(n * mult), nil
+}
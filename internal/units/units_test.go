@@ -0,0 +1,42 @@
+package units
+
+import "testing"
+
+func TestFormatBytes(t *testing.T) {
+	for n, want := range map[uint64]string{
+		0:             "0B",
+		1023:          "1023B",
+		1024:          "1.0KiB",
+		1536:          "1.5KiB",
+		5 << 20:       "5.0MiB",
+		3 << 30:       "3.0GiB",
+		1<<60 + 1<<59: "1.5EiB",
+	} {
+		if got := FormatBytes(n); got != want {
+			t.Fatalf("FormatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	for raw, want := range map[string]uint64{
+		"0":        0,
+		"512":      512,
+		"64KB":     64 << 10,
+		" 2 gib ":  2 << 30,
+		"1.5GB":    3 << 29,
+		"1m":       1 << 20,
+		"10B":      10,
+		"1.5":      1,
+		"16777216": 16 << 20,
+	} {
+		if got, err := ParseBytes(raw); err != nil || got != want {
+			t.Fatalf("ParseBytes(%q) = %d, %v, want %d", raw, got, err, want)
+		}
+	}
+	for _, raw := range []string{"", "KB", "-1", "1XB", "inf", "NaN", "+Inf GB", "1e30", "16777216TiB"} {
+		if got, err := ParseBytes(raw); err == nil {
+			t.Fatalf("ParseBytes(%q) = %d, want an error", raw, got)
+		}
+	}
+}